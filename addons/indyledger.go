@@ -4,6 +4,7 @@ import (
 	"errors"
 
 	"github.com/findy-network/findy-wrapper-go/dto"
+	"github.com/findy-network/findy-wrapper-go/identifiers"
 	"github.com/findy-network/findy-wrapper-go/internal/c2go"
 	"github.com/findy-network/findy-wrapper-go/ledger"
 	"github.com/findy-network/findy-wrapper-go/plugin"
@@ -65,13 +66,19 @@ func (ao *Indy) Write(tx plugin.TxInfo, ID, data string) error {
 func (ao *Indy) Read(tx plugin.TxInfo, ID string) (name string, value string, err error) {
 	switch tx.TxType {
 	case plugin.TxTypeDID:
-		assert.That(false, "we don't support DID reading from ledger")
+		return ao.ReadDID(tx, ID)
 
 	case plugin.TxTypeSchema:
 		return ao.ReadSchema(tx, ID)
 
 	case plugin.TxTypeCredDef:
 		return ao.ReadCredDef(tx, ID)
+
+	case plugin.TxTypeRevRegDef:
+		return ao.ReadRevRegDef(tx, ID)
+
+	case plugin.TxTypeRevReg:
+		return ao.ReadRevReg(tx, ID)
 	}
 	return
 }
@@ -89,6 +96,7 @@ func (ao *Indy) ReadCredDef(
 	defer err2.Handle(&err)
 
 	glog.V(100).Infoln("submitter:", tx.SubmitterDID)
+	credDefID = identifiers.Unqualify(credDefID)
 
 	r := <-ledger.BuildGetCredDefRequest(tx.SubmitterDID, credDefID)
 	try.To(r.Err())
@@ -109,6 +117,50 @@ func (ao *Indy) ReadCredDef(
 	return credDefID, credDef, nil
 }
 
+func (ao *Indy) ReadRevRegDef(
+	tx plugin.TxInfo,
+	ID string,
+) (name string, value string, err error) {
+	defer err2.Handle(&err)
+
+	glog.V(100).Infoln("submitter:", tx.SubmitterDID)
+	ID = identifiers.Unqualify(ID)
+
+	r := <-ledger.BuildGetRevocRegDefRequest(tx.SubmitterDID, ID)
+	try.To(r.Err())
+
+	r = <-ledger.SubmitRequest(ao.handle, r.Str1())
+	try.To(r.Err())
+
+	r = <-ledger.ParseGetRevocRegDefResponse(r.Str1())
+	try.To(r.Err())
+
+	return r.Str1(), r.Str2(), nil
+}
+
+// ReadRevReg reads the state of the revocation registry. The ID is built with
+// plugin.RevRegStateID.
+func (ao *Indy) ReadRevReg(
+	tx plugin.TxInfo,
+	ID string,
+) (name string, value string, err error) {
+	defer err2.Handle(&err)
+
+	glog.V(100).Infoln("submitter:", tx.SubmitterDID)
+	revRegDefID, timestamp := try.To2(plugin.SplitRevRegStateID(ID))
+
+	r := <-ledger.BuildGetRevocRegRequest(tx.SubmitterDID, revRegDefID, timestamp)
+	try.To(r.Err())
+
+	r = <-ledger.SubmitRequest(ao.handle, r.Str1())
+	try.To(r.Err())
+
+	r = <-ledger.ParseGetRevocRegResponse(r.Str1())
+	try.To(r.Err())
+
+	return ID, r.Str2(), nil
+}
+
 func (ao *Indy) ReadSchema(
 	tx plugin.TxInfo,
 	ID string,
@@ -116,6 +168,7 @@ func (ao *Indy) ReadSchema(
 	defer err2.Handle(&err)
 
 	glog.V(100).Infoln("submitter:", tx.SubmitterDID)
+	ID = identifiers.Unqualify(ID)
 
 	r := <-ledger.BuildGetSchemaRequest(tx.SubmitterDID, ID)
 	try.To(r.Err())
@@ -134,6 +187,38 @@ func (ao *Indy) ReadSchema(
 	return readSchemaID, scJSON, nil
 }
 
+func (ao *Indy) ReadDID(
+	tx plugin.TxInfo,
+	ID string,
+) (name string, value string, err error) {
+	defer err2.Handle(&err)
+
+	glog.V(100).Infoln("submitter:", tx.SubmitterDID)
+	ID = identifiers.Unqualify(ID)
+
+	r := <-ledger.BuildGetNymRequest(tx.SubmitterDID, ID)
+	try.To(r.Err())
+
+	r = <-ledger.SubmitRequest(ao.handle, r.Str1())
+	try.To(r.Err())
+
+	var resp struct {
+		Result struct {
+			Data *string `json:"data"`
+		} `json:"result"`
+	}
+	dto.FromJSONStr(r.Str1(), &resp)
+	if resp.Result.Data == nil {
+		return ID, "", plugin.ErrNotExist
+	}
+	var nym struct {
+		Dest   string `json:"dest"`
+		VerKey string `json:"verkey"`
+	}
+	dto.FromJSONStr(*resp.Result.Data, &nym)
+	return ID, nym.VerKey, nil
+}
+
 func (ao *Indy) WriteDID(
 	tx plugin.TxInfo,
 	_ string,
@@ -5,6 +5,7 @@ import (
 	"strings"
 	"sync"
 
+	"github.com/findy-network/findy-wrapper-go/identifiers"
 	"github.com/findy-network/findy-wrapper-go/plugin"
 	"github.com/findy-network/findy-wrapper-go/pool"
 	"github.com/golang/glog"
@@ -52,12 +53,19 @@ func (m *Mem) Write(ti plugin.TxInfo, ID, data string) error {
 	//		return nil
 	//	}
 
+	ID, data = plugin.MapEntry(ti, ID, data)
+	ID = identifiers.Unqualify(ID)
+
 	m.Mem.Lock()
 	defer m.Mem.Unlock()
 	_, ok := m.Mem.Ory[ID]
-	if ok {
+	switch {
+	case ok:
 		//return err2.ErrAlreadyExist
 		glog.V(1).Infoln("update:", ti.TxType)
+	case ti.Update && !m.cacheMode:
+		// cache is filled lazily, other ledgers can update only what exists
+		return plugin.ErrNotExist
 	}
 
 	m.IncSeqNo()
@@ -66,7 +74,8 @@ func (m *Mem) Write(ti plugin.TxInfo, ID, data string) error {
 }
 
 // Read reads ID specific data from memory ledger. If data doesn't exist
-// plugin.ErrNotExist error value is returned.
+// plugin.ErrNotExist error value is returned. Qualified IDs are read by their
+// unqualified forms.
 func (m *Mem) Read(
 	tx plugin.TxInfo,
 	ID string,
@@ -75,6 +84,8 @@ func (m *Mem) Read(
 	value string,
 	err error,
 ) {
+	ID = identifiers.Unqualify(ID)
+
 	m.Mem.Lock()
 	defer m.Mem.Unlock()
 
@@ -50,3 +50,67 @@ func TestMemLedger_Read(t *testing.T) {
 		assert.Equal("testData", value)
 	}
 }
+
+func TestMemLedger_Update(t *testing.T) {
+	defer assert.PushTester(t)()
+
+	m := new(Mem)
+	assert.That(m.Open(""))
+	tx := plugin.TxCredDef
+	tx.Update = true
+	err := m.Write(tx, "credDefID", "credDef")
+	assert.Error(err)
+	assert.Equal(err, plugin.ErrNotExist)
+
+	assert.NoError(m.Write(plugin.TxCredDef, "credDefID", "credDef"))
+	assert.NoError(m.Write(tx, "credDefID", "rotatedCredDef"))
+	_, value, err := m.Read(plugin.TxCredDef, "credDefID")
+	assert.NoError(err)
+	assert.Equal(value, "rotatedCredDef")
+
+	cache := new(Mem)
+	assert.That(cache.Open("cache"))
+	assert.NoError(cache.Write(tx, "credDefID", "credDef"), "cache is filled lazily")
+}
+
+func TestMemLedger_qualifiedIDs(t *testing.T) {
+	defer assert.PushTester(t)()
+
+	const schemaID = "5Lgx4KLRTNgexDqT7WDALu:2:Education:1.0"
+	m := new(Mem)
+	assert.That(m.Open(""))
+	assert.NoError(m.Write(plugin.TxSchema, "schema:sov:did:sov:"+schemaID, "schema"))
+	for _, id := range []string{
+		schemaID,
+		"schema:sov:did:sov:" + schemaID,
+		"did:indy:sovrin:5Lgx4KLRTNgexDqT7WDALu/anoncreds/v0/SCHEMA/Education/1.0",
+	} {
+		name, value, err := m.Read(plugin.TxSchema, id)
+		assert.NoError(err)
+		assert.Equal(name, schemaID)
+		assert.Equal(value, "schema")
+	}
+}
+
+func TestMemLedger_mapDID(t *testing.T) {
+	defer assert.PushTester(t)()
+
+	const (
+		submitter = "5Lgx4KLRTNgexDqT7WDALu"
+		target    = "Th7MpTaRZVRYnPiabds81Y"
+		verkey    = "~7TYfekw4GUagBnBVCqPjiC"
+	)
+	m := new(Mem)
+	assert.That(m.Open(""))
+	tx := plugin.TxInfo{TxType: plugin.TxTypeDID, VerKey: verkey}
+	assert.NoError(m.Write(tx, submitter, target))
+	name, value, err := m.Read(plugin.TxDID, target)
+	assert.NoError(err)
+	assert.Equal(name, target)
+	assert.Equal(value, verkey)
+
+	assert.NoError(m.Write(plugin.TxDID, "testID", "testData"))
+	_, value, err = m.Read(plugin.TxDID, "testID")
+	assert.NoError(err)
+	assert.Equal(value, "testData", "without verkey the entry is kept as is")
+}
@@ -10,6 +10,7 @@ package anoncreds
 import "C"
 import (
 	"crypto/sha256"
+	"encoding/json"
 	"math"
 	"math/big"
 
@@ -122,20 +123,22 @@ type Proof struct {
 	Identifiers    []IdentifiersObj       `json:"identifiers"`
 }
 
-// IdentifiersObj is wrapper struct for libindy's corresponding JSON type.
+// IdentifiersObj is wrapper struct for libindy's corresponding JSON type. The
+// Timestamp is a JSON number in libindy's proofs.
 type IdentifiersObj struct {
-	SchemaID  string `json:"schema_id"`
-	CredDefID string `json:"cred_def_id"`
-	RevRegID  string `json:"rev_reg_id,omitempty"`
-	Timestamp string `json:"timestamp,omitempty"`
+	SchemaID  string      `json:"schema_id"`
+	CredDefID string      `json:"cred_def_id"`
+	RevRegID  string      `json:"rev_reg_id,omitempty"`
+	Timestamp json.Number `json:"timestamp,omitempty"`
 }
 
 // RequestedProof is wrapper struct for libindy's corresponding JSON type.
 type RequestedProof struct {
-	RevealedAttrs     map[string]RevealedAttr `json:"revealed_attrs"`
-	UnrevealedAttrs   map[string]interface{}  `json:"unrevealed_attrs"`
-	SelfAttestedAttrs map[string]interface{}  `json:"self_attested_attrs"`
-	Predicates        map[string]interface{}  `json:"predicates"`
+	RevealedAttrs      map[string]RevealedAttr      `json:"revealed_attrs"`
+	RevealedAttrGroups map[string]RevealedAttrGroup `json:"revealed_attr_groups,omitempty"`
+	UnrevealedAttrs    map[string]interface{}       `json:"unrevealed_attrs"`
+	SelfAttestedAttrs  map[string]interface{}       `json:"self_attested_attrs"`
+	Predicates         map[string]interface{}       `json:"predicates"`
 }
 
 // RevealedAttr is wrapper struct for libindy's corresponding JSON type.
@@ -145,6 +148,13 @@ type RevealedAttr struct {
 	Encoded       string `json:"encoded"`
 }
 
+// RevealedAttrGroup is wrapper struct for libindy's corresponding JSON type.
+// It's used for the attributes requested with AttrInfo.Names.
+type RevealedAttrGroup struct {
+	SubProofIndex int                    `json:"sub_proof_index"`
+	Values        map[string]CredDefAttr `json:"values"`
+}
+
 // RequestedCredentials is wrapper struct for libindy's corresponding JSON type.
 type RequestedCredentials struct {
 	SelfAttestedAttributes map[string]string              `json:"self_attested_attributes"`
@@ -193,11 +203,11 @@ type ProofRequest struct {
 
 // PredicateInfo is wrapper struct for libindy's corresponding JSON type.
 type PredicateInfo struct {
-	Name         string           `json:"name"`
-	PType        string           `json:"p_type"`
-	PValue       int              `json:"p_value"`
-	Restrictions []Filter         `json:"restrictions,omitempty"`
-	NonRevoked   NonRevocInterval `json:"non_revoked,omitempty"`
+	Name         string            `json:"name"`
+	PType        string            `json:"p_type"`
+	PValue       int               `json:"p_value"`
+	Restrictions []Filter          `json:"restrictions,omitempty"`
+	NonRevoked   *NonRevocInterval `json:"non_revoked,omitempty"`
 }
 
 // AttrInfo is wrapper struct for libindy's corresponding JSON type.
@@ -205,19 +215,29 @@ type AttrInfo struct {
 	// attribute names, (case insensitive and ignore spaces)
 	// indy-sdk NOTE: should either be "name" or "names", not both and not none of them.
 	// Use "names" to specify several attributes that have to match a single credential.
-	Name         string            `json:"name"`
+	Name         string            `json:"name,omitempty"`
 	Names        []string          `json:"names,omitempty"`
 	Restrictions []Filter          `json:"restrictions,omitempty"`
 	NonRevoked   *NonRevocInterval `json:"non_revoked,omitempty"`
 }
 
-// Filter is wrapper struct for libindy's corresponding JSON type.
+// Filter is wrapper struct for libindy's corresponding JSON type. In proof
+// request restrictions it can also restrict attribute values and require
+// attributes, which are serialized as attr::<name>::value and
+// attr::<name>::marker tags.
 type Filter struct {
 	SchemaID        string `json:"schema_id,omitempty"`
 	SchemaIssuerDID string `json:"schema_issuer_did,omitempty"`
 	SchemaName      string `json:"schema_name,omitempty"`
+	SchemaVersion   string `json:"schema_version,omitempty"`
 	IssuerDID       string `json:"issuer_did,omitempty"`
 	CredDefID       string `json:"cred_def_id,omitempty"`
+
+	// AttrValues are required raw values of the attributes by their names.
+	AttrValues map[string]string `json:"-"`
+
+	// AttrMarkers are names of the attributes the credential must have.
+	AttrMarkers []string `json:"-"`
 }
 
 // NonRevocInterval is wrapper struct for libindy's corresponding JSON type.
@@ -326,6 +346,22 @@ func IssuerCreateAndStoreCredentialDef(wallet int, did, schema, tag, sigType, co
 	return c2go.FindyIssuerCreateAndStoreCredentialDef(wallet, did, schema, tag, sigType, config)
 }
 
+// IssuerRotateCredentialDefStart generates temporary keys for the existing
+// credential definition in the wallet and returns the new cred def JSON. The
+// temporary cred def must be written to the ledger before it's applied with
+// IssuerRotateCredentialDefApply. The config is optional, see
+// IssuerCreateAndStoreCredentialDef. See RotateCredDef for the whole flow.
+func IssuerRotateCredentialDefStart(wallet int, credDefID, config string) ctx.Channel {
+	return c2go.FindyIssuerRotateCredentialDefStart(wallet, credDefID, config)
+}
+
+// IssuerRotateCredentialDefApply replaces the keys of the credential
+// definition in the wallet with the temporary keys generated by
+// IssuerRotateCredentialDefStart.
+func IssuerRotateCredentialDefApply(wallet int, credDefID string) ctx.Channel {
+	return c2go.FindyIssuerRotateCredentialDefApply(wallet, credDefID)
+}
+
 // IssuerCreateCredentialOffer creates on credential offer for part of indy
 // specific issuing protocol. It's called before an issuer send its result as on
 // offer credential.
@@ -390,8 +426,28 @@ func VerifierVerifyProof(proofReqJSON, proofJSON, schemasJSON, credDefsJSON, rev
 	return c2go.FindyVerifierVerifyProof(proofReqJSON, proofJSON, schemasJSON, credDefsJSON, revRegDefsJSON, revRegsJSON)
 }
 
+// ProverSearchCredentials searches for credentials by the WQL query, see the
+// wql package. It returns the search handle and the count of the found
+// credentials (Uint64), and the credentials are fetched in batches with
+// ProverFetchCredentials.
+func ProverSearchCredentials(wallet int, queryJSON string) ctx.Channel {
+	return c2go.FindyProverSearchCredentials(wallet, queryJSON)
+}
+
+// ProverFetchCredentials fetches the next count credentials of the search. It
+// returns the JSON array of CredentialInfo.
+func ProverFetchCredentials(searchHandle, count int) ctx.Channel {
+	return c2go.FindyProverFetchCredentials(searchHandle, count)
+}
+
+// ProverCloseCredentialsSearch closes the search identified by search handle.
+func ProverCloseCredentialsSearch(searchHandle int) ctx.Channel {
+	return c2go.FindyProverCloseCredentialsSearch(searchHandle)
+}
+
 // ProverSearchCredentialsForProofReq searches for credentials matching the
-// given proof request. Instead of immediately returning of fetched credentials
+// given proof request. The optional extraQueryJSON has WQL queries by the
+// referents, see ExtraQuery. Instead of immediately returning of fetched credentials
 // this call returns searchHandle that can be used later to fetch records by
 // small batches with ProverFetchCredentialsForProofReq.
 func ProverSearchCredentialsForProofReq(wallet int, proofReqJSON, extraQueryJSON string) ctx.Channel {
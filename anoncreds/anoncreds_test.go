@@ -14,6 +14,7 @@ import (
 	"github.com/findy-network/findy-wrapper-go/did"
 	"github.com/findy-network/findy-wrapper-go/helpers"
 	"github.com/findy-network/findy-wrapper-go/ledger"
+	"github.com/findy-network/findy-wrapper-go/wql"
 )
 
 const ledgerWaitTimer = 1 * time.Second
@@ -189,8 +190,8 @@ func TestIssuerCreateSchema(t *testing.T) {
 				RequestedPredicates: map[string]PredicateInfo{},
 			}
 			pReqStr := dto.ToJSON(pReq)
-			wql := fmt.Sprintf(`"attr::email::value": "%s"`, emailToVerify)
-			wqlJSONStr := fmt.Sprintf(`{"attr1_referent": {%s}}`, wql)
+			emailQuery := fmt.Sprintf(`"attr::email::value": "%s"`, emailToVerify)
+			wqlJSONStr := fmt.Sprintf(`{"attr1_referent": {%s}}`, emailQuery)
 			glog.V(2).Infoln("---------")
 			glog.V(2).Infoln("--", wqlJSONStr)
 			glog.V(2).Infoln("---------")
@@ -209,6 +210,20 @@ func TestIssuerCreateSchema(t *testing.T) {
 			glog.V(2).Infoln("--> len: ", len(credInfo))
 			r = <-ProverCloseCredentialsSearchForProofReq(searchHandle)
 			try.To(r.Err())
+
+			// the same search without the proof request
+			r = <-ProverSearchCredentials(w2, wql.AttrValue("email", emailToVerify).String())
+			try.To(r.Err())
+			searchHandle = r.Handle()
+			assert.Equal(r.Uint64(), uint64(1))
+			r = <-ProverFetchCredentials(searchHandle, fetchMax)
+			try.To(r.Err())
+			found := make([]CredentialInfo, 0, fetchMax)
+			dto.FromJSONStr(r.Str1(), &found)
+			assert.SLen(found, 1)
+			assert.Equal(found[0].Referent, credInfo[0].CredInfo.Referent)
+			r = <-ProverCloseCredentialsSearch(searchHandle)
+			try.To(r.Err())
 			schemaObject := map[string]interface{}{}
 			dto.FromJSONStr(scJSON, &schemaObject)
 			schemas := map[string]map[string]interface{}{
@@ -245,6 +260,30 @@ func TestIssuerCreateSchema(t *testing.T) {
 			dto.FromJSONStr(proofJS, &proof)
 			assert.That(r.Yes())
 			assert.Equal(proof.RequestedProof.RevealedAttrs["attr1_referent"].Raw, emailToVerify)
+
+			// the same requested credentials with the auto prover and the
+			// WQL query we searched with above
+			ap, err := AutoProve(pool, w2, w2DID, pReq, SelectionPolicy{
+				Query: wql.AttrValue("email", emailToVerify),
+			})
+			assert.NoError(err)
+			assert.Equal(ap.RequestedCredentials.RequestedAttributes["attr1_referent"].CredID,
+				credInfo[0].CredInfo.Referent)
+			assert.MLen(ap.Schemas, 1)
+			assert.MLen(ap.CredDefs, 1)
+			r = <-ProverCreateProof(w2, pReqStr, ap.RequestedCredentialsJSON(), msid,
+				ap.SchemasJSON(), ap.CredDefsJSON(), "{}")
+			try.To(r.Err())
+			r = <-VerifierVerifyProof(pReqStr, r.Str1(), ap.SchemasJSON(), ap.CredDefsJSON(), "{}", "{}")
+			try.To(r.Err())
+			assert.That(r.Yes())
+
+			report, err := VerifyProofWith(pool, stewardDID, pReq, proofJS,
+				VerifyOptions{Encoder: EncoderLegacy})
+			assert.NoError(err)
+			assert.That(report.Verified, report.Failures)
+			assert.Equal(report.Revealed["attr1_referent"]["email"].Raw, emailToVerify)
+			assert.Equal(report.Revealed["attr1_referent"]["email"].CredDefID, cdid)
 		})
 	}
 	helpers.CloseAndDeleteTestWallet(w2, name2, t)
@@ -0,0 +1,254 @@
+package anoncreds
+
+import (
+	"encoding/json"
+	"sort"
+	"strings"
+
+	"github.com/findy-network/findy-wrapper-go"
+	"github.com/findy-network/findy-wrapper-go/dto"
+	"github.com/findy-network/findy-wrapper-go/wql"
+	"github.com/lainio/err2"
+	"github.com/lainio/err2/try"
+)
+
+// fetchBatchSize is the count of credentials fetched per one call of
+// ProverFetchCredentialsForProofReq.
+const fetchBatchSize = 100
+
+// SelectionPolicy tells AutoProve how to select one credential for each
+// referent of the proof request when the wallet has several candidates.
+type SelectionPolicy struct {
+	// IssuerDID accepts only credentials issued by this DID. It's given to the
+	// wallet search as an extra query, empty means any issuer.
+	IssuerDID string
+
+	// Query is an extra WQL query for the wallet search of every referent.
+	// It's combined with IssuerDID.
+	Query wql.Query
+
+	// Less orders the candidates of the referent, and the first one is
+	// selected. The order of the wallet search isn't specified by libindy, so
+	// without Less the selection is arbitrary. Less can compare e.g. the
+	// credential attributes like an issuing date.
+	Less func(a, b CredentialInfo) bool
+
+	// PreferNonRevoked ranks non-revoked credentials before the others. See
+	// IsRevoked.
+	PreferNonRevoked bool
+
+	// IsRevoked reports if the credential is revoked. If it's nil,
+	// credentials without a revocation registry are preferred, because they
+	// are the only ones we know cannot be revoked.
+	IsRevoked func(info CredentialInfo) bool
+
+	// SelfAttested has values for the attribute referents which can be self
+	// attested, i.e. they have no restrictions. They are used only when the
+	// wallet doesn't have a credential for the referent.
+	SelfAttested map[string]string
+}
+
+// AutoProof is the result of AutoProve. It has everything needed for
+// ProverCreateProof except the master secret and the revocation states.
+type AutoProof struct {
+	RequestedCredentials RequestedCredentials
+
+	// LedgerObjects has the schemas and cred defs of the selected
+	// credentials.
+	LedgerObjects
+
+	// Unsatisfied lists the referents which cannot be satisfied in sorted
+	// order.
+	Unsatisfied []string
+}
+
+// UnsatisfiedError is returned by AutoProve when the wallet doesn't have
+// credentials for all of the referents of the proof request.
+type UnsatisfiedError struct {
+	Referents []string
+}
+
+func (e *UnsatisfiedError) Error() string {
+	return "cannot satisfy referents: " + strings.Join(e.Referents, ", ")
+}
+
+// RequestedCredentialsJSON returns the requested credentials JSON for
+// ProverCreateProof.
+func (ap *AutoProof) RequestedCredentialsJSON() string {
+	return dto.ToJSON(ap.RequestedCredentials)
+}
+
+/*
+AutoProve builds requested credentials for the proof request from the wallet's
+credentials according the selection policy. It searches credentials for every
+referent, selects one of them, and reads the schemas and cred defs of the
+selected credentials from the ledger with the submitter DID.
+
+All of the attributes are revealed. Timestamps are not set, i.e. the caller
+must add them and the revocation states if non-revocation is requested.
+
+If some of the referents cannot be satisfied, the partial result is returned
+with *UnsatisfiedError, which lists the referents.
+
+	ap, err := anoncreds.AutoProve(pool, w, myDID, proofReq, anoncreds.SelectionPolicy{PreferNonRevoked: true})
+	r := <-anoncreds.ProverCreateProof(w, dto.ToJSON(proofReq), ap.RequestedCredentialsJSON(),
+		msid, ap.SchemasJSON(), ap.CredDefsJSON(), "{}")
+*/
+func AutoProve(
+	pool, wallet int,
+	submitter string,
+	proofReq ProofRequest,
+	policy SelectionPolicy,
+) (ap *AutoProof, err error) {
+	defer err2.Handle(&err, "auto prove")
+
+	ap = &AutoProof{
+		RequestedCredentials: RequestedCredentials{
+			SelfAttestedAttributes: map[string]string{},
+			RequestedAttributes:    map[string]RequestedAttrObject{},
+			RequestedPredicates:    map[string]RequestedPredObject{},
+		},
+		LedgerObjects: NewLedgerObjects(),
+		Unsatisfied:   make([]string, 0),
+	}
+	selected := make([]CredentialInfo, 0,
+		len(proofReq.RequestedAttributes)+len(proofReq.RequestedPredicates))
+
+	candidates := try.To1(searchCredentials(wallet, proofReq, policy))
+
+	for ref, attrInfo := range proofReq.RequestedAttributes {
+		info, found := policy.selectFrom(candidates[ref])
+		switch {
+		case found:
+			ap.RequestedCredentials.RequestedAttributes[ref] = RequestedAttrObject{
+				CredID:   info.Referent,
+				Revealed: true,
+			}
+			selected = append(selected, info)
+		case len(attrInfo.Restrictions) == 0 && hasKey(policy.SelfAttested, ref):
+			ap.RequestedCredentials.SelfAttestedAttributes[ref] = policy.SelfAttested[ref]
+		default:
+			ap.Unsatisfied = append(ap.Unsatisfied, ref)
+		}
+	}
+	for ref := range proofReq.RequestedPredicates {
+		info, found := policy.selectFrom(candidates[ref])
+		if !found {
+			ap.Unsatisfied = append(ap.Unsatisfied, ref)
+			continue
+		}
+		ap.RequestedCredentials.RequestedPredicates[ref] = RequestedPredObject{
+			CredID: info.Referent,
+		}
+		selected = append(selected, info)
+	}
+
+	for _, info := range selected {
+		try.To(ap.Read(pool, submitter, info.SchemaID, info.CredDefID))
+	}
+
+	if len(ap.Unsatisfied) > 0 {
+		sort.Strings(ap.Unsatisfied)
+		return ap, &UnsatisfiedError{Referents: ap.Unsatisfied}
+	}
+	return ap, nil
+}
+
+// searchCredentials returns all of the wallet's credentials matching the proof
+// request by the referents.
+func searchCredentials(
+	wallet int,
+	proofReq ProofRequest,
+	policy SelectionPolicy,
+) (creds map[string][]Credentials, err error) {
+	defer err2.Handle(&err)
+
+	referents := make([]string, 0,
+		len(proofReq.RequestedAttributes)+len(proofReq.RequestedPredicates))
+	for ref := range proofReq.RequestedAttributes {
+		referents = append(referents, ref)
+	}
+	for ref := range proofReq.RequestedPredicates {
+		referents = append(referents, ref)
+	}
+
+	r := <-ProverSearchCredentialsForProofReq(wallet, dto.ToJSON(proofReq),
+		policy.extraQuery(referents))
+	try.To(r.Err())
+	searchHandle := r.Handle()
+	defer func() {
+		r := <-ProverCloseCredentialsSearchForProofReq(searchHandle)
+		if err == nil {
+			err = r.Err()
+		}
+	}()
+
+	creds = make(map[string][]Credentials, len(referents))
+	for _, ref := range referents {
+		for {
+			r = <-ProverFetchCredentialsForProofReq(searchHandle, ref, fetchBatchSize)
+			try.To(r.Err())
+			batch := make([]Credentials, 0, fetchBatchSize)
+			try.To(json.Unmarshal([]byte(r.Str1()), &batch))
+			creds[ref] = append(creds[ref], batch...)
+			if len(batch) < fetchBatchSize {
+				break
+			}
+		}
+	}
+	return creds, nil
+}
+
+// extraQuery returns the extra query JSON for
+// ProverSearchCredentialsForProofReq, or findy.NullString if the policy
+// doesn't need one.
+func (p SelectionPolicy) extraQuery(referents []string) string {
+	query := p.Query
+	switch {
+	case p.IssuerDID != "" && query.IsZero():
+		query = Filter{IssuerDID: p.IssuerDID}.Query()
+	case p.IssuerDID != "":
+		query = wql.And(Filter{IssuerDID: p.IssuerDID}.Query(), query)
+	case query.IsZero():
+		return findy.NullString
+	}
+	queries := make(map[string]wql.Query, len(referents))
+	for _, ref := range referents {
+		queries[ref] = query
+	}
+	return ExtraQuery(queries)
+}
+
+// selectFrom selects one credential from the candidates ordered by Less.
+func (p SelectionPolicy) selectFrom(candidates []Credentials) (info CredentialInfo, found bool) {
+	if len(candidates) == 0 {
+		return info, false
+	}
+	infos := make([]CredentialInfo, len(candidates))
+	for i, c := range candidates {
+		infos[i] = c.CredInfo
+	}
+	if p.Less != nil {
+		sort.SliceStable(infos, func(i, j int) bool {
+			return p.Less(infos[i], infos[j])
+		})
+	}
+	if p.PreferNonRevoked {
+		sort.SliceStable(infos, func(i, j int) bool {
+			return !p.revoked(infos[i]) && p.revoked(infos[j])
+		})
+	}
+	return infos[0], true
+}
+
+func (p SelectionPolicy) revoked(info CredentialInfo) bool {
+	if p.IsRevoked != nil {
+		return p.IsRevoked(info)
+	}
+	return info.RevRegID != ""
+}
+
+func hasKey(m map[string]string, key string) bool {
+	_, ok := m[key]
+	return ok
+}
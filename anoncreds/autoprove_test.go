@@ -0,0 +1,105 @@
+package anoncreds
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/findy-network/findy-wrapper-go"
+	"github.com/findy-network/findy-wrapper-go/dto"
+	"github.com/findy-network/findy-wrapper-go/wql"
+	"github.com/lainio/err2/assert"
+)
+
+func candidatesOf(infos ...CredentialInfo) []Credentials {
+	creds := make([]Credentials, len(infos))
+	for i, info := range infos {
+		creds[i] = Credentials{CredInfo: info}
+	}
+	return creds
+}
+
+func TestSelectionPolicy_selectFrom(t *testing.T) {
+	assert.PushTester(t)
+	defer assert.PopTester()
+
+	oldRevocable := CredentialInfo{Referent: "old-revocable", RevRegID: "rr1",
+		Attrs: map[string]string{"issued": "2020-01-01"}}
+	old := CredentialInfo{Referent: "old",
+		Attrs: map[string]string{"issued": "2021-01-01"}}
+	newRevocable := CredentialInfo{Referent: "new-revocable", RevRegID: "rr2",
+		Attrs: map[string]string{"issued": "2022-01-01"}}
+	candidates := candidatesOf(old, newRevocable, oldRevocable)
+	newest := func(a, b CredentialInfo) bool {
+		return a.Attrs["issued"] > b.Attrs["issued"]
+	}
+
+	tests := []struct {
+		name   string
+		policy SelectionPolicy
+		creds  []Credentials
+		want   string
+		found  bool
+	}{
+		{"empty", SelectionPolicy{}, nil, "", false},
+		{"search order", SelectionPolicy{}, candidates, "old", true},
+		{"newest", SelectionPolicy{Less: newest}, candidates, "new-revocable", true},
+		{"non revocable", SelectionPolicy{PreferNonRevoked: true}, candidates, "old", true},
+		{"newest non revoked", SelectionPolicy{
+			Less:             newest,
+			PreferNonRevoked: true,
+			IsRevoked: func(info CredentialInfo) bool {
+				return info.Referent == "new-revocable"
+			},
+		}, candidates, "old", true},
+		{"all revoked newest", SelectionPolicy{
+			Less:             newest,
+			PreferNonRevoked: true,
+			IsRevoked:        func(CredentialInfo) bool { return true },
+		}, candidates, "new-revocable", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			defer assert.PushTester(t)()
+			info, found := tt.policy.selectFrom(tt.creds)
+			assert.Equal(found, tt.found)
+			assert.Equal(info.Referent, tt.want)
+		})
+	}
+}
+
+func TestSelectionPolicy_extraQuery(t *testing.T) {
+	assert.PushTester(t)
+	defer assert.PopTester()
+
+	assert.Equal(SelectionPolicy{}.extraQuery([]string{"ref1"}), findy.NullString)
+
+	query := map[string]Filter{}
+	dto.FromJSONStr(SelectionPolicy{IssuerDID: "5Lgx4KLRTNgexDqT7WDALu"}.
+		extraQuery([]string{"ref1", "pred1"}), &query)
+	assert.MLen(query, 2)
+	assert.Equal(query["ref1"].IssuerDID, "5Lgx4KLRTNgexDqT7WDALu")
+	assert.Equal(query["pred1"].IssuerDID, "5Lgx4KLRTNgexDqT7WDALu")
+
+	queries := map[string]wql.Query{}
+	dto.FromJSONStr(SelectionPolicy{Query: wql.Eq("schema_name", "Education")}.
+		extraQuery([]string{"ref1"}), &queries)
+	assert.Equal(queries["ref1"].String(), `{"schema_name":"Education"}`)
+
+	dto.FromJSONStr(SelectionPolicy{
+		IssuerDID: "5Lgx4KLRTNgexDqT7WDALu",
+		Query:     wql.Eq("schema_name", "Education"),
+	}.extraQuery([]string{"ref1"}), &queries)
+	assert.Equal(queries["ref1"].String(),
+		`{"$and":[{"issuer_did":"5Lgx4KLRTNgexDqT7WDALu"},{"schema_name":"Education"}]}`)
+}
+
+func TestUnsatisfiedError(t *testing.T) {
+	assert.PushTester(t)
+	defer assert.PopTester()
+
+	var err error = &UnsatisfiedError{Referents: []string{"attr1", "pred1"}}
+	var unsatisfied *UnsatisfiedError
+	assert.That(errors.As(err, &unsatisfied))
+	assert.SLen(unsatisfied.Referents, 2)
+	assert.Equal(err.Error(), "cannot satisfy referents: attr1, pred1")
+}
@@ -0,0 +1,230 @@
+package anoncreds
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DateFormat is the format of time.Time values in credentials. It's the
+// dateint of Aries RFC 0441, which can be used in predicates, e.g. birth date
+// <= 20000101.
+const DateFormat = "20060102"
+
+/*
+CredValuesBuilder builds the credential values JSON for IssuerCreateCredential
+from Go values. The attribute names are validated against the schema, and the
+values are encoded with the default encoder if other isn't set with Encoding.
+The errors are collected during building and Build returns them all.
+
+Supported values are strings, booleans, integers, and time.Time, which is
+formatted with DateFormat. Types based on them are supported as well.
+
+	values, err := anoncreds.NewCredValuesBuilder("email", "age").
+		Set("email", "alice@example.com").
+		Set("age", 42).
+		BuildJSON()
+*/
+type CredValuesBuilder struct {
+	attrNames map[string]string // normalized name -> schema name
+	values    map[string]CredDefAttr
+	encoding  string
+	errs      []error
+}
+
+// NewCredValuesBuilder creates a new builder for the schema's attribute names.
+func NewCredValuesBuilder(attrNames ...string) *CredValuesBuilder {
+	b := &CredValuesBuilder{
+		attrNames: make(map[string]string, len(attrNames)),
+		values:    make(map[string]CredDefAttr, len(attrNames)),
+	}
+	for _, name := range attrNames {
+		b.attrNames[normalizeAttrName(name)] = name
+	}
+	return b
+}
+
+// NewCredValuesBuilderFromSchema creates a new builder for the attribute names
+// of the schema JSON, e.g. the one returned by IssuerCreateSchema.
+func NewCredValuesBuilderFromSchema(schemaJSON string) (*CredValuesBuilder, error) {
+	var schema struct {
+		AttrNames []string `json:"attrNames"`
+	}
+	if err := json.Unmarshal([]byte(schemaJSON), &schema); err != nil {
+		return nil, fmt.Errorf("cred values: schema: %w", err)
+	}
+	if len(schema.AttrNames) == 0 {
+		return nil, errors.New("cred values: schema has no attrNames")
+	}
+	return NewCredValuesBuilder(schema.AttrNames...), nil
+}
+
+// Encoding sets the registered encoder used for the values of this builder
+// instead of the default encoder.
+func (b *CredValuesBuilder) Encoding(name string) *CredValuesBuilder {
+	if _, ok := LookupEncoder(name); !ok {
+		b.errs = append(b.errs, fmt.Errorf("encoder %q is not registered", name))
+		return b
+	}
+	b.encoding = name
+	return b
+}
+
+// Set sets the value of the attribute.
+func (b *CredValuesBuilder) Set(name string, value any) *CredValuesBuilder {
+	schemaName, ok := b.attrNames[normalizeAttrName(name)]
+	if !ok {
+		b.errs = append(b.errs, fmt.Errorf("%s: not in schema", name))
+		return b
+	}
+	raw, err := rawValue(value)
+	if err != nil {
+		b.errs = append(b.errs, fmt.Errorf("%s: %w", name, err))
+		return b
+	}
+	b.values[schemaName] = CredDefAttr{Raw: raw}
+	return b
+}
+
+// SetMap sets the values of the attributes from the map.
+func (b *CredValuesBuilder) SetMap(values map[string]any) *CredValuesBuilder {
+	for name, value := range values {
+		b.Set(name, value)
+	}
+	return b
+}
+
+/*
+SetStruct sets the values of the attributes from the exported fields of the
+struct or a pointer to it. The attribute name is taken from the cred tag, the
+json tag, or the field name in that order. Fields tagged "-" are skipped, and
+so are zero valued fields tagged omitempty.
+
+	type Person struct {
+		Name      string    `cred:"name"`
+		BirthDate time.Time `cred:"birthdate"`
+		Nickname  string    `cred:"nickname,omitempty"`
+	}
+*/
+func (b *CredValuesBuilder) SetStruct(v any) *CredValuesBuilder {
+	rv := reflect.ValueOf(v)
+	for rv.Kind() == reflect.Pointer && !rv.IsNil() {
+		rv = rv.Elem()
+	}
+	if rv.Kind() != reflect.Struct {
+		b.errs = append(b.errs, fmt.Errorf("SetStruct: %T is not a struct", v))
+		return b
+	}
+	rt := rv.Type()
+	for i := 0; i < rt.NumField(); i++ {
+		field := rt.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		name, omitEmpty := fieldAttrName(field)
+		if name == "-" || (omitEmpty && rv.Field(i).IsZero()) {
+			continue
+		}
+		b.Set(name, rv.Field(i).Interface())
+	}
+	return b
+}
+
+// Build encodes the values and returns them by the attribute names, or all of
+// the errors found. All of the schema's attributes must have a value.
+func (b *CredValuesBuilder) Build() (values map[string]CredDefAttr, err error) {
+	errs := append([]error{}, b.errs...)
+	for _, name := range b.attrNames {
+		if _, ok := b.values[name]; !ok {
+			errs = append(errs, fmt.Errorf("%s: value is missing", name))
+		}
+	}
+	if len(errs) > 0 {
+		sort.Slice(errs, func(i, j int) bool {
+			return errs[i].Error() < errs[j].Error()
+		})
+		return nil, errors.Join(errs...)
+	}
+
+	encoder := Encode
+	if b.encoding != "" {
+		encoder, _ = LookupEncoder(b.encoding)
+	}
+	values = make(map[string]CredDefAttr, len(b.values))
+	for name, value := range b.values {
+		values[name] = CredDefAttr{Raw: value.Raw, Encoded: encoder(value.Raw)}
+	}
+	return values, nil
+}
+
+// BuildJSON is like Build but returns the credential values JSON for
+// IssuerCreateCredential.
+func (b *CredValuesBuilder) BuildJSON() (string, error) {
+	values, err := b.Build()
+	if err != nil {
+		return "", err
+	}
+	data, err := json.Marshal(values)
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
+// rawValue converts the Go value to the raw string value of the attribute.
+func rawValue(value any) (string, error) {
+	switch v := value.(type) {
+	case time.Time:
+		return v.Format(DateFormat), nil
+	case *time.Time:
+		if v != nil {
+			return v.Format(DateFormat), nil
+		}
+	case nil:
+	default:
+		rv := reflect.ValueOf(v)
+		switch rv.Kind() {
+		case reflect.String:
+			return rv.String(), nil
+		case reflect.Bool:
+			return strconv.FormatBool(rv.Bool()), nil
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			return strconv.FormatInt(rv.Int(), 10), nil
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+			return strconv.FormatUint(rv.Uint(), 10), nil
+		case reflect.Pointer:
+			if !rv.IsNil() {
+				return rawValue(rv.Elem().Interface())
+			}
+		default:
+			return "", fmt.Errorf("unsupported type %T", value)
+		}
+	}
+	return "", errors.New("nil value")
+}
+
+func fieldAttrName(field reflect.StructField) (name string, omitEmpty bool) {
+	tag, ok := field.Tag.Lookup("cred")
+	if !ok {
+		tag = field.Tag.Get("json")
+	}
+	name, opts, _ := strings.Cut(tag, ",")
+	if name == "" {
+		name = field.Name
+	}
+	for _, opt := range strings.Split(opts, ",") {
+		omitEmpty = omitEmpty || opt == "omitempty"
+	}
+	return name, omitEmpty
+}
+
+// normalizeAttrName normalizes the attribute name like libindy does: names
+// are case insensitive and spaces are ignored.
+func normalizeAttrName(name string) string {
+	return strings.ToLower(strings.ReplaceAll(name, " ", ""))
+}
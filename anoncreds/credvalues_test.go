@@ -0,0 +1,153 @@
+package anoncreds
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/findy-network/findy-wrapper-go/dto"
+	"github.com/lainio/err2/assert"
+)
+
+// ariesVectors are the encoding test vectors of Aries RFC 0592 and ACA-Py.
+var ariesVectors = map[string]string{
+	"101 Wilson Lane": "68086943237164982734333428280784300550565381723532936263016368251445461241953",
+	"87121":           "87121",
+	"SLC":             "101327353979588246869873249766058188995681113722618593621043638294296500696424",
+	"101 Tela Lane":   "63690509275174663089934667471948380740244018358024875547775652380902762701972",
+	"UT":              "93856629670657830351991220989031130499313559332549427637940645777813964461231",
+	"Alice Smith":     "62816810226936654797779705000772968058283780124309077049681734835796332704413",
+	"2018-05-28":      "23402637423876324098256519317695433196813217785795317220680415812348801086586",
+}
+
+func TestEncode(t *testing.T) {
+	defer assert.PushTester(t)()
+
+	assert.Equal(CurrentEncoder(), EncoderAries)
+	for raw, encoded := range ariesVectors {
+		assert.Equal(Encode(raw), encoded)
+	}
+
+	assert.NoError(SetEncoder(EncoderLegacy))
+	defer func() { _ = SetEncoder(EncoderAries) }()
+	assert.Equal(Encode("AliceGarcia"), "79092137925437828096026976")
+	assert.Error(SetEncoder("unknown"))
+	assert.Equal(CurrentEncoder(), EncoderLegacy)
+}
+
+func TestRegisterEncoder(t *testing.T) {
+	defer assert.PushTester(t)()
+
+	RegisterEncoder("test-len", func(raw string) string {
+		return dto.ToJSON(len(raw))
+	})
+	defer UnregisterEncoder("test-len")
+	enc, ok := LookupEncoder("test-len")
+	assert.That(ok)
+	assert.Equal(enc("abc"), "3")
+
+	values, err := NewCredValuesBuilder("name").
+		Encoding("test-len").
+		Set("name", "Alice").
+		Build()
+	assert.NoError(err)
+	assert.Equal(values["name"].Encoded, "5")
+
+	UnregisterEncoder("test-len")
+	_, ok = LookupEncoder("test-len")
+	assert.ThatNot(ok)
+	UnregisterEncoder(EncoderAries)
+	_, ok = LookupEncoder(EncoderAries)
+	assert.That(ok)
+}
+
+func TestCredValuesBuilder_Build(t *testing.T) {
+	defer assert.PushTester(t)()
+
+	type address struct {
+		Address1 string `cred:"address1"`
+		Address2 string `json:"address2"`
+		City     string
+		State    string `cred:"state"`
+		Zip      uint32 `cred:"zip"`
+		Note     string `cred:"-"`
+		Country  string `cred:"country,omitempty"`
+		internal string
+	}
+	schema := `{"ver":"1.0","id":"5Lgx4KLRTNgexDqT7WDALu:2:address:1.0",` +
+		`"name":"address","version":"1.0","attrNames":["address1","address2",` +
+		`"city","state","zip","verified","since"],"seqNo":null}`
+	b, err := NewCredValuesBuilderFromSchema(schema)
+	assert.NoError(err)
+	values, err := b.
+		SetStruct(&address{
+			Address1: "101 Tela Lane",
+			Address2: "101 Wilson Lane",
+			City:     "SLC",
+			State:    "UT",
+			Zip:      87121,
+			Note:     "not in schema",
+			internal: "not exported",
+		}).
+		SetMap(map[string]any{
+			"Verified": true,
+			"since":    time.Date(2018, 5, 28, 12, 0, 0, 0, time.UTC),
+		}).
+		Build()
+	assert.NoError(err)
+	assert.MLen(values, 7)
+	for _, value := range values {
+		if encoded, ok := ariesVectors[value.Raw]; ok {
+			assert.Equal(value.Encoded, encoded)
+		}
+	}
+	assert.Equal(values["city"].Encoded, ariesVectors["SLC"])
+	assert.Equal(values["verified"].Raw, "true")
+	assert.Equal(values["since"].Raw, "20180528")
+	assert.Equal(values["since"].Encoded, "20180528")
+}
+
+func TestCredValuesBuilder_errors(t *testing.T) {
+	defer assert.PushTester(t)()
+
+	_, err := NewCredValuesBuilder("email", "age", "score").
+		Set("email", "alice@example.com").
+		Set("phone", "555-1234").
+		Set("age", 3.5).
+		Set("score", nil).
+		Encoding("unknown").
+		BuildJSON()
+	assert.Error(err)
+	for _, want := range []string{
+		"phone: not in schema",
+		"age: unsupported type float64",
+		"score: nil value",
+		"age: value is missing",
+		`encoder "unknown" is not registered`,
+	} {
+		assert.That(strings.Contains(err.Error(), want), err.Error())
+	}
+
+	_, err = NewCredValuesBuilder("email").SetStruct("string").Build()
+	assert.Error(err)
+
+	_, err = NewCredValuesBuilderFromSchema(`{"attrNames":[]}`)
+	assert.Error(err)
+}
+
+func TestCredValuesBuilder_BuildJSON(t *testing.T) {
+	defer assert.PushTester(t)()
+
+	age := 42
+	js, err := NewCredValuesBuilder("Email Address", "age").
+		Set("email address", "alice@example.com").
+		Set("age", &age).
+		BuildJSON()
+	assert.NoError(err)
+
+	var values map[string]CredDefAttr
+	dto.FromJSONStr(js, &values)
+	assert.MLen(values, 2)
+	assert.Equal(values["Email Address"].Raw, "alice@example.com")
+	assert.Equal(values["age"], CredDefAttr{Raw: "42", Encoded: "42"})
+}
@@ -0,0 +1,99 @@
+package anoncreds
+
+import (
+	"fmt"
+	"sync"
+)
+
+// Encoder encodes the raw value of a credential attribute to the decimal
+// integer string which libindy signs.
+type Encoder func(raw string) string
+
+// Names of the built-in encoders.
+const (
+	// EncoderAries is the encoding of Aries RFC 0592: 32-bit integers are
+	// kept as they are, and everything else is the SHA-256 of the raw value.
+	// It's the default encoder.
+	EncoderAries = "aries"
+
+	// EncoderLegacy is the original encoding of this package. See SetRaw.
+	EncoderLegacy = "legacy"
+)
+
+var encoders = struct {
+	sync.RWMutex
+	registered map[string]Encoder
+	current    string
+}{
+	registered: map[string]Encoder{
+		EncoderAries:  encodeAries,
+		EncoderLegacy: encode,
+	},
+	current: EncoderAries,
+}
+
+// RegisterEncoder registers the encoder with the name. It replaces the
+// previously registered encoder with the same name.
+func RegisterEncoder(name string, enc Encoder) {
+	encoders.Lock()
+	defer encoders.Unlock()
+	encoders.registered[name] = enc
+}
+
+// UnregisterEncoder removes the registered encoder. The built-in encoders
+// cannot be removed, and if the removed encoder is the default encoder, the
+// default is EncoderAries again.
+func UnregisterEncoder(name string) {
+	encoders.Lock()
+	defer encoders.Unlock()
+	if name == EncoderAries || name == EncoderLegacy {
+		return
+	}
+	delete(encoders.registered, name)
+	if encoders.current == name {
+		encoders.current = EncoderAries
+	}
+}
+
+// SetEncoder switches the default encoder used by Encode and
+// CredValuesBuilder. The encoder must be registered.
+func SetEncoder(name string) error {
+	encoders.Lock()
+	defer encoders.Unlock()
+	if _, ok := encoders.registered[name]; !ok {
+		return fmt.Errorf("encoder %q is not registered", name)
+	}
+	encoders.current = name
+	return nil
+}
+
+// CurrentEncoder returns the name of the default encoder.
+func CurrentEncoder() string {
+	encoders.RLock()
+	defer encoders.RUnlock()
+	return encoders.current
+}
+
+// LookupEncoder returns the registered encoder by the name.
+func LookupEncoder(name string) (enc Encoder, ok bool) {
+	encoders.RLock()
+	defer encoders.RUnlock()
+	enc, ok = encoders.registered[name]
+	return enc, ok
+}
+
+// Encode encodes the raw value with the default encoder.
+func Encode(raw string) string {
+	encoders.RLock()
+	enc := encoders.registered[encoders.current]
+	encoders.RUnlock()
+	return enc(raw)
+}
+
+// SetValue sets the raw value of the attribute and writes the encoded value of
+// it with the default encoder.
+func (a *CredDefAttr) SetValue(s string) string {
+	a.Raw = s
+	a.Encoded = Encode(s)
+	return a.Encoded
+}
@@ -0,0 +1,175 @@
+package anoncreds
+
+import (
+	"encoding/json"
+	"sort"
+	"strings"
+
+	"github.com/findy-network/findy-wrapper-go/dto"
+	"github.com/findy-network/findy-wrapper-go/identifiers"
+	"github.com/findy-network/findy-wrapper-go/wql"
+)
+
+const (
+	attrTagPrefix   = "attr::"
+	attrValueSuffix = "::value"
+	attrMarkerSuff  = "::marker"
+)
+
+// filterFields is Filter without the JSON methods.
+type filterFields Filter
+
+// MarshalJSON marshals the filter to libindy's JSON format where attribute
+// values and markers are attr::<name>::value and attr::<name>::marker tags.
+func (f Filter) MarshalJSON() ([]byte, error) {
+	if len(f.AttrValues) == 0 && len(f.AttrMarkers) == 0 {
+		return json.Marshal(filterFields(f))
+	}
+	data, err := json.Marshal(filterFields(f))
+	if err != nil {
+		return nil, err
+	}
+	obj := map[string]string{}
+	if err = json.Unmarshal(data, &obj); err != nil {
+		return nil, err
+	}
+	for name, value := range f.AttrValues {
+		obj[attrTagPrefix+name+attrValueSuffix] = value
+	}
+	for _, name := range f.AttrMarkers {
+		obj[attrTagPrefix+name+attrMarkerSuff] = "1"
+	}
+	return json.Marshal(obj)
+}
+
+// UnmarshalJSON unmarshals the filter from libindy's JSON format. See
+// MarshalJSON.
+func (f *Filter) UnmarshalJSON(data []byte) error {
+	var fields filterFields
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return err
+	}
+	obj := map[string]interface{}{}
+	if err := json.Unmarshal(data, &obj); err != nil {
+		return err
+	}
+	for key, value := range obj {
+		name, isAttr := strings.CutPrefix(key, attrTagPrefix)
+		if !isAttr {
+			continue
+		}
+		if attr, isValue := strings.CutSuffix(name, attrValueSuffix); isValue {
+			if fields.AttrValues == nil {
+				fields.AttrValues = map[string]string{}
+			}
+			s, _ := value.(string)
+			fields.AttrValues[attr] = s
+		} else if attr, isMarker := strings.CutSuffix(name, attrMarkerSuff); isMarker {
+			fields.AttrMarkers = append(fields.AttrMarkers, attr)
+		}
+	}
+	sort.Strings(fields.AttrMarkers)
+	*f = Filter(fields)
+	return nil
+}
+
+// Query returns the filter as a WQL query for the credential searches. The
+// empty filter is the zero query, which matches every credential.
+func (f Filter) Query() wql.Query {
+	queries := make([]wql.Query, 0, 6+len(f.AttrValues)+len(f.AttrMarkers))
+	for _, field := range []struct{ tag, value string }{
+		{"schema_id", f.SchemaID},
+		{"schema_issuer_did", f.SchemaIssuerDID},
+		{"schema_name", f.SchemaName},
+		{"schema_version", f.SchemaVersion},
+		{"issuer_did", f.IssuerDID},
+		{"cred_def_id", f.CredDefID},
+	} {
+		if field.value != "" {
+			queries = append(queries, wql.Eq(field.tag, field.value))
+		}
+	}
+	names := make([]string, 0, len(f.AttrValues))
+	for name := range f.AttrValues {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		queries = append(queries, wql.AttrValue(name, f.AttrValues[name]))
+	}
+	for _, name := range f.AttrMarkers {
+		queries = append(queries, wql.AttrMarker(name))
+	}
+
+	switch len(queries) {
+	case 0:
+		return wql.Query{}
+	case 1:
+		return queries[0]
+	}
+	return wql.And(queries...)
+}
+
+// ExtraQuery returns the extra query JSON of
+// ProverSearchCredentialsForProofReq from the WQL queries by the referents.
+func ExtraQuery(queries map[string]wql.Query) string {
+	return dto.ToJSON(queries)
+}
+
+// IsEmpty returns true if none of the filter fields is set. An empty filter
+// matches every credential, which is never what a restriction means.
+func (f Filter) IsEmpty() bool {
+	return f.SchemaID == "" && f.SchemaIssuerDID == "" && f.SchemaName == "" &&
+		f.SchemaVersion == "" && f.IssuerDID == "" && f.CredDefID == "" &&
+		len(f.AttrValues) == 0 && len(f.AttrMarkers) == 0
+}
+
+// ProvedCredential is the credential of a sub proof as Filter.Match sees it.
+// The maps are keyed by the normalized attribute names, i.e. lowercased
+// without spaces like libindy compares them.
+type ProvedCredential struct {
+	SchemaID  string
+	CredDefID string
+
+	// Values has the revealed raw values of the credential's attributes.
+	Values map[string]string
+
+	// Names has all of the attribute names requested from the credential,
+	// revealed or not, including the predicates.
+	Names map[string]bool
+}
+
+// Match returns true if the proved credential satisfies all of the fields
+// set in the filter. Identifiers and DIDs can be qualified or unqualified.
+// The attribute values must match the revealed raw values, i.e. an
+// attribute which isn't revealed doesn't match, and the markers must be in
+// the requested names.
+func (f Filter) Match(cred ProvedCredential) bool {
+	schema, _ := identifiers.ParseSchemaID(cred.SchemaID)
+	credDef, _ := identifiers.ParseCredDefID(cred.CredDefID)
+	if !(matchField(f.SchemaID, cred.SchemaID) &&
+		matchField(f.SchemaIssuerDID, schema.DID.ID) &&
+		matchField(f.SchemaName, schema.Name) &&
+		matchField(f.SchemaVersion, schema.Version) &&
+		matchField(f.IssuerDID, credDef.DID.ID) &&
+		matchField(f.CredDefID, cred.CredDefID)) {
+		return false
+	}
+	for name, want := range f.AttrValues {
+		raw, revealed := cred.Values[normalizeAttrName(name)]
+		if !revealed || raw != want {
+			return false
+		}
+	}
+	for _, name := range f.AttrMarkers {
+		if !cred.Names[normalizeAttrName(name)] {
+			return false
+		}
+	}
+	return true
+}
+
+// matchField compares the identifiers in their unqualified forms.
+func matchField(want, have string) bool {
+	return want == "" || identifiers.Unqualify(want) == identifiers.Unqualify(have)
+}
@@ -0,0 +1,104 @@
+package anoncreds
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/findy-network/findy-wrapper-go/dto"
+	"github.com/findy-network/findy-wrapper-go/ledger"
+	"github.com/lainio/err2"
+	"github.com/lainio/err2/try"
+)
+
+// LedgerObjects has schemas, cred defs and revocation registries by their IDs
+// in the format which ProverCreateProof and VerifierVerifyProof want them.
+type LedgerObjects struct {
+	Schemas  map[string]json.RawMessage
+	CredDefs map[string]json.RawMessage
+
+	RevRegDefs map[string]json.RawMessage
+
+	// RevRegs has the states of the revocation registries by their
+	// timestamps.
+	RevRegs map[string]map[int64]json.RawMessage
+}
+
+// NewLedgerObjects creates a new empty LedgerObjects.
+func NewLedgerObjects() LedgerObjects {
+	return LedgerObjects{
+		Schemas:    map[string]json.RawMessage{},
+		CredDefs:   map[string]json.RawMessage{},
+		RevRegDefs: map[string]json.RawMessage{},
+		RevRegs:    map[string]map[int64]json.RawMessage{},
+	}
+}
+
+// Read reads the schema and the cred def from the ledger plugins if they
+// aren't already read.
+func (lo LedgerObjects) Read(pool int, submitter, schemaID, credDefID string) (err error) {
+	defer err2.Handle(&err, "ledger objects: %s, %s", schemaID, credDefID)
+
+	if _, ok := lo.Schemas[schemaID]; !ok {
+		_, schema := try.To2(ledger.ReadSchema(pool, submitter, schemaID))
+		lo.Schemas[schemaID] = try.To1(rawJSON(schema))
+	}
+	if _, ok := lo.CredDefs[credDefID]; !ok {
+		_, credDef := try.To2(ledger.ReadCredDef(pool, submitter, credDefID))
+		lo.CredDefs[credDefID] = try.To1(rawJSON(credDef))
+	}
+	return nil
+}
+
+// ReadRevocation reads the revocation registry definition and the state of
+// the registry at the timestamp from the ledger plugins if they aren't already
+// read.
+func (lo LedgerObjects) ReadRevocation(
+	pool int,
+	submitter, revRegID string,
+	timestamp int64,
+) (err error) {
+	defer err2.Handle(&err, "ledger objects: %s at %d", revRegID, timestamp)
+
+	if _, ok := lo.RevRegDefs[revRegID]; !ok {
+		_, revRegDef := try.To2(ledger.ReadRevRegDef(pool, submitter, revRegID))
+		lo.RevRegDefs[revRegID] = try.To1(rawJSON(revRegDef))
+	}
+	if lo.RevRegs[revRegID] == nil {
+		lo.RevRegs[revRegID] = map[int64]json.RawMessage{}
+	}
+	if _, ok := lo.RevRegs[revRegID][timestamp]; !ok {
+		revReg := try.To1(ledger.ReadRevReg(pool, submitter, revRegID, timestamp))
+		lo.RevRegs[revRegID][timestamp] = try.To1(rawJSON(revReg))
+	}
+	return nil
+}
+
+// SchemasJSON returns the schemas JSON for ProverCreateProof and
+// VerifierVerifyProof.
+func (lo LedgerObjects) SchemasJSON() string {
+	return dto.ToJSON(lo.Schemas)
+}
+
+// CredDefsJSON returns the credential definitions JSON for ProverCreateProof
+// and VerifierVerifyProof.
+func (lo LedgerObjects) CredDefsJSON() string {
+	return dto.ToJSON(lo.CredDefs)
+}
+
+// RevRegDefsJSON returns the revocation registry definitions JSON for
+// VerifierVerifyProof.
+func (lo LedgerObjects) RevRegDefsJSON() string {
+	return dto.ToJSON(lo.RevRegDefs)
+}
+
+// RevRegsJSON returns the revocation registries JSON for VerifierVerifyProof.
+func (lo LedgerObjects) RevRegsJSON() string {
+	return dto.ToJSON(lo.RevRegs)
+}
+
+func rawJSON(s string) (json.RawMessage, error) {
+	if !json.Valid([]byte(s)) {
+		return nil, fmt.Errorf("invalid ledger JSON: %q", s)
+	}
+	return json.RawMessage(s), nil
+}
@@ -0,0 +1,297 @@
+package anoncreds
+
+import (
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+)
+
+// PredicateType is the type of the requested predicate, i.e. the comparison
+// operator of the predicate.
+type PredicateType string
+
+// Predicate types supported by libindy.
+const (
+	PredicateGE PredicateType = ">="
+	PredicateGT PredicateType = ">"
+	PredicateLE PredicateType = "<="
+	PredicateLT PredicateType = "<"
+)
+
+// Valid returns true if the predicate type is supported by libindy.
+func (pt PredicateType) Valid() bool {
+	switch pt {
+	case PredicateGE, PredicateGT, PredicateLE, PredicateLT:
+		return true
+	}
+	return false
+}
+
+// nonceBits is the size of the nonce. libindy's indy_generate_nonce uses 80
+// bits too.
+const nonceBits = 80
+
+// NewNonce generates a cryptographically secure nonce for a proof request. It's
+// a decimal number string like libindy wants it.
+func NewNonce() (string, error) {
+	max := new(big.Int).Lsh(big.NewInt(1), nonceBits)
+	n, err := rand.Int(rand.Reader, max)
+	if err != nil {
+		return "", fmt.Errorf("nonce: %w", err)
+	}
+	return n.String(), nil
+}
+
+// AttrOption is an option for the requested attributes and predicates of
+// ProofRequestBuilder.
+type AttrOption func(o *attrOptions)
+
+type attrOptions struct {
+	restrictions []Filter
+	nonRevoked   *NonRevocInterval
+}
+
+func newAttrOptions(opts []AttrOption) attrOptions {
+	var o attrOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return o
+}
+
+// WithRestrictions sets restrictions for the attribute. The credential must
+// match at least one of the filters.
+func WithRestrictions(filters ...Filter) AttrOption {
+	return func(o *attrOptions) {
+		o.restrictions = append(o.restrictions, filters...)
+	}
+}
+
+// WithNonRevoked sets the non-revocation interval of the attribute. It
+// overrides the interval of the proof request. Zero from means no beginning.
+func WithNonRevoked(from, to int) AttrOption {
+	return func(o *attrOptions) {
+		o.nonRevoked = &NonRevocInterval{From: from, To: to}
+	}
+}
+
+/*
+ProofRequestBuilder builds valid proof requests. The errors are collected
+during building and Build returns them all.
+
+	pr, err := anoncreds.NewProofRequestBuilder("Proof of Education", "1.0").
+		Attr("0_name_uuid", "name",
+			anoncreds.WithRestrictions(anoncreds.Filter{IssuerDID: issuer})).
+		Predicate("0_age_GE_uuid", "age", anoncreds.PredicateGE, 18,
+			anoncreds.WithRestrictions(anoncreds.Filter{IssuerDID: issuer}),
+			anoncreds.WithNonRevoked(0, int(time.Now().Unix()))).
+		Build()
+*/
+type ProofRequestBuilder struct {
+	pr   ProofRequest
+	errs []error
+}
+
+// NewProofRequestBuilder creates a new builder for the proof request with the
+// name and the version. The nonce is generated by Build if it isn't set.
+func NewProofRequestBuilder(name, version string) *ProofRequestBuilder {
+	return &ProofRequestBuilder{
+		pr: ProofRequest{
+			Name:                name,
+			Version:             version,
+			RequestedAttributes: map[string]AttrInfo{},
+			RequestedPredicates: map[string]PredicateInfo{},
+		},
+	}
+}
+
+// Nonce sets the nonce of the proof request. Use only for testing, Build
+// generates a secure one.
+func (b *ProofRequestBuilder) Nonce(nonce string) *ProofRequestBuilder {
+	b.pr.Nonce = nonce
+	return b
+}
+
+// NonRevoked sets the non-revocation interval of the whole proof request.
+func (b *ProofRequestBuilder) NonRevoked(from, to int) *ProofRequestBuilder {
+	b.pr.NonRevoked = &NonRevocInterval{From: from, To: to}
+	return b
+}
+
+// Attr requests the attribute by its name with the referent.
+func (b *ProofRequestBuilder) Attr(referent, name string, opts ...AttrOption) *ProofRequestBuilder {
+	o := newAttrOptions(opts)
+	b.addAttr(referent, AttrInfo{
+		Name:         name,
+		Restrictions: o.restrictions,
+		NonRevoked:   o.nonRevoked,
+	})
+	return b
+}
+
+// AttrGroup requests attributes which all must come from the same
+// credential.
+func (b *ProofRequestBuilder) AttrGroup(referent string, names []string, opts ...AttrOption) *ProofRequestBuilder {
+	o := newAttrOptions(opts)
+	b.addAttr(referent, AttrInfo{
+		Names:        names,
+		Restrictions: o.restrictions,
+		NonRevoked:   o.nonRevoked,
+	})
+	return b
+}
+
+// Predicate requests the predicate of the attribute with the referent, e.g.
+// age >= 18.
+func (b *ProofRequestBuilder) Predicate(
+	referent, name string,
+	pType PredicateType,
+	value int,
+	opts ...AttrOption,
+) *ProofRequestBuilder {
+	o := newAttrOptions(opts)
+	if b.checkReferent(referent) {
+		b.pr.RequestedPredicates[referent] = PredicateInfo{
+			Name:         name,
+			PType:        string(pType),
+			PValue:       value,
+			Restrictions: o.restrictions,
+			NonRevoked:   o.nonRevoked,
+		}
+	}
+	return b
+}
+
+// Build validates the proof request and returns it, or all of the errors
+// found.
+func (b *ProofRequestBuilder) Build() (pr ProofRequest, err error) {
+	if len(b.errs) > 0 {
+		return pr, errors.Join(b.errs...)
+	}
+	if b.pr.Nonce == "" {
+		if b.pr.Nonce, err = NewNonce(); err != nil {
+			return pr, err
+		}
+	}
+	if err = b.pr.Validate(); err != nil {
+		return pr, err
+	}
+	return b.pr, nil
+}
+
+func (b *ProofRequestBuilder) addAttr(referent string, info AttrInfo) {
+	if b.checkReferent(referent) {
+		b.pr.RequestedAttributes[referent] = info
+	}
+}
+
+func (b *ProofRequestBuilder) checkReferent(referent string) bool {
+	_, isAttr := b.pr.RequestedAttributes[referent]
+	_, isPred := b.pr.RequestedPredicates[referent]
+	if isAttr || isPred {
+		b.errs = append(b.errs, fmt.Errorf("%s: duplicate referent", referent))
+		return false
+	}
+	return true
+}
+
+// Validate checks that the proof request is valid for libindy: every
+// attribute has either a name or names, restrictions aren't empty,
+// predicates have known types, and non-revocation intervals are in order.
+// It returns all of the errors found.
+func (pr ProofRequest) Validate() error {
+	errs := make([]error, 0)
+	addErr := func(format string, a ...any) {
+		errs = append(errs, fmt.Errorf(format, a...))
+	}
+
+	if pr.Name == "" || pr.Version == "" {
+		addErr("name and version are mandatory")
+	}
+	if _, ok := new(big.Int).SetString(pr.Nonce, 10); !ok {
+		addErr("nonce must be a decimal number: %q", pr.Nonce)
+	}
+	if len(pr.RequestedAttributes)+len(pr.RequestedPredicates) == 0 {
+		addErr("nothing requested")
+	}
+	if err := pr.NonRevoked.validate(); err != nil {
+		addErr("%w", err)
+	}
+
+	for ref, info := range pr.RequestedAttributes {
+		if _, ok := pr.RequestedPredicates[ref]; ok {
+			addErr("%s: duplicate referent", ref)
+		}
+		name := strings.TrimSpace(info.Name)
+		switch {
+		case name != "" && len(info.Names) > 0:
+			addErr("%s: both name and names", ref)
+		case name == "" && len(info.Names) == 0:
+			addErr("%s: name or names is mandatory", ref)
+		}
+		if err := validateNames(info.Names); err != nil {
+			addErr("%s: %w", ref, err)
+		}
+		if err := validateRestrictions(info.Restrictions); err != nil {
+			addErr("%s: %w", ref, err)
+		}
+		if err := info.NonRevoked.validate(); err != nil {
+			addErr("%s: %w", ref, err)
+		}
+	}
+	for ref, info := range pr.RequestedPredicates {
+		if strings.TrimSpace(info.Name) == "" {
+			addErr("%s: name is mandatory", ref)
+		}
+		if !PredicateType(info.PType).Valid() {
+			addErr("%s: unknown predicate type %q", ref, info.PType)
+		}
+		if err := validateRestrictions(info.Restrictions); err != nil {
+			addErr("%s: %w", ref, err)
+		}
+		if err := info.NonRevoked.validate(); err != nil {
+			addErr("%s: %w", ref, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func validateNames(names []string) error {
+	seen := make(map[string]bool, len(names))
+	for _, name := range names {
+		key := normalizeAttrName(strings.TrimSpace(name))
+		if key == "" {
+			return errors.New("empty name in names")
+		}
+		if seen[key] {
+			return fmt.Errorf("duplicate name %q in names", name)
+		}
+		seen[key] = true
+	}
+	return nil
+}
+
+func validateRestrictions(restrictions []Filter) error {
+	for i, f := range restrictions {
+		if f.IsEmpty() {
+			return fmt.Errorf("restriction %d is empty", i)
+		}
+	}
+	return nil
+}
+
+func (nri *NonRevocInterval) validate() error {
+	if nri == nil {
+		return nil
+	}
+	if nri.From < 0 || nri.To < 0 {
+		return errors.New("negative non-revocation interval")
+	}
+	if nri.To != 0 && nri.From > nri.To {
+		return fmt.Errorf("non-revocation interval from %d is after to %d",
+			nri.From, nri.To)
+	}
+	return nil
+}
@@ -0,0 +1,187 @@
+package anoncreds
+
+import (
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/findy-network/findy-wrapper-go/dto"
+	"github.com/lainio/err2/assert"
+)
+
+func TestProofRequestBuilder_Build(t *testing.T) {
+	assert.PushTester(t)
+	defer assert.PopTester()
+
+	issuer := Filter{IssuerDID: "5Lgx4KLRTNgexDqT7WDALu"}
+	pr, err := NewProofRequestBuilder("Proof of Education", "1.0").
+		Attr("0_name_uuid", "name", WithRestrictions(issuer)).
+		AttrGroup("0_degree_uuid", []string{"degree", "date"},
+			WithRestrictions(Filter{
+				SchemaName: "Education",
+				AttrValues: map[string]string{"degree": "Maths"},
+			}),
+			WithNonRevoked(0, 1600000000)).
+		Attr("0_self_attested_thing_uuid", "self_attested_thing").
+		Predicate("0_age_GE_uuid", "age", PredicateGE, 18, WithRestrictions(issuer)).
+		Predicate("0_age_LT_uuid", "age", PredicateLT, 65, WithRestrictions(issuer)).
+		Build()
+	assert.NoError(err)
+	assert.NotEmpty(pr.Nonce)
+	assert.MLen(pr.RequestedAttributes, 3)
+	assert.MLen(pr.RequestedPredicates, 2)
+
+	js := dto.ToJSON(pr)
+	assert.That(strings.Contains(js, `"attr::degree::value":"Maths"`), js)
+	assert.That(strings.Contains(js, `"non_revoked":{"to":1600000000}`), js)
+	assert.ThatNot(strings.Contains(js, `"name":""`), js)
+
+	var pred map[string]interface{}
+	dto.FromJSONStr(dto.ToJSON(pr.RequestedPredicates["0_age_GE_uuid"]), &pred)
+	_, hasNonRevoked := pred["non_revoked"]
+	assert.ThatNot(hasNonRevoked)
+
+	var read ProofRequest
+	dto.FromJSONStr(js, &read)
+	assert.DeepEqual(read, pr)
+}
+
+func TestProofRequestBuilder_errors(t *testing.T) {
+	assert.PushTester(t)
+	defer assert.PopTester()
+
+	tests := []struct {
+		name    string
+		builder *ProofRequestBuilder
+		want    string
+	}{
+		{"duplicate referent", NewProofRequestBuilder("pr", "1.0").
+			Attr("ref", "name").Predicate("ref", "age", PredicateGE, 18),
+			"ref: duplicate referent"},
+		{"empty restriction", NewProofRequestBuilder("pr", "1.0").
+			Attr("ref", "name", WithRestrictions(Filter{})),
+			"ref: restriction 0 is empty"},
+		{"unknown predicate", NewProofRequestBuilder("pr", "1.0").
+			Predicate("ref", "age", "==", 18),
+			`ref: unknown predicate type "=="`},
+		{"interval", NewProofRequestBuilder("pr", "1.0").
+			Attr("ref", "name", WithNonRevoked(20, 10)),
+			"ref: non-revocation interval from 20 is after to 10"},
+		{"empty group", NewProofRequestBuilder("pr", "1.0").
+			AttrGroup("ref", nil),
+			"ref: name or names is mandatory"},
+		{"duplicate names", NewProofRequestBuilder("pr", "1.0").
+			AttrGroup("ref", []string{"first name", "FirstName"}),
+			`ref: duplicate name "FirstName" in names`},
+		{"nonce", NewProofRequestBuilder("pr", "1.0").
+			Nonce("abc").Attr("ref", "name"),
+			`nonce must be a decimal number: "abc"`},
+		{"nothing", NewProofRequestBuilder("pr", "1.0"),
+			"nothing requested"},
+		{"version", NewProofRequestBuilder("pr", "").Attr("ref", "name"),
+			"name and version are mandatory"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			defer assert.PushTester(t)()
+			_, err := tt.builder.Build()
+			assert.Error(err)
+			assert.Equal(err.Error(), tt.want)
+		})
+	}
+}
+
+func TestProofRequest_Validate(t *testing.T) {
+	assert.PushTester(t)
+	defer assert.PopTester()
+
+	pr := ProofRequest{
+		Name:    "pr",
+		Version: "1.0",
+		Nonce:   "123",
+		RequestedAttributes: map[string]AttrInfo{
+			"ref": {Name: "name", Names: []string{"name"}},
+		},
+	}
+	err := pr.Validate()
+	assert.Error(err)
+	assert.Equal(err.Error(), "ref: both name and names")
+
+	tests := []struct {
+		name string
+		info AttrInfo
+		err  string
+	}{
+		{"blank name", AttrInfo{Name: " \t"}, "ref: name or names is mandatory"},
+		{"blank in names", AttrInfo{Names: []string{"name", "\t"}}, "ref: empty name in names"},
+		{"duplicate case", AttrInfo{Names: []string{"Name", "name"}}, `ref: duplicate name "name" in names`},
+		{"duplicate spaces", AttrInfo{Names: []string{"first name", "FirstName "}}, `ref: duplicate name "FirstName " in names`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			defer assert.PushTester(t)()
+			pr.RequestedAttributes = map[string]AttrInfo{"ref": tt.info}
+			err := pr.Validate()
+			assert.Error(err)
+			assert.Equal(err.Error(), tt.err)
+		})
+	}
+}
+
+func TestNewNonce(t *testing.T) {
+	assert.PushTester(t)
+	defer assert.PopTester()
+
+	max := new(big.Int).Lsh(big.NewInt(1), nonceBits)
+	seen := map[string]bool{}
+	for i := 0; i < 100; i++ {
+		nonce, err := NewNonce()
+		assert.NoError(err)
+		n, ok := new(big.Int).SetString(nonce, 10)
+		assert.That(ok)
+		assert.That(n.Cmp(max) < 0)
+		assert.ThatNot(seen[nonce])
+		seen[nonce] = true
+	}
+}
+
+func TestFilter_JSON(t *testing.T) {
+	assert.PushTester(t)
+	defer assert.PopTester()
+
+	f := Filter{
+		CredDefID:   testCredDefID,
+		AttrValues:  map[string]string{"name": "Alice"},
+		AttrMarkers: []string{"age", "degree"},
+	}
+	js := dto.ToJSON(f)
+	assert.Equal(js, `{"attr::age::marker":"1","attr::degree::marker":"1",`+
+		`"attr::name::value":"Alice","cred_def_id":"`+testCredDefID+`"}`)
+
+	var read Filter
+	dto.FromJSONStr(js, &read)
+	assert.DeepEqual(read, f)
+	assert.ThatNot(read.IsEmpty())
+	assert.That(Filter{}.IsEmpty())
+
+	assert.Equal(dto.ToJSON(Filter{IssuerDID: "did"}), `{"issuer_did":"did"}`)
+}
+
+func TestFilter_Query(t *testing.T) {
+	defer assert.PushTester(t)()
+
+	assert.That(Filter{}.Query().IsZero())
+	assert.Equal(Filter{CredDefID: testCredDefID}.Query().String(),
+		`{"cred_def_id":"`+testCredDefID+`"}`)
+
+	q := Filter{
+		SchemaName:  "Education",
+		IssuerDID:   "5Lgx4KLRTNgexDqT7WDALu",
+		AttrValues:  map[string]string{"Degree": "Maths"},
+		AttrMarkers: []string{"date"},
+	}.Query()
+	assert.NoError(q.Validate())
+	assert.Equal(q.String(), `{"$and":[{"schema_name":"Education"},`+
+		`{"issuer_did":"5Lgx4KLRTNgexDqT7WDALu"},`+
+		`{"attr::degree::value":"Maths"},{"attr::date::marker":"1"}]}`)
+}
@@ -0,0 +1,114 @@
+package roles
+
+import (
+	"encoding/json"
+
+	"github.com/findy-network/findy-wrapper-go"
+	"github.com/findy-network/findy-wrapper-go/anoncreds"
+	"github.com/findy-network/findy-wrapper-go/dto"
+	"github.com/findy-network/findy-wrapper-go/ledger"
+	"github.com/lainio/err2"
+	"github.com/lainio/err2/try"
+)
+
+const holderKeyPrefix = "holder/"
+
+// Holder requests and stores credentials, and presents proofs of them.
+type Holder struct {
+	Context
+
+	// MasterSecretID is the ID of the holder's master secret in the wallet.
+	MasterSecretID string
+
+	store Store
+}
+
+// NewHolder creates a new Holder with the DID and the master secret of the
+// wallet. See CreateMasterSecret.
+func NewHolder(pool, wallet int, did, masterSecretID string, store Store) *Holder {
+	return &Holder{
+		Context:        Context{Pool: pool, Wallet: wallet, DID: did},
+		MasterSecretID: masterSecretID,
+		store:          store,
+	}
+}
+
+// CreateMasterSecret creates the master secret to the wallet. It's needed
+// only once per wallet.
+func (h *Holder) CreateMasterSecret() (err error) {
+	defer err2.Handle(&err, "create master secret")
+
+	r := <-anoncreds.ProverCreateMasterSecret(h.Wallet, h.MasterSecretID)
+	return r.Err()
+}
+
+// Request creates a credential request for the offer. The cred def is read
+// from the ledger. It returns the request JSON for the issuer, and the
+// issuance is identified by the nonce of the offer.
+func (h *Holder) Request(offer string) (credReq string, err error) {
+	defer err2.Handle(&err, "request")
+
+	var co credOffer
+	try.To(json.Unmarshal([]byte(offer), &co))
+	_, credDef := try.To2(ledger.ReadCredDef(h.Pool, h.DID, co.CredDefID))
+
+	r := <-anoncreds.ProverCreateCredentialReq(h.Wallet, h.DID, offer, credDef,
+		h.MasterSecretID)
+	try.To(r.Err())
+
+	issuance := &Issuance{
+		ID:              co.Nonce,
+		State:           StateRequested,
+		SchemaID:        co.SchemaID,
+		CredDefID:       co.CredDefID,
+		Offer:           offer,
+		CredReqMetadata: r.Str2(),
+	}
+	try.To(save(h.store, holderKeyPrefix+issuance.ID, issuance))
+	return r.Str1(), nil
+}
+
+// Store stores the issued credential of the requested issuance to the
+// wallet. It returns the credential ID in the wallet.
+func (h *Holder) Store(issuanceID, cred string) (credID string, err error) {
+	defer err2.Handle(&err, "store %s", issuanceID)
+
+	issuance := try.To1(h.Issuance(issuanceID))
+	try.To(checkState(issuanceID, issuance.State, StateRequested))
+	_, credDef := try.To2(ledger.ReadCredDef(h.Pool, h.DID, issuance.CredDefID))
+
+	r := <-anoncreds.ProverStoreCredential(h.Wallet, findy.NullString,
+		issuance.CredReqMetadata, cred, credDef, findy.NullString)
+	try.To(r.Err())
+
+	issuance.State = StateStored
+	issuance.CredID = r.Str1()
+	try.To(save(h.store, holderKeyPrefix+issuanceID, issuance))
+	return issuance.CredID, nil
+}
+
+// Present selects the credentials for the proof request with
+// anoncreds.AutoProve and creates the proof. It returns the proof JSON for
+// the verifier.
+func (h *Holder) Present(
+	proofReq anoncreds.ProofRequest,
+	policy anoncreds.SelectionPolicy,
+) (proof string, err error) {
+	defer err2.Handle(&err, "present %s", proofReq.Name)
+
+	ap := try.To1(anoncreds.AutoProve(h.Pool, h.Wallet, h.DID, proofReq, policy))
+	r := <-anoncreds.ProverCreateProof(h.Wallet, dto.ToJSON(proofReq),
+		ap.RequestedCredentialsJSON(), h.MasterSecretID, ap.SchemasJSON(),
+		ap.CredDefsJSON(), "{}")
+	try.To(r.Err())
+	return r.Str1(), nil
+}
+
+// Issuance returns the holder's issuance.
+func (h *Holder) Issuance(issuanceID string) (issuance *Issuance, err error) {
+	issuance = new(Issuance)
+	if err = load(h.store, holderKeyPrefix+issuanceID, issuance); err != nil {
+		return nil, err
+	}
+	return issuance, nil
+}
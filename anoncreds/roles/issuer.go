@@ -0,0 +1,131 @@
+package roles
+
+import (
+	"encoding/json"
+
+	"github.com/findy-network/findy-wrapper-go"
+	"github.com/findy-network/findy-wrapper-go/anoncreds"
+	"github.com/findy-network/findy-wrapper-go/dto"
+	"github.com/findy-network/findy-wrapper-go/ledger"
+	"github.com/lainio/err2"
+	"github.com/lainio/err2/try"
+)
+
+const issuerKeyPrefix = "issuer/"
+
+// Issuance is the persisted state of an issuance. Issuer and Holder have
+// their own copies of it.
+type Issuance struct {
+	// ID is the nonce of the credential offer.
+	ID        string
+	State     State
+	SchemaID  string
+	CredDefID string
+
+	// Offer is the credential offer JSON for the holder.
+	Offer string
+
+	// CredReqMetadata is the holder's metadata of the credential request.
+	CredReqMetadata string `json:",omitempty"`
+
+	// CredID is the holder's wallet ID of the stored credential.
+	CredID string `json:",omitempty"`
+}
+
+// credOffer has the fields of the libindy credential offer we need.
+type credOffer struct {
+	SchemaID  string `json:"schema_id"`
+	CredDefID string `json:"cred_def_id"`
+	Nonce     string `json:"nonce"`
+}
+
+// Issuer publishes schemas and cred defs, and issues credentials.
+type Issuer struct {
+	Context
+	store Store
+}
+
+// NewIssuer creates a new Issuer with the DID of the wallet.
+func NewIssuer(pool, wallet int, did string, store Store) *Issuer {
+	return &Issuer{
+		Context: Context{Pool: pool, Wallet: wallet, DID: did},
+		store:   store,
+	}
+}
+
+// PublishSchema creates the schema and writes it to the ledger. It returns
+// the schema ID.
+func (i *Issuer) PublishSchema(name, version string, attrNames ...string) (schemaID string, err error) {
+	defer err2.Handle(&err, "publish schema %s", name)
+
+	r := <-anoncreds.IssuerCreateSchema(i.DID, name, version, dto.ToJSON(attrNames))
+	try.To(r.Err())
+	try.To(ledger.WriteSchema(i.Pool, i.Wallet, i.DID, r.Str2()))
+	return r.Str1(), nil
+}
+
+// PublishCredDef reads the schema from the ledger to get its seqNo, creates
+// the cred def with the tag, and writes it to the ledger. It returns the cred
+// def ID. Revocation isn't supported.
+func (i *Issuer) PublishCredDef(schemaID, tag string) (credDefID string, err error) {
+	defer err2.Handle(&err, "publish cred def %s", tag)
+
+	_, schema := try.To2(ledger.ReadSchema(i.Pool, i.DID, schemaID))
+	r := <-anoncreds.IssuerCreateAndStoreCredentialDef(i.Wallet, i.DID, schema,
+		tag, findy.NullString, findy.NullString)
+	try.To(r.Err())
+	try.To(ledger.WriteCredDef(i.Pool, i.Wallet, i.DID, r.Str2()))
+	return r.Str1(), nil
+}
+
+// Offer creates a credential offer and starts a new issuance, which is
+// identified by the nonce of the offer.
+func (i *Issuer) Offer(credDefID string) (issuance *Issuance, err error) {
+	defer err2.Handle(&err, "offer %s", credDefID)
+
+	r := <-anoncreds.IssuerCreateCredentialOffer(i.Wallet, credDefID)
+	try.To(r.Err())
+	var offer credOffer
+	try.To(json.Unmarshal([]byte(r.Str1()), &offer))
+
+	issuance = &Issuance{
+		ID:        offer.Nonce,
+		State:     StateOffered,
+		SchemaID:  offer.SchemaID,
+		CredDefID: offer.CredDefID,
+		Offer:     r.Str1(),
+	}
+	try.To(save(i.store, issuerKeyPrefix+issuance.ID, issuance))
+	return issuance, nil
+}
+
+// Issue issues the credential of the offered issuance for the credential
+// request. The values are validated against the schema and encoded with
+// anoncreds.CredValuesBuilder. It returns the credential JSON for the holder.
+func (i *Issuer) Issue(issuanceID, credReq string, values map[string]any) (cred string, err error) {
+	defer err2.Handle(&err, "issue %s", issuanceID)
+
+	issuance := try.To1(i.Issuance(issuanceID))
+	try.To(checkState(issuanceID, issuance.State, StateOffered))
+
+	_, schema := try.To2(ledger.ReadSchema(i.Pool, i.DID, issuance.SchemaID))
+	b := try.To1(anoncreds.NewCredValuesBuilderFromSchema(schema))
+	credValues := try.To1(b.SetMap(values).BuildJSON())
+
+	r := <-anoncreds.IssuerCreateCredential(i.Wallet, issuance.Offer, credReq,
+		credValues, findy.NullString, findy.NullHandle)
+	try.To(r.Err())
+
+	issuance.State = StateIssued
+	try.To(save(i.store, issuerKeyPrefix+issuanceID, issuance))
+	return r.Str1(), nil
+}
+
+// Issuance returns the issuer's issuance.
+func (i *Issuer) Issuance(issuanceID string) (issuance *Issuance, err error) {
+	issuance = new(Issuance)
+	if err = load(i.store, issuerKeyPrefix+issuanceID, issuance); err != nil {
+		return nil, err
+	}
+	return issuance, nil
+}
@@ -0,0 +1,66 @@
+/*
+Package roles orchestrates the anoncreds workflows with Issuer, Holder and
+Verifier role objects. The role objects hold the pool, the wallet and the DID,
+and run the libindy and ledger calls of one workflow step in the right order.
+
+The issuance state is persisted to a Store between the steps, which means that
+the steps can be run in different processes as long as they share the store.
+Issuances are identified by the nonce of the credential offer, and
+presentations by the nonce of the proof request. Both parties know them.
+
+	issuer := roles.NewIssuer(pool, w1, issuerDID, store)
+	schemaID, err := issuer.PublishSchema("email", "1.0", "email")
+	credDefID, err := issuer.PublishCredDef(schemaID, "default")
+	offer, err := issuer.Offer(credDefID)
+
+	holder := roles.NewHolder(pool, w2, holderDID, "master-secret", store)
+	credReq, err := holder.Request(offer.Offer)
+	cred, err := issuer.Issue(offer.ID, credReq, map[string]any{"email": "alice@example.com"})
+	credID, err := holder.Store(offer.ID, cred)
+
+	verifier := roles.NewVerifier(pool, w3, verifierDID, store)
+	proofReq, err := verifier.Request(anoncreds.NewProofRequestBuilder("email", "1.0").
+		Attr("attr1_referent", "email",
+			anoncreds.WithRestrictions(anoncreds.Filter{CredDefID: credDefID})))
+	proof, err := holder.Present(proofReq, anoncreds.SelectionPolicy{})
+	report, err := verifier.Verify(proofReq.Nonce, proof)
+*/
+package roles
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Context is the pool, the wallet and the DID of the role.
+type Context struct {
+	Pool   int
+	Wallet int
+	DID    string
+}
+
+// State is the state of an issuance or a presentation.
+type State string
+
+// States of the issuance and the presentation workflows.
+const (
+	StateOffered   State = "offered"
+	StateRequested State = "requested"
+	StateIssued    State = "issued"
+	StateStored    State = "stored"
+	StateVerified  State = "verified"
+	StateFailed    State = "failed"
+)
+
+// ErrState is returned when the workflow step isn't allowed in the current
+// state.
+var ErrState = errors.New("wrong state")
+
+func checkState(id string, have State, want ...State) error {
+	for _, s := range want {
+		if have == s {
+			return nil
+		}
+	}
+	return fmt.Errorf("%w: %s is %s, want %v", ErrState, id, have, want)
+}
@@ -0,0 +1,81 @@
+package roles
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/lainio/err2/assert"
+
+	"github.com/findy-network/findy-wrapper-go"
+	"github.com/findy-network/findy-wrapper-go/anoncreds"
+	"github.com/findy-network/findy-wrapper-go/did"
+	"github.com/findy-network/findy-wrapper-go/helpers"
+	"github.com/findy-network/findy-wrapper-go/ledger"
+)
+
+const ledgerWaitTimer = 3 * time.Second
+
+func TestRoles(t *testing.T) {
+	defer assert.PushTester(t)()
+
+	pool := helpers.OpenTestPool(t)
+	w, name := helpers.CreateAndOpenTestWallet(t)
+	store, err := NewFileStore(t.TempDir())
+	assert.NoError(err)
+
+	r := <-did.CreateAndStore(w, did.Did{Seed: "000000000000000000000000Steward1"})
+	assert.NoError(r.Err())
+	stewardDID := r.Str1()
+	assert.NoError(ledger.WriteDID(pool, w, stewardDID, stewardDID, r.Str2(),
+		findy.NullString, findy.NullString))
+
+	issuer := NewIssuer(pool, w, stewardDID, store)
+	schemaID, err := issuer.PublishSchema(
+		fmt.Sprintf("ROLES_SCHEMA_%v", time.Now().Unix()), "1.0", "email")
+	assert.NoError(err)
+	time.Sleep(ledgerWaitTimer)
+	credDefID, err := issuer.PublishCredDef(schemaID, "ROLES")
+	assert.NoError(err)
+	time.Sleep(ledgerWaitTimer)
+
+	offer, err := issuer.Offer(credDefID)
+	assert.NoError(err)
+	assert.Equal(offer.State, StateOffered)
+
+	holder := NewHolder(pool, w, stewardDID, "roles-master-secret", store)
+	assert.NoError(holder.CreateMasterSecret())
+	credReq, err := holder.Request(offer.Offer)
+	assert.NoError(err)
+
+	_, err = issuer.Issue(offer.ID, credReq, map[string]any{"phone": "555-1234"})
+	assert.Error(err)
+	cred, err := issuer.Issue(offer.ID, credReq, map[string]any{"email": "alice@example.com"})
+	assert.NoError(err)
+	_, err = issuer.Issue(offer.ID, credReq, map[string]any{"email": "alice@example.com"})
+	assert.Error(err)
+
+	credID, err := holder.Store(offer.ID, cred)
+	assert.NoError(err)
+	issuance, err := holder.Issuance(offer.ID)
+	assert.NoError(err)
+	assert.Equal(issuance.State, StateStored)
+	assert.Equal(issuance.CredID, credID)
+
+	verifier := NewVerifier(pool, w, stewardDID, store)
+	proofReq, err := verifier.Request(anoncreds.NewProofRequestBuilder("email", "1.0").
+		Attr("attr1_referent", "email",
+			anoncreds.WithRestrictions(anoncreds.Filter{CredDefID: credDefID})))
+	assert.NoError(err)
+	proof, err := holder.Present(proofReq, anoncreds.SelectionPolicy{})
+	assert.NoError(err)
+	report, err := verifier.Verify(proofReq.Nonce, proof)
+	assert.NoError(err)
+	assert.That(report.Verified)
+	presentation, err := verifier.Presentation(proofReq.Nonce)
+	assert.NoError(err)
+	assert.Equal(presentation.State, StateVerified)
+
+	helpers.CloseAndDeleteTestWallet(w, name, t)
+	helpers.CloseTestPool(pool, t)
+}
@@ -0,0 +1,105 @@
+package roles
+
+import (
+	"encoding/json"
+	"errors"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// ErrNotFound is returned by Store.Load when the key doesn't exist.
+var ErrNotFound = errors.New("state not found")
+
+// Store persists the workflow states by their keys.
+type Store interface {
+	Save(key string, data []byte) error
+
+	// Load follows ErrNotFound semantics.
+	Load(key string) ([]byte, error)
+}
+
+// MemStore is a transient Store. It's convenient for unit tests and for the
+// workflows run in one process.
+type MemStore struct {
+	mu     sync.Mutex
+	states map[string][]byte
+}
+
+// NewMemStore creates a new empty MemStore.
+func NewMemStore() *MemStore {
+	return &MemStore{states: make(map[string][]byte)}
+}
+
+// Save saves a copy of the data.
+func (s *MemStore) Save(key string, data []byte) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.states[key] = append([]byte(nil), data...)
+	return nil
+}
+
+// Load returns a copy of the data.
+func (s *MemStore) Load(key string) ([]byte, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	data, ok := s.states[key]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	return append([]byte(nil), data...), nil
+}
+
+// FileStore is a Store which writes every state to its own JSON file in the
+// directory.
+type FileStore struct {
+	dir string
+}
+
+// NewFileStore creates the directory if needed and returns a FileStore for
+// it.
+func NewFileStore(dir string) (*FileStore, error) {
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, err
+	}
+	return &FileStore{dir: dir}, nil
+}
+
+// Save writes the data to the file of the key.
+func (s *FileStore) Save(key string, data []byte) error {
+	tmp := s.filename(key) + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o600); err != nil {
+		return err
+	}
+	return os.Rename(tmp, s.filename(key))
+}
+
+// Load reads the data from the file of the key.
+func (s *FileStore) Load(key string) ([]byte, error) {
+	data, err := os.ReadFile(s.filename(key))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, ErrNotFound
+	}
+	return data, err
+}
+
+func (s *FileStore) filename(key string) string {
+	return filepath.Join(s.dir, url.PathEscape(key)+".json")
+}
+
+func save(store Store, key string, state any) error {
+	data, err := json.Marshal(state)
+	if err != nil {
+		return err
+	}
+	return store.Save(key, data)
+}
+
+func load(store Store, key string, state any) error {
+	data, err := store.Load(key)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, state)
+}
@@ -0,0 +1,53 @@
+package roles
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/lainio/err2/assert"
+)
+
+func TestStores(t *testing.T) {
+	defer assert.PushTester(t)()
+
+	fileStore, err := NewFileStore(t.TempDir())
+	assert.NoError(err)
+	for _, store := range []Store{NewMemStore(), fileStore} {
+		_, err := store.Load("issuer/1")
+		assert.That(errors.Is(err, ErrNotFound))
+
+		issuance := &Issuance{ID: "1", State: StateOffered, Offer: "{}"}
+		assert.NoError(save(store, issuerKeyPrefix+issuance.ID, issuance))
+		issuance.State = StateIssued
+		assert.NoError(save(store, issuerKeyPrefix+issuance.ID, issuance))
+
+		var loaded Issuance
+		assert.NoError(load(store, issuerKeyPrefix+"1", &loaded))
+		assert.DeepEqual(loaded, *issuance)
+	}
+}
+
+func TestCheckState(t *testing.T) {
+	defer assert.PushTester(t)()
+
+	store := NewMemStore()
+	issuance := &Issuance{ID: "1", State: StateIssued}
+	assert.NoError(save(store, issuerKeyPrefix+issuance.ID, issuance))
+	issuance.State = StateStored
+	assert.NoError(save(store, holderKeyPrefix+issuance.ID, issuance))
+	presentation := &Presentation{ID: "2", State: StateVerified}
+	assert.NoError(save(store, verifierKeyPrefix+presentation.ID, presentation))
+
+	// the states are checked before the wallet and the ledger are used
+	_, err := NewIssuer(0, 0, "", store).Issue("1", "{}", nil)
+	assert.That(errors.Is(err, ErrState))
+	_, err = NewHolder(0, 0, "", "", store).Store("1", "{}")
+	assert.That(errors.Is(err, ErrState))
+	_, err = NewVerifier(0, 0, "", store).Verify("2", "{}")
+	assert.That(errors.Is(err, ErrState))
+
+	_, err = NewVerifier(0, 0, "", store).Verify("3", "{}")
+	assert.That(errors.Is(err, ErrNotFound))
+
+	assert.NoError(checkState("1", StateRequested, StateOffered, StateRequested))
+}
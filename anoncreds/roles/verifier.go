@@ -0,0 +1,84 @@
+package roles
+
+import (
+	"github.com/findy-network/findy-wrapper-go/anoncreds"
+	"github.com/lainio/err2"
+	"github.com/lainio/err2/try"
+)
+
+const verifierKeyPrefix = "verifier/"
+
+// Presentation is the persisted state of a presentation at the verifier.
+type Presentation struct {
+	// ID is the nonce of the proof request.
+	ID           string
+	State        State
+	ProofRequest anoncreds.ProofRequest
+
+	// Failures are the failures of the verification, see
+	// anoncreds.ProofReport.
+	Failures []string `json:",omitempty"`
+}
+
+// Verifier requests and verifies proofs.
+type Verifier struct {
+	Context
+
+	// VerifyOptions tell the encoders of the issuers, see
+	// anoncreds.VerifyProofWith.
+	VerifyOptions anoncreds.VerifyOptions
+
+	store Store
+}
+
+// NewVerifier creates a new Verifier. The DID is used to read the ledger.
+func NewVerifier(pool, wallet int, did string, store Store) *Verifier {
+	return &Verifier{
+		Context: Context{Pool: pool, Wallet: wallet, DID: did},
+		store:   store,
+	}
+}
+
+// Request starts a new presentation of the proof request built by the
+// builder. The presentation is identified by the nonce of the proof request.
+func (v *Verifier) Request(b *anoncreds.ProofRequestBuilder) (pr anoncreds.ProofRequest, err error) {
+	defer err2.Handle(&err, "request proof")
+
+	pr = try.To1(b.Build())
+	presentation := &Presentation{
+		ID:           pr.Nonce,
+		State:        StateRequested,
+		ProofRequest: pr,
+	}
+	try.To(save(v.store, verifierKeyPrefix+presentation.ID, presentation))
+	return pr, nil
+}
+
+// Verify verifies the proof of the requested presentation with
+// anoncreds.VerifyProofWith. The presentation state is StateVerified or
+// StateFailed according to the report.
+func (v *Verifier) Verify(presentationID, proof string) (report *anoncreds.ProofReport, err error) {
+	defer err2.Handle(&err, "verify %s", presentationID)
+
+	presentation := try.To1(v.Presentation(presentationID))
+	try.To(checkState(presentationID, presentation.State, StateRequested))
+
+	report = try.To1(anoncreds.VerifyProofWith(v.Pool, v.DID,
+		presentation.ProofRequest, proof, v.VerifyOptions))
+	presentation.State = StateVerified
+	if !report.Verified {
+		presentation.State = StateFailed
+		presentation.Failures = report.Failures
+	}
+	try.To(save(v.store, verifierKeyPrefix+presentationID, presentation))
+	return report, nil
+}
+
+// Presentation returns the verifier's presentation.
+func (v *Verifier) Presentation(presentationID string) (presentation *Presentation, err error) {
+	presentation = new(Presentation)
+	if err = load(v.store, verifierKeyPrefix+presentationID, presentation); err != nil {
+		return nil, err
+	}
+	return presentation, nil
+}
@@ -0,0 +1,35 @@
+package anoncreds
+
+import (
+	"github.com/findy-network/findy-wrapper-go"
+	"github.com/findy-network/findy-wrapper-go/ledger"
+	"github.com/lainio/err2"
+	"github.com/lainio/err2/try"
+)
+
+/*
+RotateCredDef rotates the keys of the credential definition, e.g. after a key
+compromise. It generates the temporary keys, writes the rotated cred def to all
+of the ledger plugins with ledger.UpdateCredDef, and applies the new keys to
+the wallet only after the ledger is updated. It returns the rotated cred def
+JSON.
+
+If the ledger write fails, the wallet still uses the old keys, and the
+rotation can be started again. Credentials issued with the old keys cannot be
+verified with the rotated cred def.
+
+	credDef, err := anoncreds.RotateCredDef(pool, w, issuerDID, credDefID)
+*/
+func RotateCredDef(pool, wallet int, submitter, credDefID string) (credDef string, err error) {
+	defer err2.Handle(&err, "rotate cred def %s", credDefID)
+
+	r := <-IssuerRotateCredentialDefStart(wallet, credDefID, findy.NullString)
+	try.To(r.Err())
+	credDef = r.Str1()
+
+	try.To(ledger.UpdateCredDef(pool, wallet, submitter, credDef))
+
+	r = <-IssuerRotateCredentialDefApply(wallet, credDefID)
+	try.To(r.Err())
+	return credDef, nil
+}
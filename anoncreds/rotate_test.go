@@ -0,0 +1,58 @@
+package anoncreds
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/lainio/err2/assert"
+
+	"github.com/findy-network/findy-wrapper-go"
+	"github.com/findy-network/findy-wrapper-go/did"
+	"github.com/findy-network/findy-wrapper-go/helpers"
+	"github.com/findy-network/findy-wrapper-go/ledger"
+)
+
+func TestRotateCredDef(t *testing.T) {
+	defer assert.PushTester(t)()
+
+	pool := helpers.OpenTestPool(t)
+	w, name := helpers.CreateAndOpenTestWallet(t)
+
+	r := <-did.CreateAndStore(w, did.Did{Seed: "000000000000000000000000Steward1"})
+	assert.NoError(r.Err())
+	stewardDID := r.Str1()
+	assert.NoError(ledger.WriteDID(pool, w, stewardDID, stewardDID, r.Str2(),
+		findy.NullString, findy.NullString))
+
+	schemaName := fmt.Sprintf("ROTATE_SCHEMA_%v", time.Now().Unix())
+	r = <-IssuerCreateSchema(stewardDID, schemaName, "1.0", `["email"]`)
+	assert.NoError(r.Err())
+	assert.NoError(ledger.WriteSchema(pool, w, stewardDID, r.Str2()))
+	time.Sleep(ledgerWaitTimer)
+	_, scJSON, err := ledger.ReadSchema(pool, stewardDID, r.Str1())
+	assert.NoError(err)
+
+	r = <-IssuerCreateAndStoreCredentialDef(w, stewardDID, scJSON,
+		"ROTATED", findy.NullString, findy.NullString)
+	assert.NoError(r.Err())
+	credDefID := r.Str1()
+	oldCredDef := r.Str2()
+
+	assert.NoError(ledger.WriteCredDef(pool, w, stewardDID, oldCredDef))
+
+	credDef, err := RotateCredDef(pool, w, stewardDID, credDefID)
+	assert.NoError(err)
+	assert.NotEqual(credDef, oldCredDef)
+
+	time.Sleep(ledgerWaitTimer)
+	_, ledgerCredDef, err := ledger.ReadCredDef(pool, stewardDID, credDefID)
+	assert.NoError(err)
+	assert.Equal(ledgerCredDef, credDef)
+
+	r = <-IssuerCreateCredentialOffer(w, credDefID)
+	assert.NoError(r.Err())
+
+	helpers.CloseAndDeleteTestWallet(w, name, t)
+	helpers.CloseTestPool(pool, t)
+}
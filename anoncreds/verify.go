@@ -0,0 +1,366 @@
+package anoncreds
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"github.com/findy-network/findy-wrapper-go/dto"
+	"github.com/lainio/err2"
+	"github.com/lainio/err2/try"
+)
+
+// ProofReport explains the result of VerifyProof. All of the maps are keyed
+// by the referents of the proof request.
+type ProofReport struct {
+	// Verified is true only if libindy verified the proof and all of the
+	// checks of the report passed, i.e. Failures is empty.
+	Verified bool
+
+	// Revealed has the revealed values by the attribute names. An attribute
+	// requested with AttrInfo.Name has only one value.
+	Revealed map[string]map[string]RevealedValue
+
+	Predicates   map[string]PredicateOutcome
+	SelfAttested map[string]string
+	Restrictions map[string]RestrictionCheck
+
+	// Failures tells why the verification failed. It's sorted.
+	Failures []string
+}
+
+// RevealedValue is a revealed attribute value of the proof with the
+// identifiers of the credential it's from.
+type RevealedValue struct {
+	Raw       string
+	Encoded   string
+	SchemaID  string
+	CredDefID string
+}
+
+// PredicateOutcome tells the result of the requested predicate. Predicates
+// are zero knowledge proofs, which means that Satisfied is true only if the
+// whole proof is verified, i.e. it's the Verified of the report.
+type PredicateOutcome struct {
+	Name      string
+	PType     string
+	PValue    int
+	Satisfied bool
+	SchemaID  string
+	CredDefID string
+}
+
+// RestrictionCheck is the result of checking the credential used for the
+// referent against the restrictions of the proof request, see Filter.Match.
+// Referents without restrictions are always compliant.
+type RestrictionCheck struct {
+	Compliant bool
+	SchemaID  string
+	CredDefID string
+}
+
+// VerifyOptions are the options of VerifyProofWith.
+type VerifyOptions struct {
+	// Encoder is the name of the registered encoder which the revealed raw
+	// values must encode to their encoded values with. Empty means
+	// EncoderAries.
+	Encoder string
+
+	// CredDefEncoders has the encoders by the cred def IDs of the issuers
+	// which don't use Encoder.
+	CredDefEncoders map[string]string
+}
+
+/*
+VerifyProof verifies the proof against the original proof request. It resolves
+the schemas, cred defs and revocation registries by Proof.Identifiers from the
+ledger plugins with the submitter DID, calls VerifierVerifyProof, and explains
+the result in the returned ProofReport.
+
+In addition to libindy's verification, VerifyProof checks that all of the
+referents are in the proof, the credentials used comply with the restrictions
+of the proof request, and the revealed raw values match their encoded values
+with EncoderAries. See VerifyProofWith for other encoders.
+
+The error is returned only if the verification cannot be run, e.g. the ledger
+cannot be read. Verification failures are in the report.
+
+	report, err := anoncreds.VerifyProof(pool, myDID, proofReq, proofJSON)
+	if err == nil && !report.Verified {
+		glog.Warningln("proof failed:", report.Failures)
+	}
+*/
+func VerifyProof(
+	pool int,
+	submitter string,
+	proofReq ProofRequest,
+	proofJSON string,
+) (report *ProofReport, err error) {
+	return VerifyProofWith(pool, submitter, proofReq, proofJSON, VerifyOptions{})
+}
+
+// VerifyProofWith verifies the proof like VerifyProof, but the raw values are
+// checked with the encoders of the options.
+func VerifyProofWith(
+	pool int,
+	submitter string,
+	proofReq ProofRequest,
+	proofJSON string,
+	opts VerifyOptions,
+) (report *ProofReport, err error) {
+	defer err2.Handle(&err, "verify proof")
+
+	var proof Proof
+	try.To(json.Unmarshal([]byte(proofJSON), &proof))
+
+	report = newProofReport(proofReq, proof, opts)
+	objects := NewLedgerObjects()
+	for _, id := range proof.Identifiers {
+		try.To(objects.Read(pool, submitter, id.SchemaID, id.CredDefID))
+		if id.RevRegID == "" {
+			continue
+		}
+		timestamp, tsErr := id.Timestamp.Int64()
+		if tsErr != nil {
+			report.fail("%s: no timestamp for revocation registry", id.RevRegID)
+			return report.done(), nil
+		}
+		try.To(objects.ReadRevocation(pool, submitter, id.RevRegID, timestamp))
+	}
+
+	r := <-VerifierVerifyProof(dto.ToJSON(proofReq), proofJSON,
+		objects.SchemasJSON(), objects.CredDefsJSON(),
+		objects.RevRegDefsJSON(), objects.RevRegsJSON())
+	switch {
+	case r.Err() != nil:
+		report.fail("libindy: %v", r.Err())
+	case !r.Yes():
+		report.fail("cryptographic verification failed")
+	}
+	report.Verified = r.Err() == nil && r.Yes()
+	report.done()
+	for ref, outcome := range report.Predicates {
+		outcome.Satisfied = report.Verified
+		report.Predicates[ref] = outcome
+	}
+	return report, nil
+}
+
+// newProofReport builds the report from the proof request and the proof
+// without the libindy verification.
+func newProofReport(proofReq ProofRequest, proof Proof, opts VerifyOptions) *ProofReport {
+	report := &ProofReport{
+		Revealed:     map[string]map[string]RevealedValue{},
+		Predicates:   map[string]PredicateOutcome{},
+		SelfAttested: map[string]string{},
+		Restrictions: map[string]RestrictionCheck{},
+		Failures:     make([]string, 0),
+	}
+	rp := proof.RequestedProof
+	proved := provedCredentials(proofReq, proof)
+
+	for ref, attrInfo := range proofReq.RequestedAttributes {
+		if value, ok := rp.SelfAttestedAttrs[ref]; ok {
+			report.SelfAttested[ref] = fmt.Sprint(value)
+			if len(attrInfo.Restrictions) > 0 {
+				report.fail("%s: self attested attribute has restrictions", ref)
+			}
+			continue
+		}
+		index, found := attrSubProofIndex(rp, ref)
+		if !found {
+			report.fail("%s: attribute is missing", ref)
+			continue
+		}
+		id, ok := identifier(proof, index)
+		if !ok {
+			report.fail("%s: no identifiers for sub proof %d", ref, index)
+			continue
+		}
+		report.checkRestrictions(ref, attrInfo.Restrictions, proved[index])
+		report.addRevealed(ref, attrInfo, rp, id, opts.encoderName(id.CredDefID))
+	}
+
+	for ref, predInfo := range proofReq.RequestedPredicates {
+		index, found := subProofIndex(rp.Predicates[ref])
+		if !found {
+			report.fail("%s: predicate is missing", ref)
+			continue
+		}
+		id, ok := identifier(proof, index)
+		if !ok {
+			report.fail("%s: no identifiers for sub proof %d", ref, index)
+			continue
+		}
+		report.checkRestrictions(ref, predInfo.Restrictions, proved[index])
+		report.Predicates[ref] = PredicateOutcome{
+			Name:      predInfo.Name,
+			PType:     predInfo.PType,
+			PValue:    predInfo.PValue,
+			SchemaID:  id.SchemaID,
+			CredDefID: id.CredDefID,
+		}
+	}
+	return report
+}
+
+func (report *ProofReport) addRevealed(
+	ref string,
+	attrInfo AttrInfo,
+	rp RequestedProof,
+	id IdentifiersObj,
+	encoderName string,
+) {
+	values := map[string]CredDefAttr{}
+	if attr, ok := rp.RevealedAttrs[ref]; ok {
+		values[attrInfo.Name] = CredDefAttr{Raw: attr.Raw, Encoded: attr.Encoded}
+	} else if group, ok := rp.RevealedAttrGroups[ref]; ok {
+		values = group.Values
+	}
+	if len(values) == 0 {
+		return // attribute is proved but not revealed
+	}
+	enc, ok := LookupEncoder(encoderName)
+	if !ok {
+		report.fail("%s: encoder %q is not registered", ref, encoderName)
+	}
+	revealed := make(map[string]RevealedValue, len(values))
+	for name, value := range values {
+		if ok && value.Encoded != enc(value.Raw) {
+			report.fail("%s: raw value of %s doesn't match its encoded value",
+				ref, name)
+		}
+		revealed[name] = RevealedValue{
+			Raw:       value.Raw,
+			Encoded:   value.Encoded,
+			SchemaID:  id.SchemaID,
+			CredDefID: id.CredDefID,
+		}
+	}
+	report.Revealed[ref] = revealed
+}
+
+// provedCredentials returns the credentials of the proof by their sub proof
+// indexes with the requested attribute names and the revealed values.
+func provedCredentials(proofReq ProofRequest, proof Proof) map[int]ProvedCredential {
+	rp := proof.RequestedProof
+	proved := make(map[int]ProvedCredential, len(proof.Identifiers))
+	credential := func(index int) (ProvedCredential, bool) {
+		cred, ok := proved[index]
+		if !ok {
+			id, found := identifier(proof, index)
+			if !found {
+				return cred, false
+			}
+			cred = ProvedCredential{
+				SchemaID:  id.SchemaID,
+				CredDefID: id.CredDefID,
+				Values:    map[string]string{},
+				Names:     map[string]bool{},
+			}
+			proved[index] = cred
+		}
+		return cred, true
+	}
+
+	for ref, attrInfo := range proofReq.RequestedAttributes {
+		index, found := attrSubProofIndex(rp, ref)
+		if !found {
+			continue
+		}
+		cred, ok := credential(index)
+		if !ok {
+			continue
+		}
+		for _, name := range append([]string{attrInfo.Name}, attrInfo.Names...) {
+			if name != "" {
+				cred.Names[normalizeAttrName(name)] = true
+			}
+		}
+		if attr, ok := rp.RevealedAttrs[ref]; ok {
+			cred.Values[normalizeAttrName(attrInfo.Name)] = attr.Raw
+		}
+		for name, value := range rp.RevealedAttrGroups[ref].Values {
+			cred.Values[normalizeAttrName(name)] = value.Raw
+		}
+	}
+	for ref, predInfo := range proofReq.RequestedPredicates {
+		index, found := subProofIndex(rp.Predicates[ref])
+		if !found {
+			continue
+		}
+		if cred, ok := credential(index); ok {
+			cred.Names[normalizeAttrName(predInfo.Name)] = true
+		}
+	}
+	return proved
+}
+
+func (report *ProofReport) checkRestrictions(ref string, restrictions []Filter, cred ProvedCredential) {
+	check := RestrictionCheck{
+		Compliant: len(restrictions) == 0,
+		SchemaID:  cred.SchemaID,
+		CredDefID: cred.CredDefID,
+	}
+	for _, f := range restrictions {
+		if f.Match(cred) {
+			check.Compliant = true
+			break
+		}
+	}
+	if !check.Compliant {
+		report.fail("%s: credential %s doesn't comply with restrictions",
+			ref, cred.CredDefID)
+	}
+	report.Restrictions[ref] = check
+}
+
+func (report *ProofReport) fail(format string, a ...any) {
+	report.Failures = append(report.Failures, fmt.Sprintf(format, a...))
+}
+
+func (report *ProofReport) done() *ProofReport {
+	sort.Strings(report.Failures)
+	report.Verified = report.Verified && len(report.Failures) == 0
+	return report
+}
+
+// encoderName returns the name of the encoder for the credentials of the cred
+// def.
+func (o VerifyOptions) encoderName(credDefID string) string {
+	if name, ok := o.CredDefEncoders[credDefID]; ok {
+		return name
+	}
+	if o.Encoder != "" {
+		return o.Encoder
+	}
+	return EncoderAries
+}
+
+func attrSubProofIndex(rp RequestedProof, ref string) (int, bool) {
+	if attr, ok := rp.RevealedAttrs[ref]; ok {
+		return attr.SubProofIndex, true
+	}
+	if group, ok := rp.RevealedAttrGroups[ref]; ok {
+		return group.SubProofIndex, true
+	}
+	return subProofIndex(rp.UnrevealedAttrs[ref])
+}
+
+// subProofIndex returns the sub_proof_index of the JSON object which is
+// unmarshalled to interface{}.
+func subProofIndex(v interface{}) (int, bool) {
+	obj, ok := v.(map[string]interface{})
+	if !ok {
+		return 0, false
+	}
+	index, ok := obj["sub_proof_index"].(float64)
+	return int(index), ok
+}
+
+func identifier(proof Proof, index int) (IdentifiersObj, bool) {
+	if index < 0 || index >= len(proof.Identifiers) {
+		return IdentifiersObj{}, false
+	}
+	return proof.Identifiers[index], true
+}
@@ -0,0 +1,221 @@
+package anoncreds
+
+import (
+	"testing"
+
+	"github.com/findy-network/findy-wrapper-go/dto"
+	"github.com/lainio/err2/assert"
+)
+
+const (
+	testSchemaID  = "5Lgx4KLRTNgexDqT7WDALu:2:Education:1.0"
+	testCredDefID = "5Lgx4KLRTNgexDqT7WDALu:3:CL:14:default"
+)
+
+var testProofJSON = `{
+  "requested_proof": {
+    "revealed_attrs": {
+      "0_name_uuid": {"sub_proof_index": 0, "raw": "Alice Smith",
+        "encoded": "62816810226936654797779705000772968058283780124309077049681734835796332704413"},
+      "0_date_uuid": {"sub_proof_index": 0, "raw": "2018-05-28", "encoded": "12345"}
+    },
+    "revealed_attr_groups": {
+      "0_group_uuid": {"sub_proof_index": 0, "values": {
+        "degree": {"raw": "3", "encoded": "3"},
+        "age": {"raw": "25", "encoded": "25"}
+      }}
+    },
+    "unrevealed_attrs": {"0_hidden_uuid": {"sub_proof_index": 0}},
+    "self_attested_attrs": {"0_self_attested_thing_uuid": "my self-attested value"},
+    "predicates": {"0_age_GE_uuid": {"sub_proof_index": 0}}
+  },
+  "proof": {},
+  "identifiers": [{
+    "schema_id": "5Lgx4KLRTNgexDqT7WDALu:2:Education:1.0",
+    "cred_def_id": "5Lgx4KLRTNgexDqT7WDALu:3:CL:14:default"
+  }]
+}`
+
+func testProofRequest(issuer string) ProofRequest {
+	restrictions := []Filter{{IssuerDID: issuer}}
+	return ProofRequest{
+		Name:    "Proof of Education",
+		Version: "1.0",
+		Nonce:   "1234567890",
+		RequestedAttributes: map[string]AttrInfo{
+			"0_name_uuid":                {Name: "name", Restrictions: restrictions},
+			"0_date_uuid":                {Name: "date"},
+			"0_group_uuid":               {Names: []string{"degree", "age"}, Restrictions: restrictions},
+			"0_hidden_uuid":              {Name: "hidden"},
+			"0_self_attested_thing_uuid": {Name: "self_attested_thing"},
+		},
+		RequestedPredicates: map[string]PredicateInfo{
+			"0_age_GE_uuid": {Name: "age", PType: ">=", PValue: 18, Restrictions: restrictions},
+		},
+	}
+}
+
+func TestNewProofReport(t *testing.T) {
+	assert.PushTester(t)
+	defer assert.PopTester()
+
+	var proof Proof
+	dto.FromJSONStr(testProofJSON, &proof)
+	report := newProofReport(testProofRequest("5Lgx4KLRTNgexDqT7WDALu"), proof, VerifyOptions{})
+	report.Verified = true
+	report.done()
+
+	assert.ThatNot(report.Verified)
+	assert.SLen(report.Failures, 1)
+	assert.Equal(report.Failures[0],
+		"0_date_uuid: raw value of date doesn't match its encoded value")
+
+	assert.MLen(report.Revealed, 3)
+	assert.Equal(report.Revealed["0_name_uuid"]["name"].Raw, "Alice Smith")
+	assert.Equal(report.Revealed["0_name_uuid"]["name"].CredDefID, testCredDefID)
+	assert.MLen(report.Revealed["0_group_uuid"], 2)
+	assert.Equal(report.Revealed["0_group_uuid"]["age"].Raw, "25")
+	assert.Equal(report.SelfAttested["0_self_attested_thing_uuid"], "my self-attested value")
+	assert.Equal(report.Predicates["0_age_GE_uuid"].PType, ">=")
+	assert.Equal(report.Predicates["0_age_GE_uuid"].PValue, 18)
+	assert.That(report.Restrictions["0_name_uuid"].Compliant)
+	assert.That(report.Restrictions["0_hidden_uuid"].Compliant)
+}
+
+func TestNewProofReport_restrictions(t *testing.T) {
+	assert.PushTester(t)
+	defer assert.PopTester()
+
+	var proof Proof
+	dto.FromJSONStr(testProofJSON, &proof)
+	proofReq := testProofRequest("NcYxiDXkpYi6ov5FcYDi1e")
+	delete(proofReq.RequestedAttributes, "0_date_uuid")
+	delete(proofReq.RequestedAttributes, "0_hidden_uuid")
+	proofReq.RequestedAttributes["0_missing_uuid"] = AttrInfo{Name: "missing"}
+	report := newProofReport(proofReq, proof, VerifyOptions{}).done()
+
+	assert.ThatNot(report.Verified)
+	assert.DeepEqual(report.Failures, []string{
+		"0_age_GE_uuid: credential " + testCredDefID + " doesn't comply with restrictions",
+		"0_group_uuid: credential " + testCredDefID + " doesn't comply with restrictions",
+		"0_missing_uuid: attribute is missing",
+		"0_name_uuid: credential " + testCredDefID + " doesn't comply with restrictions",
+	})
+	assert.ThatNot(report.Restrictions["0_name_uuid"].Compliant)
+	assert.Equal(report.Restrictions["0_name_uuid"].SchemaID, testSchemaID)
+}
+
+func TestNewProofReport_attrRestrictions(t *testing.T) {
+	assert.PushTester(t)
+	defer assert.PopTester()
+
+	var proof Proof
+	dto.FromJSONStr(testProofJSON, &proof)
+	proofReq := testProofRequest("5Lgx4KLRTNgexDqT7WDALu")
+	delete(proofReq.RequestedAttributes, "0_date_uuid")
+	name := proofReq.RequestedAttributes["0_name_uuid"]
+	name.Restrictions = []Filter{{AttrValues: map[string]string{"name": "Alice Smith"}}}
+	proofReq.RequestedAttributes["0_name_uuid"] = name
+	group := proofReq.RequestedAttributes["0_group_uuid"]
+	group.Restrictions = []Filter{{AttrValues: map[string]string{"degree": "4"}}}
+	proofReq.RequestedAttributes["0_group_uuid"] = group
+	pred := proofReq.RequestedPredicates["0_age_GE_uuid"]
+	pred.Restrictions = []Filter{{AttrMarkers: []string{"hidden"}}}
+	proofReq.RequestedPredicates["0_age_GE_uuid"] = pred
+	proofReq.RequestedAttributes["0_other_uuid"] = AttrInfo{Name: "hidden",
+		Restrictions: []Filter{{AttrMarkers: []string{"email"}}}}
+	proof.RequestedProof.UnrevealedAttrs = map[string]interface{}{
+		"0_hidden_uuid": map[string]interface{}{"sub_proof_index": 0.0},
+		"0_other_uuid":  map[string]interface{}{"sub_proof_index": 0.0},
+	}
+	report := newProofReport(proofReq, proof, VerifyOptions{}).done()
+
+	assert.DeepEqual(report.Failures, []string{
+		"0_group_uuid: credential " + testCredDefID + " doesn't comply with restrictions",
+		"0_other_uuid: credential " + testCredDefID + " doesn't comply with restrictions",
+	})
+	assert.That(report.Restrictions["0_name_uuid"].Compliant)
+	assert.That(report.Restrictions["0_age_GE_uuid"].Compliant)
+	assert.ThatNot(report.Restrictions["0_group_uuid"].Compliant)
+}
+
+func TestNewProofReport_encoders(t *testing.T) {
+	assert.PushTester(t)
+	defer assert.PopTester()
+
+	var proof Proof
+	dto.FromJSONStr(testProofJSON, &proof)
+	proofReq := testProofRequest("5Lgx4KLRTNgexDqT7WDALu")
+
+	// registered encoders don't affect the verification of other issuers
+	RegisterEncoder("test-date", func(string) string { return "12345" })
+	defer UnregisterEncoder("test-date")
+	report := newProofReport(proofReq, proof, VerifyOptions{}).done()
+	assert.DeepEqual(report.Failures, []string{
+		"0_date_uuid: raw value of date doesn't match its encoded value",
+	})
+
+	report = newProofReport(proofReq, proof, VerifyOptions{
+		CredDefEncoders: map[string]string{testCredDefID: "unknown"},
+	}).done()
+	assert.DeepEqual(report.Failures, []string{
+		`0_date_uuid: encoder "unknown" is not registered`,
+		`0_group_uuid: encoder "unknown" is not registered`,
+		`0_name_uuid: encoder "unknown" is not registered`,
+	})
+}
+
+func TestProof_timestamp(t *testing.T) {
+	assert.PushTester(t)
+	defer assert.PopTester()
+
+	var proof Proof
+	dto.FromJSONStr(`{"identifiers": [{"schema_id": "`+testSchemaID+
+		`", "cred_def_id": "`+testCredDefID+
+		`", "rev_reg_id": "rr1", "timestamp": 1650000000}]}`, &proof)
+	timestamp, err := proof.Identifiers[0].Timestamp.Int64()
+	assert.NoError(err)
+	assert.Equal(timestamp, int64(1650000000))
+}
+
+func TestFilter_Match(t *testing.T) {
+	assert.PushTester(t)
+	defer assert.PopTester()
+
+	tests := []struct {
+		name   string
+		filter Filter
+		want   bool
+	}{
+		{"empty", Filter{}, true},
+		{"schema ID", Filter{SchemaID: testSchemaID}, true},
+		{"schema issuer", Filter{SchemaIssuerDID: "5Lgx4KLRTNgexDqT7WDALu"}, true},
+		{"schema name", Filter{SchemaName: "Education"}, true},
+		{"wrong schema name", Filter{SchemaName: "Email"}, false},
+		{"issuer", Filter{IssuerDID: "5Lgx4KLRTNgexDqT7WDALu"}, true},
+		{"wrong issuer", Filter{IssuerDID: "NcYxiDXkpYi6ov5FcYDi1e"}, false},
+		{"cred def", Filter{CredDefID: testCredDefID, SchemaName: "Education"}, true},
+		{"cred def and wrong name", Filter{CredDefID: testCredDefID, SchemaName: "Email"}, false},
+		{"qualified issuer", Filter{IssuerDID: "did:sov:5Lgx4KLRTNgexDqT7WDALu"}, true},
+		{"qualified schema ID", Filter{SchemaID: "schema:sov:did:sov:" + testSchemaID}, true},
+		{"did:indy cred def", Filter{CredDefID: "did:indy:sovrin:5Lgx4KLRTNgexDqT7WDALu" +
+			"/anoncreds/v0/CLAIM_DEF/14/default"}, true},
+		{"value", Filter{AttrValues: map[string]string{"First Name": "Alice"}}, true},
+		{"wrong value", Filter{AttrValues: map[string]string{"firstname": "Bob"}}, false},
+		{"value not revealed", Filter{AttrValues: map[string]string{"age": "25"}}, false},
+		{"marker", Filter{AttrMarkers: []string{"age"}}, true},
+		{"missing marker", Filter{AttrMarkers: []string{"email"}}, false},
+	}
+	cred := ProvedCredential{
+		SchemaID:  testSchemaID,
+		CredDefID: testCredDefID,
+		Values:    map[string]string{"firstname": "Alice"},
+		Names:     map[string]bool{"firstname": true, "age": true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			defer assert.PushTester(t)()
+			assert.Equal(tt.filter.Match(cred), tt.want)
+		})
+	}
+}
@@ -0,0 +1,389 @@
+package anoncreds
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/findy-network/findy-wrapper-go/dto"
+	"github.com/findy-network/findy-wrapper-go/identifiers"
+	"github.com/lainio/err2"
+	"github.com/lainio/err2/try"
+)
+
+// JSON-LD contexts and types of the W3C documents.
+const (
+	W3CContextV2         = "https://www.w3.org/ns/credentials/v2"
+	AnonCredsW3CContext  = "https://raw.githubusercontent.com/hyperledger/anoncreds-spec/main/data/anoncreds-w3c-context.json"
+	TypeVC               = "VerifiableCredential"
+	TypeVP               = "VerifiablePresentation"
+	TypeDataIntegrity    = "DataIntegrityProof"
+	TypeAnonCredsDef     = "AnonCredsDefinition"
+	AnonCredsCryptosuite = "anoncreds-2023"
+)
+
+// VerifiableCredential is a W3C VC data model document of an Indy credential
+// or of a sub proof of an Indy proof.
+type VerifiableCredential struct {
+	Context           []string           `json:"@context"`
+	Type              []string           `json:"type"`
+	Issuer            string             `json:"issuer"`
+	CredentialSchema  CredentialSchema   `json:"credentialSchema"`
+	CredentialSubject map[string]string  `json:"credentialSubject"`
+	Proof             DataIntegrityProof `json:"proof"`
+}
+
+// VerifiablePresentation is a W3C VC data model presentation of an Indy proof.
+type VerifiablePresentation struct {
+	Context              []string               `json:"@context"`
+	Type                 []string               `json:"type"`
+	VerifiableCredential []VerifiableCredential `json:"verifiableCredential"`
+	Proof                DataIntegrityProof     `json:"proof"`
+}
+
+// CredentialSchema has the Indy identifiers of the credential. Revocation is
+// the revocation registry ID of the revocable credentials, and Timestamp is
+// the time of the registry state which the sub proof of the presentation is
+// made against.
+type CredentialSchema struct {
+	Type       string      `json:"type"`
+	Definition string      `json:"definition"`
+	Schema     string      `json:"schema"`
+	Revocation string      `json:"revocation,omitempty"`
+	Timestamp  json.Number `json:"timestamp,omitempty"`
+}
+
+// DataIntegrityProof carries the anoncreds signature or proof. ProofValue is
+// the base64url encoded JSON of the libindy data which isn't in the other
+// fields of the document.
+type DataIntegrityProof struct {
+	Type               string `json:"type"`
+	Cryptosuite        string `json:"cryptosuite"`
+	ProofPurpose       string `json:"proofPurpose"`
+	VerificationMethod string `json:"verificationMethod,omitempty"`
+	Challenge          string `json:"challenge,omitempty"`
+	ProofValue         string `json:"proofValue"`
+}
+
+// indyCredential is the credential JSON of IssuerCreateCredential.
+type indyCredential struct {
+	SchemaID                  string                 `json:"schema_id"`
+	CredDefID                 string                 `json:"cred_def_id"`
+	RevRegID                  *string                `json:"rev_reg_id"`
+	Values                    map[string]CredDefAttr `json:"values"`
+	Signature                 json.RawMessage        `json:"signature"`
+	SignatureCorrectnessProof json.RawMessage        `json:"signature_correctness_proof"`
+	RevReg                    json.RawMessage        `json:"rev_reg"`
+	Witness                   json.RawMessage        `json:"witness"`
+}
+
+// credentialSignature is the proof value of the VC. The encoded values are
+// needed, because the credential might not be encoded with the default
+// encoder.
+type credentialSignature struct {
+	Signature                 json.RawMessage   `json:"signature"`
+	SignatureCorrectnessProof json.RawMessage   `json:"signature_correctness_proof"`
+	RevReg                    json.RawMessage   `json:"rev_reg"`
+	Witness                   json.RawMessage   `json:"witness"`
+	Encoded                   map[string]string `json:"encoded"`
+}
+
+// presentationProof is the proof value of the VP. Raw values of the requested
+// proof are in the credential subjects and they are left out.
+type presentationProof struct {
+	AggregatedProof json.RawMessage `json:"aggregated_proof"`
+	RequestedProof  RequestedProof  `json:"requested_proof"`
+}
+
+/*
+ToW3CCredential converts the stored credential to a W3C verifiable credential.
+The info is the credential info of the wallet, e.g. from ProverGetCredential,
+and the credJSON is the credential from IssuerCreateCredential. The raw values
+of them must match.
+
+	vc, err := anoncreds.ToW3CCredential(info, credJSON)
+	vcJSON := dto.ToJSON(vc)
+*/
+func ToW3CCredential(info CredentialInfo, credJSON string) (vc *VerifiableCredential, err error) {
+	defer err2.Handle(&err, "to W3C credential")
+
+	var cred indyCredential
+	try.To(json.Unmarshal([]byte(credJSON), &cred))
+	if cred.SchemaID != info.SchemaID || cred.CredDefID != info.CredDefID {
+		return nil, errors.New("credential info doesn't match credential")
+	}
+
+	subject := make(map[string]string, len(cred.Values))
+	encoded := make(map[string]string, len(cred.Values))
+	for name, value := range cred.Values {
+		if raw, ok := info.Attrs[name]; ok && raw != value.Raw {
+			return nil, fmt.Errorf("raw value of %s mismatch", name)
+		}
+		subject[name] = value.Raw
+		encoded[name] = value.Encoded
+	}
+	var revRegID string
+	if cred.RevRegID != nil {
+		revRegID = *cred.RevRegID
+	}
+
+	return &VerifiableCredential{
+		Context:           []string{W3CContextV2, AnonCredsW3CContext},
+		Type:              []string{TypeVC},
+		Issuer:            issuerOf(cred.CredDefID),
+		CredentialSchema:  newCredentialSchema(cred.SchemaID, cred.CredDefID, revRegID),
+		CredentialSubject: subject,
+		Proof: DataIntegrityProof{
+			Type:               TypeDataIntegrity,
+			Cryptosuite:        AnonCredsCryptosuite,
+			ProofPurpose:       "assertionMethod",
+			VerificationMethod: cred.CredDefID,
+			ProofValue: encodeProofValue(credentialSignature{
+				Signature:                 cred.Signature,
+				SignatureCorrectnessProof: cred.SignatureCorrectnessProof,
+				RevReg:                    cred.RevReg,
+				Witness:                   cred.Witness,
+				Encoded:                   encoded,
+			}),
+		},
+	}, nil
+}
+
+// FromW3CCredential converts the W3C verifiable credential back to the
+// credential JSON of libindy, e.g. for ProverStoreCredential.
+func FromW3CCredential(vc VerifiableCredential) (credJSON string, err error) {
+	defer err2.Handle(&err, "from W3C credential")
+
+	try.To(vc.checkProof("assertionMethod"))
+	var sig credentialSignature
+	try.To(decodeProofValue(vc.Proof.ProofValue, &sig))
+
+	values := make(map[string]CredDefAttr, len(vc.CredentialSubject))
+	for name, raw := range vc.CredentialSubject {
+		encoded, ok := sig.Encoded[name]
+		if !ok {
+			return "", fmt.Errorf("%s: no encoded value", name)
+		}
+		values[name] = CredDefAttr{Raw: raw, Encoded: encoded}
+	}
+	if len(values) != len(sig.Encoded) {
+		return "", errors.New("credential subject doesn't match signed values")
+	}
+	cred := indyCredential{
+		SchemaID:                  vc.CredentialSchema.Schema,
+		CredDefID:                 vc.CredentialSchema.Definition,
+		Values:                    values,
+		Signature:                 sig.Signature,
+		SignatureCorrectnessProof: sig.SignatureCorrectnessProof,
+		RevReg:                    sig.RevReg,
+		Witness:                   sig.Witness,
+	}
+	if vc.CredentialSchema.Revocation != "" {
+		cred.RevRegID = &vc.CredentialSchema.Revocation
+	}
+	return dto.ToJSON(cred), nil
+}
+
+/*
+ToW3CPresentation converts the proof of the proof request to a W3C verifiable
+presentation. Every sub proof is presented as a verifiable credential which
+has the revealed attributes of it. The revocation registry ID and the
+timestamp of the sub proof are in the credential schema of the credential.
+
+	vp, err := anoncreds.ToW3CPresentation(proofReq, proofJSON)
+*/
+func ToW3CPresentation(proofReq ProofRequest, proofJSON string) (vp *VerifiablePresentation, err error) {
+	defer err2.Handle(&err, "to W3C presentation")
+
+	var proof Proof
+	try.To(json.Unmarshal([]byte(proofJSON), &proof))
+	var indyProof struct {
+		Proofs          []json.RawMessage `json:"proofs"`
+		AggregatedProof json.RawMessage   `json:"aggregated_proof"`
+	}
+	try.To(json.Unmarshal([]byte(dto.ToJSON(proof.Proof)), &indyProof))
+	if len(indyProof.Proofs) != len(proof.Identifiers) {
+		return nil, errors.New("sub proofs don't match identifiers")
+	}
+
+	subjects := make([]map[string]string, len(proof.Identifiers))
+	for i := range subjects {
+		subjects[i] = map[string]string{}
+	}
+	rp := proof.RequestedProof
+	for ref, attr := range rp.RevealedAttrs {
+		info, ok := proofReq.RequestedAttributes[ref]
+		if !ok {
+			return nil, fmt.Errorf("%s: not in proof request", ref)
+		}
+		if attr.SubProofIndex < 0 || attr.SubProofIndex >= len(subjects) {
+			return nil, fmt.Errorf("%s: sub proof index out of range", ref)
+		}
+		subjects[attr.SubProofIndex][info.Name] = attr.Raw
+		attr.Raw = ""
+		rp.RevealedAttrs[ref] = attr
+	}
+	for ref, group := range rp.RevealedAttrGroups {
+		if group.SubProofIndex < 0 || group.SubProofIndex >= len(subjects) {
+			return nil, fmt.Errorf("%s: sub proof index out of range", ref)
+		}
+		for name, value := range group.Values {
+			subjects[group.SubProofIndex][name] = value.Raw
+			group.Values[name] = CredDefAttr{Encoded: value.Encoded}
+		}
+	}
+
+	vp = &VerifiablePresentation{
+		Context:              []string{W3CContextV2, AnonCredsW3CContext},
+		Type:                 []string{TypeVP},
+		VerifiableCredential: make([]VerifiableCredential, len(proof.Identifiers)),
+		Proof: DataIntegrityProof{
+			Type:         TypeDataIntegrity,
+			Cryptosuite:  AnonCredsCryptosuite,
+			ProofPurpose: "authentication",
+			Challenge:    proofReq.Nonce,
+			ProofValue: encodeProofValue(presentationProof{
+				AggregatedProof: indyProof.AggregatedProof,
+				RequestedProof:  rp,
+			}),
+		},
+	}
+	for i, id := range proof.Identifiers {
+		if id.RevRegID != "" && id.Timestamp == "" {
+			return nil, fmt.Errorf("%s: no timestamp for revocation registry", id.RevRegID)
+		}
+		schema := newCredentialSchema(id.SchemaID, id.CredDefID, id.RevRegID)
+		schema.Timestamp = id.Timestamp
+		vp.VerifiableCredential[i] = VerifiableCredential{
+			Context:           []string{W3CContextV2, AnonCredsW3CContext},
+			Type:              []string{TypeVC},
+			Issuer:            issuerOf(id.CredDefID),
+			CredentialSchema:  schema,
+			CredentialSubject: subjects[i],
+			Proof: DataIntegrityProof{
+				Type:               TypeDataIntegrity,
+				Cryptosuite:        AnonCredsCryptosuite,
+				ProofPurpose:       "assertionMethod",
+				VerificationMethod: id.CredDefID,
+				ProofValue:         base64.RawURLEncoding.EncodeToString(indyProof.Proofs[i]),
+			},
+		}
+	}
+	return vp, nil
+}
+
+/*
+FromW3CPresentation converts the W3C verifiable presentation back to the proof
+JSON of libindy for VerifierVerifyProof or VerifyProof. The raw values are
+taken from the credential subjects. The challenge of the presentation must be
+the nonce of the proof request.
+
+	proofJSON, err := anoncreds.FromW3CPresentation(proofReq, vp)
+	report, err := anoncreds.VerifyProof(pool, myDID, proofReq, proofJSON)
+*/
+func FromW3CPresentation(proofReq ProofRequest, vp VerifiablePresentation) (proofJSON string, err error) {
+	defer err2.Handle(&err, "from W3C presentation")
+
+	if vp.Proof.Type != TypeDataIntegrity || vp.Proof.Cryptosuite != AnonCredsCryptosuite {
+		return "", fmt.Errorf("unsupported proof %s/%s", vp.Proof.Type, vp.Proof.Cryptosuite)
+	}
+	if vp.Proof.Challenge != proofReq.Nonce {
+		return "", errors.New("challenge doesn't match nonce of proof request")
+	}
+	var pp presentationProof
+	try.To(decodeProofValue(vp.Proof.ProofValue, &pp))
+
+	proof := Proof{
+		RequestedProof: pp.RequestedProof,
+		Identifiers:    make([]IdentifiersObj, len(vp.VerifiableCredential)),
+	}
+	subProofs := make([]json.RawMessage, len(vp.VerifiableCredential))
+	for i, vc := range vp.VerifiableCredential {
+		try.To(vc.checkProof("assertionMethod"))
+		subProofs[i] = try.To1(base64.RawURLEncoding.DecodeString(vc.Proof.ProofValue))
+		proof.Identifiers[i] = IdentifiersObj{
+			SchemaID:  vc.CredentialSchema.Schema,
+			CredDefID: vc.CredentialSchema.Definition,
+			RevRegID:  vc.CredentialSchema.Revocation,
+			Timestamp: vc.CredentialSchema.Timestamp,
+		}
+	}
+
+	rp := proof.RequestedProof
+	for ref, attr := range rp.RevealedAttrs {
+		info := proofReq.RequestedAttributes[ref]
+		attr.Raw = try.To1(subjectValue(vp, attr.SubProofIndex, info.Name))
+		rp.RevealedAttrs[ref] = attr
+	}
+	for _, group := range rp.RevealedAttrGroups {
+		for name, value := range group.Values {
+			value.Raw = try.To1(subjectValue(vp, group.SubProofIndex, name))
+			group.Values[name] = value
+		}
+	}
+	proof.Proof = map[string]interface{}{
+		"proofs":           subProofs,
+		"aggregated_proof": pp.AggregatedProof,
+	}
+	return dto.ToJSON(proof), nil
+}
+
+// issuerOf returns the did:sov DID of the cred def's issuer, or the DID as it
+// is in the cred def ID if the ID is already qualified.
+func issuerOf(credDefID string) string {
+	id, err := identifiers.ParseCredDefID(credDefID)
+	if err != nil {
+		return ""
+	}
+	if id.DID.Method == identifiers.Unqualified {
+		return id.DID.Sov().String()
+	}
+	return id.DID.String()
+}
+
+func newCredentialSchema(schemaID, credDefID, revRegID string) CredentialSchema {
+	return CredentialSchema{
+		Type:       TypeAnonCredsDef,
+		Definition: credDefID,
+		Schema:     schemaID,
+		Revocation: revRegID,
+	}
+}
+
+func (vc VerifiableCredential) checkProof(purpose string) error {
+	p := vc.Proof
+	if p.Type != TypeDataIntegrity || p.Cryptosuite != AnonCredsCryptosuite {
+		return fmt.Errorf("unsupported proof %s/%s", p.Type, p.Cryptosuite)
+	}
+	if p.ProofPurpose != purpose {
+		return fmt.Errorf("proof purpose must be %s", purpose)
+	}
+	if p.VerificationMethod != vc.CredentialSchema.Definition {
+		return errors.New("verification method isn't the cred def")
+	}
+	return nil
+}
+
+func subjectValue(vp VerifiablePresentation, index int, name string) (string, error) {
+	if index < 0 || index >= len(vp.VerifiableCredential) {
+		return "", fmt.Errorf("%s: sub proof index %d out of range", name, index)
+	}
+	raw, ok := vp.VerifiableCredential[index].CredentialSubject[name]
+	if !ok {
+		return "", fmt.Errorf("%s: not in credential subject %d", name, index)
+	}
+	return raw, nil
+}
+
+func encodeProofValue(v any) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(dto.ToJSON(v)))
+}
+
+func decodeProofValue(s string, v any) error {
+	data, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return fmt.Errorf("proof value: %w", err)
+	}
+	return json.Unmarshal(data, v)
+}
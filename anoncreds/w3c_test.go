@@ -0,0 +1,184 @@
+package anoncreds
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/findy-network/findy-wrapper-go/dto"
+	"github.com/lainio/err2/assert"
+)
+
+var testCredJSON = `{
+  "schema_id": "5Lgx4KLRTNgexDqT7WDALu:2:Education:1.0",
+  "cred_def_id": "5Lgx4KLRTNgexDqT7WDALu:3:CL:14:default",
+  "rev_reg_id": null,
+  "values": {
+    "name": {"raw": "Alice Smith",
+      "encoded": "62816810226936654797779705000772968058283780124309077049681734835796332704413"},
+    "age": {"raw": "25", "encoded": "25"}
+  },
+  "signature": {"p_credential": {"m_2": "1234", "a": "5678", "e": "9", "v": "10"}, "r_credential": null},
+  "signature_correctness_proof": {"se": "11", "c": "12"},
+  "rev_reg": null,
+  "witness": null
+}`
+
+func TestToW3CCredential(t *testing.T) {
+	defer assert.PushTester(t)()
+
+	info := CredentialInfo{
+		Referent:  "cred-1",
+		Attrs:     map[string]string{"name": "Alice Smith", "age": "25"},
+		SchemaID:  testSchemaID,
+		CredDefID: testCredDefID,
+	}
+	vc, err := ToW3CCredential(info, testCredJSON)
+	assert.NoError(err)
+	assert.Equal(vc.Issuer, "did:sov:5Lgx4KLRTNgexDqT7WDALu")
+	assert.Equal(vc.CredentialSchema.Definition, testCredDefID)
+	assert.Equal(vc.CredentialSubject["name"], "Alice Smith")
+	assert.Equal(vc.Proof.VerificationMethod, testCredDefID)
+
+	var fromJSON VerifiableCredential
+	dto.FromJSONStr(dto.ToJSON(vc), &fromJSON)
+	credJSON, err := FromW3CCredential(fromJSON)
+	assert.NoError(err)
+	assertJSONEqual(t, credJSON, testCredJSON)
+
+	fromJSON.CredentialSubject["name"] = "Mallory"
+	credJSON, err = FromW3CCredential(fromJSON)
+	assert.NoError(err, "tampering is caught by the signature")
+	tampered := credValue(credJSON, "name")
+	assert.NotEqual(Encode(tampered.Raw), tampered.Encoded)
+
+	delete(fromJSON.CredentialSubject, "age")
+	_, err = FromW3CCredential(fromJSON)
+	assert.Error(err)
+
+	info.Attrs["age"] = "26"
+	_, err = ToW3CCredential(info, testCredJSON)
+	assert.Error(err)
+	info.CredDefID = "other"
+	_, err = ToW3CCredential(info, testCredJSON)
+	assert.Error(err)
+}
+
+func TestToW3CPresentation(t *testing.T) {
+	defer assert.PushTester(t)()
+
+	var proof Proof
+	dto.FromJSONStr(testProofJSON, &proof)
+	proof.Proof = map[string]interface{}{
+		"proofs":           []interface{}{map[string]interface{}{"primary_proof": "sub proof 0"}},
+		"aggregated_proof": map[string]interface{}{"c_hash": "13", "c_list": []interface{}{}},
+	}
+	proofJSON := dto.ToJSON(proof)
+	proofReq := testProofRequest("5Lgx4KLRTNgexDqT7WDALu")
+
+	vp, err := ToW3CPresentation(proofReq, proofJSON)
+	assert.NoError(err)
+	assert.SLen(vp.VerifiableCredential, 1)
+	assert.Equal(vp.Proof.Challenge, proofReq.Nonce)
+	subject := vp.VerifiableCredential[0].CredentialSubject
+	assert.MLen(subject, 4)
+	assert.Equal(subject["name"], "Alice Smith")
+	assert.Equal(subject["degree"], "3")
+
+	var fromJSON VerifiablePresentation
+	dto.FromJSONStr(dto.ToJSON(vp), &fromJSON)
+	back, err := FromW3CPresentation(proofReq, fromJSON)
+	assert.NoError(err)
+	assertJSONEqual(t, back, proofJSON)
+
+	proofReq.Nonce = "other"
+	_, err = FromW3CPresentation(proofReq, fromJSON)
+	assert.Error(err)
+}
+
+func TestToW3CPresentation_revocation(t *testing.T) {
+	defer assert.PushTester(t)()
+
+	const revRegID = "5Lgx4KLRTNgexDqT7WDALu:4:" + testCredDefID + ":CL_ACCUM:default"
+	var proof Proof
+	dto.FromJSONStr(testProofJSON, &proof)
+	proof.Identifiers[0].RevRegID = revRegID
+	proof.Identifiers[0].Timestamp = "1650000000"
+	proof.Proof = map[string]interface{}{
+		"proofs": []interface{}{map[string]interface{}{
+			"primary_proof":   "sub proof 0",
+			"non_revoc_proof": map[string]interface{}{"x_list": "1", "c_list": "2"},
+		}},
+		"aggregated_proof": map[string]interface{}{"c_hash": "13", "c_list": []interface{}{}},
+	}
+	proofJSON := dto.ToJSON(proof)
+	proofReq := testProofRequest("5Lgx4KLRTNgexDqT7WDALu")
+
+	vp, err := ToW3CPresentation(proofReq, proofJSON)
+	assert.NoError(err)
+	schema := vp.VerifiableCredential[0].CredentialSchema
+	assert.Equal(schema.Revocation, revRegID)
+	assert.Equal(schema.Timestamp, json.Number("1650000000"))
+
+	var fromJSON VerifiablePresentation
+	dto.FromJSONStr(dto.ToJSON(vp), &fromJSON)
+	back, err := FromW3CPresentation(proofReq, fromJSON)
+	assert.NoError(err)
+	assertJSONEqual(t, back, proofJSON)
+	var backProof Proof
+	dto.FromJSONStr(back, &backProof)
+	assert.Equal(backProof.Identifiers[0].RevRegID, revRegID)
+	timestamp, err := backProof.Identifiers[0].Timestamp.Int64()
+	assert.NoError(err)
+	assert.Equal(timestamp, int64(1650000000))
+
+	proof.Identifiers[0].Timestamp = ""
+	_, err = ToW3CPresentation(proofReq, dto.ToJSON(proof))
+	assert.Error(err)
+}
+
+func TestToW3CPresentation_subProofIndex(t *testing.T) {
+	defer assert.PushTester(t)()
+
+	proofReq := testProofRequest("5Lgx4KLRTNgexDqT7WDALu")
+	for _, index := range []int{-1, 1} {
+		var proof Proof
+		dto.FromJSONStr(testProofJSON, &proof)
+		proof.Proof = map[string]interface{}{
+			"proofs": []interface{}{map[string]interface{}{}},
+		}
+		attr := proof.RequestedProof.RevealedAttrs["0_name_uuid"]
+		attr.SubProofIndex = index
+		proof.RequestedProof.RevealedAttrs["0_name_uuid"] = attr
+		_, err := ToW3CPresentation(proofReq, dto.ToJSON(proof))
+		assert.Error(err)
+
+		proof = Proof{}
+		dto.FromJSONStr(testProofJSON, &proof)
+		proof.Proof = map[string]interface{}{
+			"proofs": []interface{}{map[string]interface{}{}},
+		}
+		group := proof.RequestedProof.RevealedAttrGroups["0_group_uuid"]
+		group.SubProofIndex = index
+		proof.RequestedProof.RevealedAttrGroups["0_group_uuid"] = group
+		_, err = ToW3CPresentation(proofReq, dto.ToJSON(proof))
+		assert.Error(err)
+	}
+
+	vp := VerifiablePresentation{VerifiableCredential: make([]VerifiableCredential, 1)}
+	_, err := subjectValue(vp, -1, "name")
+	assert.Error(err)
+}
+
+func credValue(credJSON, name string) CredDefAttr {
+	var cred indyCredential
+	dto.FromJSONStr(credJSON, &cred)
+	return cred.Values[name]
+}
+
+func assertJSONEqual(t *testing.T, got, want string) {
+	t.Helper()
+	var g, w interface{}
+	assert.NoError(json.Unmarshal([]byte(got), &g))
+	assert.NoError(json.Unmarshal([]byte(want), &w))
+	assert.DeepEqual(g, w)
+}
@@ -0,0 +1,95 @@
+package crypto
+
+import (
+	"runtime"
+	"sync"
+
+	"github.com/findy-network/findy-wrapper-go/dto"
+	"github.com/findy-network/findy-wrapper-go/internal/ctx"
+)
+
+// DefaultConcurrency is the concurrency limit of the batch functions when
+// zero is given. It's the number of libindy commands in flight.
+var DefaultConcurrency = 4 * runtime.NumCPU()
+
+// PackItem is one message of PackBatch.
+type PackItem struct {
+	// SenderKey is the sender's verkey, or findy.NullString for anoncrypt.
+	SenderKey     string
+	Msg           []byte
+	RecipientKeys []string
+}
+
+// CryptItem is one message of AuthCryptBatch and AnonCryptBatch.
+type CryptItem struct {
+	RecipientKey string
+	Msg          []byte
+}
+
+// BatchResult is the result of one batch item. The results are in the order
+// of the items, and every item has its own error.
+type BatchResult struct {
+	Data []byte
+	Err  error
+}
+
+// PackBatch packs the messages with Pack, at most concurrency at the same
+// time.
+func PackBatch(wallet int, items []PackItem, concurrency int) []BatchResult {
+	return runBatch(len(items), concurrency, func(i int) ctx.Channel {
+		return Pack(wallet, items[i].SenderKey, items[i].Msg, items[i].RecipientKeys...)
+	})
+}
+
+// UnpackBatch unpacks the messages with UnpackMessage, at most concurrency at
+// the same time. The Data of the results is the JSON of Unpacked, see
+// NewUnpacked.
+func UnpackBatch(wallet int, msgs [][]byte, concurrency int) []BatchResult {
+	return runBatch(len(msgs), concurrency, func(i int) ctx.Channel {
+		return UnpackMessage(wallet, msgs[i])
+	})
+}
+
+// AuthCryptBatch encrypts the messages with AuthCrypt from the sender key, at
+// most concurrency at the same time.
+func AuthCryptBatch(wallet int, senderKey string, items []CryptItem, concurrency int) []BatchResult {
+	return runBatch(len(items), concurrency, func(i int) ctx.Channel {
+		return AuthCrypt(wallet, senderKey, items[i].RecipientKey, items[i].Msg)
+	})
+}
+
+// AnonCryptBatch encrypts the messages with AnonCrypt, at most concurrency
+// at the same time.
+func AnonCryptBatch(items []CryptItem, concurrency int) []BatchResult {
+	return runBatch(len(items), concurrency, func(i int) ctx.Channel {
+		return AnonCrypt(items[i].RecipientKey, items[i].Msg)
+	})
+}
+
+// runBatch starts the n commands and waits their results so that at most
+// concurrency commands are running.
+func runBatch(n, concurrency int, start func(i int) ctx.Channel) []BatchResult {
+	if concurrency <= 0 {
+		concurrency = DefaultConcurrency
+	}
+	results := make([]BatchResult, n)
+	sem := make(chan struct{}, concurrency)
+	var wg sync.WaitGroup
+	for i := 0; i < n; i++ {
+		sem <- struct{}{}
+		wg.Add(1)
+		go func(i int) {
+			defer func() { <-sem; wg.Done() }()
+			results[i] = batchResult(<-start(i))
+		}(i)
+	}
+	wg.Wait()
+	return results
+}
+
+func batchResult(r dto.Result) BatchResult {
+	if r.Err() != nil {
+		return BatchResult{Err: r.Err()}
+	}
+	return BatchResult{Data: r.Bytes()}
+}
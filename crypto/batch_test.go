@@ -0,0 +1,149 @@
+package crypto
+
+import (
+	"fmt"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/lainio/err2/assert"
+
+	"github.com/findy-network/findy-wrapper-go"
+	"github.com/findy-network/findy-wrapper-go/did"
+	"github.com/findy-network/findy-wrapper-go/dto"
+	"github.com/findy-network/findy-wrapper-go/helpers"
+	"github.com/findy-network/findy-wrapper-go/internal/ctx"
+)
+
+func TestRunBatch(t *testing.T) {
+	defer assert.PushTester(t)()
+
+	var running, maxRunning int32
+	results := runBatch(50, 3, func(i int) ctx.Channel {
+		ch := make(ctx.Channel, 1)
+		go func() {
+			n := atomic.AddInt32(&running, 1)
+			for {
+				prev := atomic.LoadInt32(&maxRunning)
+				if n <= prev || atomic.CompareAndSwapInt32(&maxRunning, prev, n) {
+					break
+				}
+			}
+			time.Sleep(time.Millisecond)
+			atomic.AddInt32(&running, -1)
+
+			var r dto.Result
+			if i%10 == 0 {
+				r.SetErr(fmt.Errorf("item %d", i))
+			} else {
+				r.SetBytes([]byte(fmt.Sprint(i)))
+			}
+			ch <- r
+		}()
+		return ch
+	})
+
+	assert.SLen(results, 50)
+	assert.That(maxRunning <= 3)
+	for i, r := range results {
+		if i%10 == 0 {
+			assert.Error(r.Err)
+		} else {
+			assert.NoError(r.Err)
+			assert.Equal(string(r.Data), fmt.Sprint(i))
+		}
+	}
+}
+
+func TestPackBatch(t *testing.T) {
+	defer assert.PushTester(t)()
+
+	w, name := helpers.CreateAndOpenTestWallet(t)
+	r := <-did.Create(w)
+	assert.NoError(r.Err())
+	senderKey := r.Str2()
+	r = <-did.Create(w)
+	assert.NoError(r.Err())
+	recipientKey := r.Str2()
+
+	items := make([]PackItem, 20)
+	for i := range items {
+		items[i] = PackItem{SenderKey: senderKey, Msg: []byte(fmt.Sprintf(`{"i":%d}`, i)),
+			RecipientKeys: []string{recipientKey}}
+	}
+	items[5].SenderKey = findy.NullString
+	items[7].RecipientKeys = []string{"invalid"}
+
+	packed := PackBatch(w, items, 4)
+	assert.That(packed[7].Err != nil)
+	msgs := make([][]byte, len(packed))
+	for i, p := range packed {
+		msgs[i] = p.Data
+	}
+	for i, u := range UnpackBatch(w, msgs, 0) {
+		if i == 7 {
+			assert.Error(u.Err)
+			continue
+		}
+		assert.NoError(u.Err)
+		assert.Equal(NewUnpacked(u.Data).Message, string(items[i].Msg))
+	}
+
+	crypted := AuthCryptBatch(w, senderKey, []CryptItem{{recipientKey, []byte("a")}, {recipientKey, []byte("b")}}, 2)
+	for i, c := range crypted {
+		assert.NoError(c.Err)
+		r = <-AuthDecrypt(w, recipientKey, c.Data)
+		assert.NoError(r.Err())
+		assert.Equal(string(r.Bytes()), []string{"a", "b"}[i])
+	}
+	anonCrypted := AnonCryptBatch([]CryptItem{{recipientKey, []byte("a")}, {"invalid", []byte("b")}}, 0)
+	assert.NoError(anonCrypted[0].Err)
+	assert.Error(anonCrypted[1].Err)
+
+	helpers.CloseAndDeleteTestWallet(w, name, t)
+}
+
+func benchmarkItems(b *testing.B, w int) []PackItem {
+	r := <-did.Create(w)
+	if r.Err() != nil {
+		b.Fatal(r.Err())
+	}
+	senderKey := r.Str2()
+	items := make([]PackItem, 100)
+	for i := range items {
+		items[i] = PackItem{SenderKey: senderKey, Msg: []byte(connectionRequest),
+			RecipientKeys: []string{senderKey}}
+	}
+	return items
+}
+
+func BenchmarkPack(b *testing.B) {
+	w, name := helpers.CreateAndOpenTestWallet(b)
+	items := benchmarkItems(b, w)
+	b.ResetTimer()
+	for n := 0; n < b.N; n++ {
+		for _, item := range items {
+			r := <-Pack(w, item.SenderKey, item.Msg, item.RecipientKeys...)
+			if r.Err() != nil {
+				b.Fatal(r.Err())
+			}
+		}
+	}
+	b.StopTimer()
+	helpers.CloseAndDeleteTestWallet(w, name, b)
+}
+
+func BenchmarkPackBatch(b *testing.B) {
+	w, name := helpers.CreateAndOpenTestWallet(b)
+	items := benchmarkItems(b, w)
+	b.ResetTimer()
+	for n := 0; n < b.N; n++ {
+		for _, r := range PackBatch(w, items, 0) {
+			if r.Err != nil {
+				b.Fatal(r.Err)
+			}
+		}
+	}
+	b.StopTimer()
+	helpers.CloseAndDeleteTestWallet(w, name, b)
+}
@@ -113,3 +113,26 @@ func (up *Unpacking) Bytes() (bytes []byte, err error) {
 	up.Unpacked = *NewUnpacked(r.Bytes())
 	return up.Unpacked.Bytes(), nil
 }
+
+// WalletSigner signs with the wallet's verkey by SignMsg. It implements the
+// Signer interface of the didcomm package. Kid is the key ID in the
+// signatures, e.g. did:example:alice#key-1.
+type WalletSigner struct {
+	Wallet int
+	VerKey string
+	Kid    string
+}
+
+// KeyID returns the key ID.
+func (s WalletSigner) KeyID() string {
+	return s.Kid
+}
+
+// Sign signs the data with the wallet's verkey.
+func (s WalletSigner) Sign(data []byte) ([]byte, error) {
+	r := <-SignMsg(s.Wallet, s.VerKey, data)
+	if r.Err() != nil {
+		return nil, r.Err()
+	}
+	return r.Bytes(), nil
+}
@@ -0,0 +1,144 @@
+package didcomm
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha512"
+	"crypto/subtle"
+	"encoding/binary"
+	"errors"
+	"fmt"
+
+	"golang.org/x/crypto/chacha20poly1305"
+)
+
+// Content encryption algorithms. Authcrypt uses always EncA256CBCHS512.
+const (
+	EncA256CBCHS512 = "A256CBC-HS512"
+	EncA256GCM      = "A256GCM"
+	EncXC20P        = "XC20P"
+)
+
+// contentCipher encrypts and decrypts the content with a detached tag.
+type contentCipher interface {
+	keySize() int
+	ivSize() int
+	seal(cek, iv, plaintext, aad []byte) (ciphertext, tag []byte, err error)
+	open(cek, iv, ciphertext, tag, aad []byte) ([]byte, error)
+}
+
+func newContentCipher(enc string) (contentCipher, error) {
+	switch enc {
+	case EncA256CBCHS512:
+		return cbcHMAC{}, nil
+	case EncA256GCM:
+		return aeadCipher{newAEAD: newGCM, key: 32, iv: 12}, nil
+	case EncXC20P:
+		return aeadCipher{newAEAD: chacha20poly1305.NewX, key: chacha20poly1305.KeySize,
+			iv: chacha20poly1305.NonceSizeX}, nil
+	}
+	return nil, fmt.Errorf("%w enc %q", ErrUnsupported, enc)
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+type aeadCipher struct {
+	newAEAD func(key []byte) (cipher.AEAD, error)
+	key, iv int
+}
+
+func (c aeadCipher) keySize() int { return c.key }
+func (c aeadCipher) ivSize() int  { return c.iv }
+
+func (c aeadCipher) seal(cek, iv, plaintext, aad []byte) (ciphertext, tag []byte, err error) {
+	aead, err := c.newAEAD(cek)
+	if err != nil {
+		return nil, nil, err
+	}
+	sealed := aead.Seal(nil, iv, plaintext, aad)
+	tagStart := len(sealed) - aead.Overhead()
+	return sealed[:tagStart], sealed[tagStart:], nil
+}
+
+func (c aeadCipher) open(cek, iv, ciphertext, tag, aad []byte) ([]byte, error) {
+	aead, err := c.newAEAD(cek)
+	if err != nil {
+		return nil, err
+	}
+	if len(iv) != aead.NonceSize() {
+		return nil, fmt.Errorf("invalid iv length %d", len(iv))
+	}
+	return aead.Open(nil, iv, append(append([]byte(nil), ciphertext...), tag...), aad)
+}
+
+// cbcHMAC is AES_256_CBC_HMAC_SHA_512 of RFC 7518 section 5.2.
+type cbcHMAC struct{}
+
+func (cbcHMAC) keySize() int { return 64 }
+func (cbcHMAC) ivSize() int  { return aes.BlockSize }
+
+func (c cbcHMAC) seal(cek, iv, plaintext, aad []byte) (ciphertext, tag []byte, err error) {
+	if len(cek) != c.keySize() || len(iv) != c.ivSize() {
+		return nil, nil, errors.New("invalid key or iv length")
+	}
+	block, err := aes.NewCipher(cek[32:])
+	if err != nil {
+		return nil, nil, err
+	}
+	pad := aes.BlockSize - len(plaintext)%aes.BlockSize
+	ciphertext = make([]byte, len(plaintext)+pad)
+	copy(ciphertext, plaintext)
+	for i := len(plaintext); i < len(ciphertext); i++ {
+		ciphertext[i] = byte(pad)
+	}
+	cipher.NewCBCEncrypter(block, iv).CryptBlocks(ciphertext, ciphertext)
+	return ciphertext, c.tag(cek[:32], aad, iv, ciphertext), nil
+}
+
+func (c cbcHMAC) open(cek, iv, ciphertext, tag, aad []byte) ([]byte, error) {
+	if len(cek) != c.keySize() || len(iv) != c.ivSize() {
+		return nil, errors.New("invalid key or iv length")
+	}
+	if subtle.ConstantTimeCompare(tag, c.tag(cek[:32], aad, iv, ciphertext)) != 1 {
+		return nil, errors.New("invalid tag")
+	}
+	if len(ciphertext) == 0 || len(ciphertext)%aes.BlockSize != 0 {
+		return nil, errors.New("invalid ciphertext length")
+	}
+	block, err := aes.NewCipher(cek[32:])
+	if err != nil {
+		return nil, err
+	}
+	plaintext := make([]byte, len(ciphertext))
+	cipher.NewCBCDecrypter(block, iv).CryptBlocks(plaintext, ciphertext)
+	pad := int(plaintext[len(plaintext)-1])
+	if pad == 0 || pad > aes.BlockSize {
+		return nil, errors.New("invalid padding")
+	}
+	return plaintext[:len(plaintext)-pad], nil
+}
+
+func (cbcHMAC) tag(macKey, aad, iv, ciphertext []byte) []byte {
+	mac := hmac.New(sha512.New, macKey)
+	mac.Write(aad)
+	mac.Write(iv)
+	mac.Write(ciphertext)
+	_ = binary.Write(mac, binary.BigEndian, uint64(len(aad))*8)
+	return mac.Sum(nil)[:32]
+}
+
+func random(n int) ([]byte, error) {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		return nil, err
+	}
+	return b, nil
+}
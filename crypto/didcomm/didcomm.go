@@ -0,0 +1,80 @@
+/*
+Package didcomm implements the DIDComm v2 message formats: plaintext JWM
+messages, signed messages (JWS, EdDSA), and encrypted messages (JWE) with
+anoncrypt (ECDH-ES+A256KW) and authcrypt (ECDH-1PU+A256KW) for multiple
+recipients. The key agreement is X25519. Ed25519 keys of the wallet can be
+used with the same conversion as libindy uses for its box encryption, see
+X25519PublicKey and X25519PrivateKey.
+
+The package is pure Go. Signing goes through the Signer interface, which
+crypto.WalletSigner implements with crypto.SignMsg, so wallet keys don't need
+to leave the wallet for signing.
+
+	signed, err := didcomm.Sign(msg, didcomm.Ed25519Signer{Kid: kid, Key: key})
+	encrypted, err := didcomm.Authcrypt(dto.ToJSON(signed), senderKey, recipientKeys...)
+
+The results are typed envelopes, which are parsed with Parse.
+*/
+package didcomm
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+// Media types of the DIDComm v2 envelopes.
+const (
+	TypPlain     = "application/didcomm-plain+json"
+	TypSigned    = "application/didcomm-signed+json"
+	TypEncrypted = "application/didcomm-encrypted+json"
+)
+
+// ErrUnsupported is returned for the algorithms and key types which aren't
+// supported.
+var ErrUnsupported = errors.New("didcomm: unsupported")
+
+// Envelope is *Message, *JWS or *JWE.
+type Envelope interface {
+	MediaType() string
+}
+
+// Message is the plaintext DIDComm v2 message.
+type Message struct {
+	ID          string          `json:"id"`
+	Typ         string          `json:"typ,omitempty"`
+	Type        string          `json:"type"`
+	From        string          `json:"from,omitempty"`
+	To          []string        `json:"to,omitempty"`
+	ThID        string          `json:"thid,omitempty"`
+	PThID       string          `json:"pthid,omitempty"`
+	CreatedTime int64           `json:"created_time,omitempty"`
+	ExpiresTime int64           `json:"expires_time,omitempty"`
+	Body        json.RawMessage `json:"body"`
+}
+
+// MediaType returns TypPlain.
+func (m *Message) MediaType() string { return TypPlain }
+
+// Parse parses the envelope JSON to *Message, *JWS or *JWE by its members.
+func Parse(data []byte) (Envelope, error) {
+	var members map[string]json.RawMessage
+	if err := json.Unmarshal(data, &members); err != nil {
+		return nil, fmt.Errorf("didcomm: parse: %w", err)
+	}
+	var env Envelope
+	switch {
+	case members["ciphertext"] != nil:
+		env = new(JWE)
+	case members["signatures"] != nil:
+		env = new(JWS)
+	case members["type"] != nil:
+		env = new(Message)
+	default:
+		return nil, errors.New("didcomm: parse: unknown envelope")
+	}
+	if err := json.Unmarshal(data, env); err != nil {
+		return nil, fmt.Errorf("didcomm: parse: %w", err)
+	}
+	return env, nil
+}
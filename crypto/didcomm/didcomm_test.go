@@ -0,0 +1,273 @@
+package didcomm
+
+import (
+	"crypto/ed25519"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/lainio/err2/assert"
+)
+
+// Alice's signing key and Bob's key agreement keys of the DIDComm v2 spec
+// test vectors.
+var (
+	aliceSignKey = ed25519.NewKeyFromSeed(mustDecode("pFRUKkyzx4kHdJtFSnlPA9WzqkDT1HWV0xZ5OYZd2SY"))
+	bobKeys      = []PrivateKey{
+		{Kid: "did:example:bob#key-x25519-1", D: mustDecode("b9NnuOCB0hm7YGNvaE9DMhwH_wjZA1-gWD6dA0JWdL0")},
+		{Kid: "did:example:bob#key-x25519-2", D: mustDecode("p-vteoF1gopny1HXywt76xz_uC83UUmrgszsI-ThBKk")},
+		{Kid: "did:example:bob#key-x25519-3", D: mustDecode("f9WJeuQXEItkGM8shN4dqFr5fLQLBasHnWZ-8dPaSo0")},
+	}
+)
+
+// specMessage is the plaintext message of the DIDComm v2 spec test vectors.
+var specMessage = &Message{
+	ID:          "1234567890",
+	Typ:         TypPlain,
+	Type:        "http://example.com/protocols/lets_do_lunch/1.0/proposal",
+	From:        "did:example:alice",
+	To:          []string{"did:example:bob"},
+	CreatedTime: 1516269022,
+	ExpiresTime: 1516385931,
+	Body:        json.RawMessage(`{"messagespecificattribute":"and its value"}`),
+}
+
+func TestSign(t *testing.T) {
+	defer assert.PushTester(t)()
+
+	jws, err := Sign(specMessage, Ed25519Signer{Kid: "did:example:alice#key-1", Key: aliceSignKey})
+	assert.NoError(err)
+	assert.SLen(jws.Signatures, 1)
+	assert.Equal(jws.Payload, "eyJpZCI6IjEyMzQ1Njc4OTAiLCJ0eXAiOiJhcHBsaWNhdGlvbi9kaWRjb21tLXBsYWluK2pzb24i"+
+		"LCJ0eXBlIjoiaHR0cDovL2V4YW1wbGUuY29tL3Byb3RvY29scy9sZXRzX2RvX2x1bmNoLzEuMC9wcm9wb3NhbCIsImZyb20iOiJk"+
+		"aWQ6ZXhhbXBsZTphbGljZSIsInRvIjpbImRpZDpleGFtcGxlOmJvYiJdLCJjcmVhdGVkX3RpbWUiOjE1MTYyNjkwMjIsImV4cGly"+
+		"ZXNfdGltZSI6MTUxNjM4NTkzMSwiYm9keSI6eyJtZXNzYWdlc3BlY2lmaWNhdHRyaWJ1dGUiOiJhbmQgaXRzIHZhbHVlIn19")
+	assert.Equal(jws.Signatures[0].Protected, "eyJ0eXAiOiJhcHBsaWNhdGlvbi9kaWRjb21tLXNpZ25lZCtqc29uIiwiYWxnIjoiRWREU0EifQ")
+	assert.Equal(jws.Signatures[0].Signature,
+		"FW33NnvOHV0Ted9-F7GZbkia-vYAfBKtH4oBxbrttWAhBZ6UFJMxcGjL3lwOl4YohI3kyyd08LHPWNMgP2EVCQ")
+
+	keys := map[string]ed25519.PublicKey{
+		"did:example:alice#key-1": aliceSignKey.Public().(ed25519.PublicKey),
+	}
+	msg, err := Verify(jws, keys)
+	assert.NoError(err)
+	assert.DeepEqual(msg, specMessage)
+
+	jws.Signatures[0].Signature = jws.Signatures[0].Signature[:10] + "AAAA" + jws.Signatures[0].Signature[14:]
+	_, err = Verify(jws, keys)
+	assert.Error(err)
+	_, err = Verify(jws, nil)
+	assert.Error(err)
+}
+
+// TestDecrypt_ecdh1pu uses the test vector of draft-madden-jose-ecdh-1pu-04
+// appendix B.
+func TestDecrypt_ecdh1pu(t *testing.T) {
+	defer assert.PushTester(t)()
+
+	alice := PublicKey{X: mustDecode("Knbm_BcdQr7WIoz-uqit9M0wbcfEr6y-9UfIZ8QnBD4")}
+	bob := PrivateKey{Kid: "bob-key-2", D: mustDecode("1gDirl_r_Y3-qUa3WXHgEXrrEHngWThU3c9zj9A2uBg")}
+	jwe := &JWE{
+		Protected: "eyJhbGciOiJFQ0RILTFQVStBMTI4S1ciLCJlbmMiOiJBMjU2Q0JDLUhTNTEyIiwiYXB1IjoiUVd4cFkyVSIsImFw" +
+			"diI6IlFtOWlJR0Z1WkNCRGFHRnliR2xsIiwiZXBrIjp7Imt0eSI6Ik9LUCIsImNydiI6IlgyNTUxOSIsIngiOiJrOW9mX2Nw" +
+			"QWFqeTBwb1c1Z2FpeFhHczluSGt3ZzFBRnFVQUZhMzlkeUJjIn19",
+		Recipients: []JWERecipient{{
+			Header: RecipientHeader{Kid: "bob-key-2"},
+			EncryptedKey: "pOMVA9_PtoRe7xXW1139NzzN1UhiFoio8lGto9cf0t8PyU-sjNXH8-LIRLycq8CHJQbDwvQeU1cSl55cQ0hG" +
+				"ezJu2N9IY0QN",
+		}},
+		IV:         "AAECAwQFBgcICQoLDA0ODw",
+		Ciphertext: "Az2IWsISEMDJvyc5XRL-3-d-RgNBOGolCsxFFoUXFYw",
+		Tag:        "HLb4fTlm8spGmij3RyOs2gJ4DpHM4hhVRwdF_hGb3WQ",
+	}
+	payload, err := Decrypt(jwe, bob, &alice)
+	assert.NoError(err)
+	assert.Equal(string(payload), "Three is a magic number.")
+
+	_, err = Decrypt(jwe, bob, nil)
+	assert.Error(err)
+	_, err = Decrypt(jwe, bob, &PublicKey{X: bobKeys[0].Public().X})
+	assert.Error(err)
+}
+
+// TestDecrypt_anoncrypt uses the anoncrypt test vector of the DIDComm v2 spec
+// which uses X25519 and XC20P. The other anoncrypt vectors of the spec use NIST
+// curves, which aren't supported.
+func TestDecrypt_anoncrypt(t *testing.T) {
+	defer assert.PushTester(t)()
+
+	jwe := &JWE{
+		Protected: "eyJlcGsiOnsia3R5IjoiT0tQIiwiY3J2IjoiWDI1NTE5IiwieCI6IkpIanNtSVJaQWFCMHpSR193TlhMVjJyUGdnRjAwaGRI" +
+			"Ylc1cmo4ZzBJMjQifSwiYXB2IjoiTmNzdUFuclJmUEs2OUEtcmtaMEw5WFdVRzRqTXZOQzNaZzc0QlB6NTNQQSIsInR5cCI6" +
+			"ImFwcGxpY2F0aW9uL2RpZGNvbW0tZW5jcnlwdGVkK2pzb24iLCJlbmMiOiJYQzIwUCIsImFsZyI6IkVDREgtRVMrQTI1NktXIn0",
+		Recipients: []JWERecipient{
+			{
+				Header:       RecipientHeader{Kid: "did:example:bob#key-x25519-1"},
+				EncryptedKey: "3n1olyBR3nY7ZGAprOx-b7wYAKza6cvOYjNwVg3miTnbLwPP_FmE1A",
+			},
+			{
+				Header:       RecipientHeader{Kid: "did:example:bob#key-x25519-2"},
+				EncryptedKey: "j5eSzn3kCrIkhQAWPnEwrFPMW6hG0zF_y37gUvvc5gvlzsuNX4hXrQ",
+			},
+			{
+				Header:       RecipientHeader{Kid: "did:example:bob#key-x25519-3"},
+				EncryptedKey: "TEWlqlq-ao7Lbynf0oZYhxs7ZB39SUWBCK4qjqQqfeItfwmNyDm73A",
+			},
+		},
+		IV: "ESpmcyGiZpRjc5urDela21TOOTW8Wqd1",
+		Ciphertext: "KWS7gJU7TbyJlcT9dPkCw-ohNigGaHSukR9MUqFM0THbCTCNkY-g5tahBFyszlKIKXs7qOtqzYyWbPou2q77XlAeYs93IhF6" +
+			"NvaIjyNqYklvj-OtJt9W2Pj5CLOMdsR0C30wchGoXd6wEQZY4ttbzpxYznqPmJ0b9KW6ZP-l4_DSRYe9B-1oSWMNmqMPwluKbtg" +
+			"uC-riy356Xbu2C9ShfWmpmjz1HyJWQhZfczuwkWWlE63g26FMskIZZd_jGpEhPFHKUXCFwbuiw_Iy3R0BIzmXXdK_w7PZMMPba" +
+			"xssl2UeJmLQgCAP8j8TukxV96EKa6rGgULvlo7qibjJqsS5j03bnbxkuxwbfyu3OxwgVzFWlyHbUH6p",
+		Tag: "6ylC_iAs4JvDQzXeY6MuYQ",
+	}
+	h, err := jwe.Header()
+	assert.NoError(err)
+	assert.Equal(h.Apv, apv([]PublicKey{bobKeys[0].Public(), bobKeys[1].Public(), bobKeys[2].Public()}))
+
+	for _, key := range bobKeys {
+		payload, err := Decrypt(jwe, key, nil)
+		assert.NoError(err)
+		env, err := Parse(payload)
+		assert.NoError(err)
+		assert.DeepEqual(env, Envelope(specMessage))
+	}
+
+	jwe.Tag = "AAAAAAAAAAAAAAAAAAAAAA"
+	_, err = Decrypt(jwe, bobKeys[0], nil)
+	assert.Error(err)
+}
+
+func TestEncryptDecrypt(t *testing.T) {
+	defer assert.PushTester(t)()
+
+	alice := X25519PrivateKey("did:example:alice#key-x25519-1", aliceSignKey)
+	recipients := make([]PublicKey, len(bobKeys))
+	for i, key := range bobKeys {
+		recipients[i] = key.Public()
+	}
+	signed, err := Sign(specMessage, Ed25519Signer{Kid: "did:example:alice#key-1", Key: aliceSignKey})
+	assert.NoError(err)
+	payload, err := json.Marshal(signed)
+	assert.NoError(err)
+
+	for _, enc := range []string{EncA256CBCHS512, EncA256GCM, EncXC20P} {
+		jwe, err := Anoncrypt(payload, enc, recipients...)
+		assert.NoError(err)
+		assert.DeepEqual(jwe.Kids(), []string{bobKeys[0].Kid, bobKeys[1].Kid, bobKeys[2].Kid})
+		for _, key := range bobKeys {
+			decrypted, err := Decrypt(jwe, key, nil)
+			assert.NoError(err)
+			assert.Equal(string(decrypted), string(payload))
+		}
+	}
+
+	jwe, err := Authcrypt(payload, alice, recipients...)
+	assert.NoError(err)
+	h, err := jwe.Header()
+	assert.NoError(err)
+	assert.Equal(h.Alg, AlgECDH1PUA256KW)
+	assert.Equal(h.Skid, alice.Kid)
+	assert.Equal(string(mustDecode(h.Apu)), alice.Kid)
+	alicePub := alice.Public()
+	for _, key := range bobKeys {
+		decrypted, err := Decrypt(jwe, key, &alicePub)
+		assert.NoError(err)
+
+		env, err := Parse(decrypted)
+		assert.NoError(err)
+		assert.Equal(env.MediaType(), TypSigned)
+	}
+
+	// the envelope round trip through JSON and Parse
+	data, err := json.Marshal(jwe)
+	assert.NoError(err)
+	env, err := Parse(data)
+	assert.NoError(err)
+	parsed, ok := env.(*JWE)
+	assert.That(ok)
+	_, err = Decrypt(parsed, bobKeys[0], &alicePub)
+	assert.NoError(err)
+
+	parsed.Tag = encode(make([]byte, 32))
+	_, err = Decrypt(parsed, bobKeys[0], &alicePub)
+	assert.Error(err)
+	_, err = Decrypt(jwe, PrivateKey{Kid: "did:example:carol#key-1", D: bobKeys[0].D}, &alicePub)
+	assert.Error(err)
+	_, err = Anoncrypt(payload, "A128GCM", recipients...)
+	assert.Error(err)
+
+	// authcrypt isn't accepted without the key committing A256CBC-HS512
+	for _, enc := range []string{EncA256GCM, EncXC20P} {
+		jwe, err := encrypt(payload, AlgECDH1PUA256KW, enc, &alice, recipients)
+		assert.NoError(err)
+		_, err = Decrypt(jwe, bobKeys[0], &alicePub)
+		assert.That(errors.Is(err, ErrUnsupported))
+	}
+}
+
+func TestX25519Keys(t *testing.T) {
+	defer assert.PushTester(t)()
+
+	pub, err := X25519PublicKey("kid", aliceSignKey.Public().(ed25519.PublicKey))
+	assert.NoError(err)
+	assert.DeepEqual(X25519PrivateKey("kid", aliceSignKey).Public(), pub)
+
+	// the public keys of the spec's JWKs
+	assert.Equal(encode(bobKeys[1].Public().X), "UT9S3F5ep16KSNBBShU2wh3qSfqYjlasZimn0mB8_VM")
+	assert.Equal(encode(bobKeys[2].Public().X), "82k2BTUiywKv49fKLZa-WwDi8RBf0tB0M8bvSAUQ3yY")
+
+	_, err = NewPrivateKey("kid", make([]byte, 31))
+	assert.Error(err)
+}
+
+// TestKeyWrap uses the test vector of RFC 3394 section 4.6.
+func TestKeyWrap(t *testing.T) {
+	defer assert.PushTester(t)()
+
+	kek := mustHex("000102030405060708090A0B0C0D0E0F101112131415161718191A1B1C1D1E1F")
+	key := mustHex("00112233445566778899AABBCCDDEEFF000102030405060708090A0B0C0D0E0F")
+	wrapped, err := keyWrap(kek, key)
+	assert.NoError(err)
+	assert.Equal(hex.EncodeToString(wrapped),
+		"28c9f404c4b810f4cbccb35cfb87f8263f5786e2d80ed326cbc7f0e71a99f43bfb988b9b7a02dd21")
+	unwrapped, err := keyUnwrap(kek, wrapped)
+	assert.NoError(err)
+	assert.DeepEqual(unwrapped, key)
+
+	wrapped[0] ^= 1
+	_, err = keyUnwrap(kek, wrapped)
+	assert.Error(err)
+}
+
+func TestParse(t *testing.T) {
+	defer assert.PushTester(t)()
+
+	data, err := json.Marshal(specMessage)
+	assert.NoError(err)
+	env, err := Parse(data)
+	assert.NoError(err)
+	assert.DeepEqual(env, Envelope(specMessage))
+
+	_, err = Parse([]byte(`{"id":"1"}`))
+	assert.Error(err)
+	_, err = Parse([]byte(`[]`))
+	assert.Error(err)
+}
+
+func mustDecode(s string) []byte {
+	b, err := decode(s)
+	if err != nil {
+		panic(err)
+	}
+	return b
+}
+
+func mustHex(s string) []byte {
+	b, err := hex.DecodeString(s)
+	if err != nil {
+		panic(err)
+	}
+	return b
+}
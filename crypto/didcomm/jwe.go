@@ -0,0 +1,239 @@
+package didcomm
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/lainio/err2"
+	"github.com/lainio/err2/try"
+	"golang.org/x/crypto/curve25519"
+)
+
+// Key agreement algorithms.
+const (
+	AlgECDHESA256KW  = "ECDH-ES+A256KW"
+	AlgECDH1PUA256KW = "ECDH-1PU+A256KW"
+	AlgECDH1PUA128KW = "ECDH-1PU+A128KW"
+)
+
+// JWE is the encrypted message in the general JSON serialization.
+type JWE struct {
+	Protected  string         `json:"protected"`
+	Recipients []JWERecipient `json:"recipients"`
+	IV         string         `json:"iv"`
+	Ciphertext string         `json:"ciphertext"`
+	Tag        string         `json:"tag"`
+}
+
+// JWERecipient has the content encryption key wrapped for one recipient.
+type JWERecipient struct {
+	Header       RecipientHeader `json:"header"`
+	EncryptedKey string          `json:"encrypted_key"`
+}
+
+// RecipientHeader is the per recipient header.
+type RecipientHeader struct {
+	Kid string `json:"kid"`
+}
+
+// Header is the protected header of the JWE. Skid and Apu are only in
+// authcrypt.
+type Header struct {
+	Typ  string `json:"typ,omitempty"`
+	Alg  string `json:"alg"`
+	Enc  string `json:"enc"`
+	Skid string `json:"skid,omitempty"`
+	Apu  string `json:"apu,omitempty"`
+	Apv  string `json:"apv"`
+	Epk  JWK    `json:"epk"`
+}
+
+// MediaType returns TypEncrypted.
+func (e *JWE) MediaType() string { return TypEncrypted }
+
+// Header returns the decoded protected header.
+func (e *JWE) Header() (h *Header, err error) {
+	defer err2.Handle(&err, "didcomm: header")
+
+	h = new(Header)
+	try.To(json.Unmarshal(try.To1(decode(e.Protected)), h))
+	return h, nil
+}
+
+// Kids returns the key IDs of the recipients.
+func (e *JWE) Kids() []string {
+	kids := make([]string, len(e.Recipients))
+	for i, r := range e.Recipients {
+		kids[i] = r.Header.Kid
+	}
+	return kids
+}
+
+// Anoncrypt encrypts the payload to the recipients with ECDH-ES+A256KW and
+// the content encryption algorithm.
+func Anoncrypt(payload []byte, enc string, recipients ...PublicKey) (jwe *JWE, err error) {
+	defer err2.Handle(&err, "didcomm: anoncrypt")
+
+	return encrypt(payload, AlgECDHESA256KW, enc, nil, recipients)
+}
+
+// Authcrypt encrypts the payload to the recipients with ECDH-1PU+A256KW and
+// A256CBC-HS512. The sender's key ID is the skid of the header.
+func Authcrypt(payload []byte, sender PrivateKey, recipients ...PublicKey) (jwe *JWE, err error) {
+	defer err2.Handle(&err, "didcomm: authcrypt")
+
+	if sender.Kid == "" {
+		return nil, errors.New("sender kid is required")
+	}
+	return encrypt(payload, AlgECDH1PUA256KW, EncA256CBCHS512, &sender, recipients)
+}
+
+func encrypt(payload []byte, alg, enc string, sender *PrivateKey, recipients []PublicKey) (jwe *JWE, err error) {
+	if len(recipients) == 0 {
+		return nil, errors.New("no recipients")
+	}
+	content := try.To1(newContentCipher(enc))
+	eph := PrivateKey{D: try.To1(random(curve25519.ScalarSize))}
+
+	h := Header{Typ: TypEncrypted, Alg: alg, Enc: enc, Apv: apv(recipients), Epk: eph.Public().jwk()}
+	if sender != nil {
+		h.Skid = sender.Kid
+		h.Apu = encode([]byte(sender.Kid))
+	}
+	protected := encode(try.To1(json.Marshal(h)))
+
+	cek := try.To1(random(content.keySize()))
+	iv := try.To1(random(content.ivSize()))
+	ciphertext, tag := try.To2(content.seal(cek, iv, payload, []byte(protected)))
+
+	jwe = &JWE{Protected: protected, IV: encode(iv), Ciphertext: encode(ciphertext), Tag: encode(tag)}
+	for _, recipient := range recipients {
+		kek := try.To1(deriveKEK(&h, eph, recipient, sender, tag))
+		jwe.Recipients = append(jwe.Recipients, JWERecipient{
+			Header:       RecipientHeader{Kid: recipient.Kid},
+			EncryptedKey: encode(try.To1(keyWrap(kek, cek))),
+		})
+	}
+	return jwe, nil
+}
+
+// Decrypt decrypts the JWE with the recipient's key. The sender's public key
+// is required for authcrypt, and its key ID must be the skid of the header.
+// Use Header to get the skid to resolve the key. Authcrypt is accepted only
+// with A256CBC-HS512, because ECDH-1PU needs a key committing content
+// encryption.
+func Decrypt(jwe *JWE, recipient PrivateKey, sender *PublicKey) (payload []byte, err error) {
+	defer err2.Handle(&err, "didcomm: decrypt")
+
+	h := try.To1(jwe.Header())
+	content := try.To1(newContentCipher(h.Enc))
+	epk := try.To1(h.Epk.publicKey())
+
+	var senderPub *PublicKey
+	switch h.Alg {
+	case AlgECDHESA256KW:
+	case AlgECDH1PUA256KW, AlgECDH1PUA128KW:
+		if h.Enc != EncA256CBCHS512 {
+			return nil, fmt.Errorf("%w enc %q for %s", ErrUnsupported, h.Enc, h.Alg)
+		}
+		if sender == nil {
+			return nil, fmt.Errorf("sender key of %s is required", h.Skid)
+		}
+		if h.Skid != "" && h.Skid != sender.Kid {
+			return nil, fmt.Errorf("sender key %s isn't skid %s", sender.Kid, h.Skid)
+		}
+		senderPub = sender
+	default:
+		return nil, fmt.Errorf("%w alg %q", ErrUnsupported, h.Alg)
+	}
+
+	var encryptedKey string
+	for _, r := range jwe.Recipients {
+		if r.Header.Kid == recipient.Kid {
+			encryptedKey = r.EncryptedKey
+			break
+		}
+	}
+	if encryptedKey == "" {
+		return nil, fmt.Errorf("recipient %s not found", recipient.Kid)
+	}
+
+	tag := try.To1(decode(jwe.Tag))
+	kek := try.To1(deriveKEKRecipient(h, epk, recipient, senderPub, tag))
+	cek := try.To1(keyUnwrap(kek, try.To1(decode(encryptedKey))))
+	return content.open(cek, try.To1(decode(jwe.IV)), try.To1(decode(jwe.Ciphertext)),
+		tag, []byte(jwe.Protected))
+}
+
+// deriveKEK derives the key encryption key at the sender side.
+func deriveKEK(h *Header, eph PrivateKey, recipient PublicKey, sender *PrivateKey, tag []byte) ([]byte, error) {
+	ze, err := curve25519.X25519(eph.D, recipient.X)
+	if err != nil {
+		return nil, err
+	}
+	if sender == nil {
+		return kdf(h, ze, nil)
+	}
+	zs, err := curve25519.X25519(sender.D, recipient.X)
+	if err != nil {
+		return nil, err
+	}
+	return kdf(h, append(ze, zs...), tag)
+}
+
+// deriveKEKRecipient derives the key encryption key at the recipient side.
+func deriveKEKRecipient(h *Header, epk PublicKey, recipient PrivateKey, sender *PublicKey, tag []byte) ([]byte, error) {
+	ze, err := curve25519.X25519(recipient.D, epk.X)
+	if err != nil {
+		return nil, err
+	}
+	if sender == nil {
+		return kdf(h, ze, nil)
+	}
+	zs, err := curve25519.X25519(recipient.D, sender.X)
+	if err != nil {
+		return nil, err
+	}
+	return kdf(h, append(ze, zs...), tag)
+}
+
+func kdf(h *Header, z, tag []byte) ([]byte, error) {
+	apu, err := decode(h.Apu)
+	if err != nil {
+		return nil, err
+	}
+	apv, err := decode(h.Apv)
+	if err != nil {
+		return nil, err
+	}
+	keyLen := 32
+	if h.Alg == AlgECDH1PUA128KW {
+		keyLen = 16
+	}
+	return concatKDF(z, h.Alg, apu, apv, keyLen, tag), nil
+}
+
+// apv is the SHA-256 hash of the sorted recipient key IDs joined with dots.
+func apv(recipients []PublicKey) string {
+	kids := make([]string, len(recipients))
+	for i, r := range recipients {
+		kids[i] = r.Kid
+	}
+	sort.Strings(kids)
+	h := sha256.Sum256([]byte(strings.Join(kids, ".")))
+	return encode(h[:])
+}
+
+func encode(b []byte) string {
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+// decode accepts base64url with and without padding.
+func decode(s string) ([]byte, error) {
+	return base64.RawURLEncoding.DecodeString(strings.TrimRight(s, "="))
+}
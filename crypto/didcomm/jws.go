@@ -0,0 +1,113 @@
+package didcomm
+
+import (
+	"crypto/ed25519"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/lainio/err2"
+	"github.com/lainio/err2/try"
+)
+
+// AlgEdDSA is the only supported signature algorithm.
+const AlgEdDSA = "EdDSA"
+
+// Signer signs with the key of the key ID. crypto.WalletSigner signs with
+// the wallet's key.
+type Signer interface {
+	KeyID() string
+	Sign(data []byte) ([]byte, error)
+}
+
+// Ed25519Signer is a Signer for a raw Ed25519 private key.
+type Ed25519Signer struct {
+	Kid string
+	Key ed25519.PrivateKey
+}
+
+// KeyID returns the key ID.
+func (s Ed25519Signer) KeyID() string { return s.Kid }
+
+// Sign signs the data.
+func (s Ed25519Signer) Sign(data []byte) ([]byte, error) {
+	return ed25519.Sign(s.Key, data), nil
+}
+
+// JWS is the signed message in the general JSON serialization.
+type JWS struct {
+	Payload    string      `json:"payload"`
+	Signatures []Signature `json:"signatures"`
+}
+
+// Signature is one signature of the JWS.
+type Signature struct {
+	Protected string          `json:"protected"`
+	Signature string          `json:"signature"`
+	Header    SignatureHeader `json:"header"`
+}
+
+// SignatureHeader is the unprotected header of the signature.
+type SignatureHeader struct {
+	Kid string `json:"kid"`
+}
+
+type jwsProtected struct {
+	Typ string `json:"typ"`
+	Alg string `json:"alg"`
+}
+
+// MediaType returns TypSigned.
+func (s *JWS) MediaType() string { return TypSigned }
+
+// Sign signs the message with all of the signers.
+func Sign(msg *Message, signers ...Signer) (jws *JWS, err error) {
+	defer err2.Handle(&err, "didcomm: sign")
+
+	if len(signers) == 0 {
+		return nil, errors.New("no signers")
+	}
+	payload := encode(try.To1(json.Marshal(msg)))
+	protected := encode(try.To1(json.Marshal(jwsProtected{Typ: TypSigned, Alg: AlgEdDSA})))
+	jws = &JWS{Payload: payload}
+	for _, signer := range signers {
+		sig := try.To1(signer.Sign([]byte(protected + "." + payload)))
+		jws.Signatures = append(jws.Signatures, Signature{
+			Protected: protected,
+			Signature: encode(sig),
+			Header:    SignatureHeader{Kid: signer.KeyID()},
+		})
+	}
+	return jws, nil
+}
+
+// Verify verifies the signatures whose key IDs are in the keys, and returns
+// the message. At least one signature must be verified, and all of the
+// verified signatures must be valid.
+func Verify(jws *JWS, keys map[string]ed25519.PublicKey) (msg *Message, err error) {
+	defer err2.Handle(&err, "didcomm: verify")
+
+	verified := 0
+	for _, sig := range jws.Signatures {
+		key, ok := keys[sig.Header.Kid]
+		if !ok {
+			continue
+		}
+		var protected jwsProtected
+		try.To(json.Unmarshal(try.To1(decode(sig.Protected)), &protected))
+		if protected.Alg != AlgEdDSA {
+			return nil, fmt.Errorf("%w alg %q", ErrUnsupported, protected.Alg)
+		}
+		signature := try.To1(decode(sig.Signature))
+		if !ed25519.Verify(key, []byte(sig.Protected+"."+jws.Payload), signature) {
+			return nil, fmt.Errorf("invalid signature of %s", sig.Header.Kid)
+		}
+		verified++
+	}
+	if verified == 0 {
+		return nil, errors.New("no signature with known key")
+	}
+	msg = new(Message)
+	try.To(json.Unmarshal(try.To1(decode(jws.Payload)), msg))
+	return msg, nil
+}
@@ -0,0 +1,100 @@
+package didcomm
+
+import (
+	"crypto/aes"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/binary"
+	"errors"
+)
+
+// concatKDF is the Concat KDF of NIST SP 800-56A with SHA-256 as it's used in
+// JWA section 4.6.2. The tag is the ECDH-1PU extension to SuppPubInfo, it's
+// nil for ECDH-ES.
+func concatKDF(z []byte, alg string, apu, apv []byte, keyLen int, tag []byte) []byte {
+	var otherInfo []byte
+	otherInfo = appendLenPrefixed(otherInfo, []byte(alg))
+	otherInfo = appendLenPrefixed(otherInfo, apu)
+	otherInfo = appendLenPrefixed(otherInfo, apv)
+	otherInfo = binary.BigEndian.AppendUint32(otherInfo, uint32(keyLen*8))
+	if tag != nil {
+		otherInfo = appendLenPrefixed(otherInfo, tag)
+	}
+
+	var key []byte
+	for counter := uint32(1); len(key) < keyLen; counter++ {
+		h := sha256.New()
+		_ = binary.Write(h, binary.BigEndian, counter)
+		h.Write(z)
+		h.Write(otherInfo)
+		key = h.Sum(key)
+	}
+	return key[:keyLen]
+}
+
+func appendLenPrefixed(b, data []byte) []byte {
+	b = binary.BigEndian.AppendUint32(b, uint32(len(data)))
+	return append(b, data...)
+}
+
+var keyWrapIV = []byte{0xa6, 0xa6, 0xa6, 0xa6, 0xa6, 0xa6, 0xa6, 0xa6}
+
+// errKeyUnwrap is returned when the integrity check of the key unwrap fails.
+var errKeyUnwrap = errors.New("key unwrap failed")
+
+// keyWrap is the AES Key Wrap of RFC 3394.
+func keyWrap(kek, key []byte) ([]byte, error) {
+	block, err := aes.NewCipher(kek)
+	if err != nil {
+		return nil, err
+	}
+	if len(key)%8 != 0 || len(key) < 16 {
+		return nil, errors.New("invalid key length to wrap")
+	}
+	n := len(key) / 8
+	out := make([]byte, 8+len(key))
+	copy(out[8:], key)
+	a := append([]byte(nil), keyWrapIV...)
+	buf := make([]byte, 16)
+	for j := 0; j < 6; j++ {
+		for i := 1; i <= n; i++ {
+			copy(buf, a)
+			copy(buf[8:], out[i*8:i*8+8])
+			block.Encrypt(buf, buf)
+			t := uint64(n*j + i)
+			binary.BigEndian.PutUint64(a, binary.BigEndian.Uint64(buf[:8])^t)
+			copy(out[i*8:], buf[8:])
+		}
+	}
+	copy(out, a)
+	return out, nil
+}
+
+// keyUnwrap is the AES Key Unwrap of RFC 3394.
+func keyUnwrap(kek, wrapped []byte) ([]byte, error) {
+	block, err := aes.NewCipher(kek)
+	if err != nil {
+		return nil, err
+	}
+	if len(wrapped)%8 != 0 || len(wrapped) < 24 {
+		return nil, errors.New("invalid wrapped key length")
+	}
+	n := len(wrapped)/8 - 1
+	out := append([]byte(nil), wrapped...)
+	a := append([]byte(nil), wrapped[:8]...)
+	buf := make([]byte, 16)
+	for j := 5; j >= 0; j-- {
+		for i := n; i >= 1; i-- {
+			t := uint64(n*j + i)
+			binary.BigEndian.PutUint64(buf, binary.BigEndian.Uint64(a)^t)
+			copy(buf[8:], out[i*8:i*8+8])
+			block.Decrypt(buf, buf)
+			copy(a, buf[:8])
+			copy(out[i*8:], buf[8:])
+		}
+	}
+	if subtle.ConstantTimeCompare(a, keyWrapIV) != 1 {
+		return nil, errKeyUnwrap
+	}
+	return out[8:], nil
+}
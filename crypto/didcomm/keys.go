@@ -0,0 +1,76 @@
+package didcomm
+
+import (
+	"crypto/ed25519"
+	"fmt"
+
+	"github.com/findy-network/findy-wrapper-go/internal/x25519"
+	"golang.org/x/crypto/curve25519"
+)
+
+// PublicKey is the X25519 public key of a recipient or a sender.
+type PublicKey struct {
+	Kid string
+	X   []byte
+}
+
+// PrivateKey is the X25519 private key of a recipient or a sender.
+type PrivateKey struct {
+	Kid string
+	D   []byte
+}
+
+// JWK is the OKP JSON Web Key, which is used for the ephemeral keys.
+type JWK struct {
+	Kty string `json:"kty"`
+	Crv string `json:"crv"`
+	X   string `json:"x"`
+}
+
+// NewPrivateKey returns the X25519 private key for the raw key.
+func NewPrivateKey(kid string, d []byte) (PrivateKey, error) {
+	if len(d) != curve25519.ScalarSize {
+		return PrivateKey{}, fmt.Errorf("%w X25519 key length %d", ErrUnsupported, len(d))
+	}
+	return PrivateKey{Kid: kid, D: d}, nil
+}
+
+// Public returns the public key.
+func (k PrivateKey) Public() PublicKey {
+	x, _ := curve25519.X25519(k.D, curve25519.Basepoint) // key length is checked
+	return PublicKey{Kid: k.Kid, X: x}
+}
+
+// X25519PublicKey converts the Ed25519 public key, e.g. a wallet's verkey, to
+// the X25519 public key like libindy does.
+func X25519PublicKey(kid string, pub ed25519.PublicKey) (PublicKey, error) {
+	x, err := x25519.PublicKey(pub)
+	if err != nil {
+		return PublicKey{}, err
+	}
+	return PublicKey{Kid: kid, X: x}, nil
+}
+
+// X25519PrivateKey converts the Ed25519 private key to the X25519 private key
+// like libindy does.
+func X25519PrivateKey(kid string, priv ed25519.PrivateKey) PrivateKey {
+	return PrivateKey{Kid: kid, D: x25519.PrivateKey(priv)}
+}
+
+func (k PublicKey) jwk() JWK {
+	return JWK{Kty: "OKP", Crv: "X25519", X: encode(k.X)}
+}
+
+func (j JWK) publicKey() (PublicKey, error) {
+	if j.Kty != "OKP" || j.Crv != "X25519" {
+		return PublicKey{}, fmt.Errorf("%w epk %s %s", ErrUnsupported, j.Kty, j.Crv)
+	}
+	x, err := decode(j.X)
+	if err != nil {
+		return PublicKey{}, err
+	}
+	if len(x) != curve25519.PointSize {
+		return PublicKey{}, fmt.Errorf("invalid epk length %d", len(x))
+	}
+	return PublicKey{X: x}, nil
+}
@@ -0,0 +1,43 @@
+package crypto
+
+import (
+	"crypto/ed25519"
+	"encoding/json"
+	"testing"
+
+	"github.com/lainio/err2/assert"
+
+	"github.com/findy-network/findy-wrapper-go/crypto/didcomm"
+	"github.com/findy-network/findy-wrapper-go/did"
+	"github.com/findy-network/findy-wrapper-go/helpers"
+	"github.com/findy-network/findy-wrapper-go/internal/base58"
+)
+
+func TestWalletSigner(t *testing.T) {
+	defer assert.PushTester(t)()
+
+	w, name := helpers.CreateAndOpenTestWallet(t)
+	r := <-did.CreateAndStore(w, did.Did{Seed: "00000000000000000000000000Alice1"})
+	assert.NoError(r.Err())
+	verKey := r.Str2()
+	pub, err := base58.Decode(verKey)
+	assert.NoError(err)
+
+	msg := &didcomm.Message{ID: "1", Type: "https://didcomm.org/trust-ping/2.0/ping",
+		Body: json.RawMessage(`{}`)}
+	signer := WalletSigner{Wallet: w, VerKey: verKey, Kid: "did:example:alice#key-1"}
+	jws, err := didcomm.Sign(msg, signer)
+	assert.NoError(err)
+
+	// the wallet's signature is the same as the one of the raw key
+	key := ed25519.NewKeyFromSeed([]byte("00000000000000000000000000Alice1"))
+	jws2, err := didcomm.Sign(msg, didcomm.Ed25519Signer{Kid: signer.Kid, Key: key})
+	assert.NoError(err)
+	assert.DeepEqual(jws, jws2)
+
+	verified, err := didcomm.Verify(jws, map[string]ed25519.PublicKey{signer.Kid: pub})
+	assert.NoError(err)
+	assert.Equal(verified.ID, msg.ID)
+
+	helpers.CloseAndDeleteTestWallet(w, name, t)
+}
@@ -0,0 +1,57 @@
+package envelope
+
+import (
+	"crypto/ed25519"
+	"encoding/json"
+	"testing"
+
+	"github.com/lainio/err2/assert"
+
+	"github.com/findy-network/findy-wrapper-go"
+	"github.com/findy-network/findy-wrapper-go/crypto"
+	"github.com/findy-network/findy-wrapper-go/did"
+	"github.com/findy-network/findy-wrapper-go/helpers"
+)
+
+// TestLibindyCompatibility cross-verifies with crypto.Pack and
+// crypto.UnpackMessage. The wallet's keys are created from the same seeds.
+func TestLibindyCompatibility(t *testing.T) {
+	defer assert.PushTester(t)()
+
+	w, name := helpers.CreateAndOpenTestWallet(t)
+	r := <-did.CreateAndStore(w, did.Did{Seed: "00000000000000000000000000Alice1"})
+	assert.NoError(r.Err())
+	aliceVerKey := r.Str2()
+	assert.Equal(aliceVerKey, VerKey(aliceKey.Public().(ed25519.PublicKey)))
+	r = <-did.CreateAndStore(w, did.Did{Seed: "000000000000000000000000000Bob01"})
+	assert.NoError(r.Err())
+	bobVerKey := r.Str2()
+	keys := NewKeys(aliceKey, bobKey)
+	msg := []byte(`{"@type":"https://didcomm.org/trust_ping/1.0/ping"}`)
+
+	for _, senderKey := range []string{aliceVerKey, findy.NullString} {
+		// libindy -> pure Go
+		r = <-crypto.Pack(w, senderKey, msg, bobVerKey)
+		assert.NoError(r.Err())
+		unpacked, err := Unpack(keys, r.Bytes())
+		assert.NoError(err)
+		assert.Equal(unpacked.Message, string(msg))
+
+		// pure Go -> libindy
+		packed, err := Pack(keys, senderKey, msg, bobVerKey)
+		assert.NoError(err)
+		r = <-crypto.UnpackMessage(w, packed)
+		assert.NoError(r.Err())
+		indyUnpacked := crypto.NewUnpacked(r.Bytes())
+		assert.Equal(indyUnpacked.Message, string(msg))
+		assert.Equal(indyUnpacked.RecipientVerkey, bobVerKey)
+		assert.Equal(indyUnpacked.SenderVerkey, unpacked.SenderVerkey)
+
+		// the results are the same JSON
+		data, err := json.Marshal(unpacked)
+		assert.NoError(err)
+		assert.Equal(string(data), string(r.Bytes()))
+	}
+
+	helpers.CloseAndDeleteTestWallet(w, name, t)
+}
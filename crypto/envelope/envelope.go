@@ -0,0 +1,82 @@
+/*
+Package envelope is a pure Go implementation of the DIDComm v1 message
+envelope of Aries RFC 0019, i.e. the format of libindy's indy_pack_message and
+indy_unpack_message. The packed messages are compatible with crypto.Pack and
+crypto.UnpackMessage in both directions, but this package doesn't need cgo or
+libindy, which makes it usable in routing and mediator services.
+
+The private keys are given with a KeyProvider. Keys is the one for raw Ed25519
+keys.
+
+	keys := envelope.NewKeys(myKey)
+	packed, err := envelope.Pack(keys, myVerKey, msg, theirVerKey)
+	...
+	unpacked, err := envelope.Unpack(keys, packed)
+
+Mediators don't need to decrypt the messages they route. Parse returns the
+recipients and the algorithm of the packed message, and Wrap and Unwrap handle
+the Aries RFC 0094 forward messages for the routing keys.
+*/
+package envelope
+
+import (
+	"crypto/ed25519"
+	"errors"
+	"fmt"
+
+	"github.com/findy-network/findy-wrapper-go/internal/base58"
+)
+
+// ErrKeyNotFound is returned by KeyProvider when it doesn't have the key.
+var ErrKeyNotFound = errors.New("envelope: key not found")
+
+// KeyProvider provides the Ed25519 private keys by their verkeys.
+type KeyProvider interface {
+	PrivateKey(verKey string) (ed25519.PrivateKey, error)
+}
+
+// Keys is a KeyProvider for raw Ed25519 private keys. The map key is the
+// base58 verkey.
+type Keys map[string]ed25519.PrivateKey
+
+// NewKeys creates Keys from the private keys.
+func NewKeys(keys ...ed25519.PrivateKey) Keys {
+	k := make(Keys, len(keys))
+	for _, key := range keys {
+		k.Add(key)
+	}
+	return k
+}
+
+// Add adds the private key and returns its verkey.
+func (k Keys) Add(key ed25519.PrivateKey) (verKey string) {
+	verKey = VerKey(key.Public().(ed25519.PublicKey))
+	k[verKey] = key
+	return verKey
+}
+
+// PrivateKey returns the private key of the verkey or ErrKeyNotFound.
+func (k Keys) PrivateKey(verKey string) (ed25519.PrivateKey, error) {
+	key, ok := k[verKey]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrKeyNotFound, verKey)
+	}
+	return key, nil
+}
+
+// VerKey returns the base58 verkey of the Ed25519 public key.
+func VerKey(pub ed25519.PublicKey) string {
+	return base58.Encode(pub)
+}
+
+// PublicKey decodes the base58 verkey to the Ed25519 public key.
+func PublicKey(verKey string) (ed25519.PublicKey, error) {
+	pub, err := base58.Decode(verKey)
+	if err != nil {
+		return nil, fmt.Errorf("envelope: verkey %q: %w", verKey, err)
+	}
+	if len(pub) != ed25519.PublicKeySize {
+		return nil, fmt.Errorf("envelope: verkey %q: invalid length %d", verKey, len(pub))
+	}
+	return pub, nil
+}
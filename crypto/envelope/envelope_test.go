@@ -0,0 +1,103 @@
+package envelope
+
+import (
+	"crypto/ed25519"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/lainio/err2/assert"
+)
+
+var (
+	aliceKey = ed25519.NewKeyFromSeed([]byte("00000000000000000000000000Alice1"))
+	bobKey   = ed25519.NewKeyFromSeed([]byte("000000000000000000000000000Bob01"))
+	carolKey = ed25519.NewKeyFromSeed([]byte("0000000000000000000000000Carol01"))
+)
+
+func TestPackUnpack(t *testing.T) {
+	defer assert.PushTester(t)()
+
+	alice := NewKeys(aliceKey)
+	aliceVerKey := VerKey(aliceKey.Public().(ed25519.PublicKey))
+	bob := NewKeys(bobKey)
+	bobVerKey := VerKey(bobKey.Public().(ed25519.PublicKey))
+	carol := NewKeys(carolKey)
+	carolVerKey := VerKey(carolKey.Public().(ed25519.PublicKey))
+	msg := []byte(`{"@type":"https://didcomm.org/basicmessage/1.0/message","content":"hello"}`)
+
+	tests := []struct {
+		name      string
+		senderKey string
+		alg       string
+	}{
+		{"authcrypt", aliceVerKey, AlgAuthcrypt},
+		{"anoncrypt", "", AlgAnoncrypt},
+		{"null string", nullStringKey, AlgAnoncrypt},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			defer assert.PushTester(t)()
+
+			packed, err := Pack(alice, tt.senderKey, msg, bobVerKey, carolVerKey)
+			assert.NoError(err)
+
+			var jwe JWE
+			assert.NoError(json.Unmarshal(packed, &jwe))
+			var protected Protected
+			assert.NoError(json.Unmarshal(mustDecode(jwe.Protected), &protected))
+			assert.Equal(protected.Alg, tt.alg)
+			assert.SLen(protected.Recipients, 2)
+
+			for _, recipient := range []struct {
+				keys   Keys
+				verKey string
+			}{{bob, bobVerKey}, {carol, carolVerKey}} {
+				unpacked, err := Unpack(recipient.keys, packed)
+				assert.NoError(err)
+				assert.Equal(string(unpacked.Bytes()), string(msg))
+				assert.Equal(unpacked.RecipientVerkey, recipient.verKey)
+				if tt.alg == AlgAuthcrypt {
+					assert.Equal(unpacked.SenderVerkey, aliceVerKey)
+				} else {
+					assert.Equal(unpacked.SenderVerkey, "")
+				}
+			}
+
+			_, err = Unpack(alice, packed)
+			assert.That(errors.Is(err, ErrNoRecipient))
+		})
+	}
+}
+
+func TestUnpack_errors(t *testing.T) {
+	defer assert.PushTester(t)()
+
+	bob := NewKeys(bobKey)
+	bobVerKey := VerKey(bobKey.Public().(ed25519.PublicKey))
+	packed, err := Pack(nil, "", []byte("msg"), bobVerKey)
+	assert.NoError(err)
+
+	var jwe JWE
+	assert.NoError(json.Unmarshal(packed, &jwe))
+	jwe.Ciphertext = encode([]byte("tampered"))
+	tampered, err := json.Marshal(jwe)
+	assert.NoError(err)
+	_, err = Unpack(bob, tampered)
+	assert.Error(err)
+
+	_, err = Unpack(bob, []byte("{}"))
+	assert.Error(err)
+	_, err = Pack(nil, "", []byte("msg"))
+	assert.Error(err)
+	_, err = Pack(nil, "", []byte("msg"), "invalid verkey")
+	assert.Error(err)
+	_, err = Pack(bob, "unknown", []byte("msg"), bobVerKey)
+	assert.That(errors.Is(err, ErrKeyNotFound))
+}
+
+func mustDecode(s string) []byte {
+	b, err := decode(s)
+	assert.NoError(err)
+	return b
+}
@@ -0,0 +1,72 @@
+package envelope
+
+import (
+	"crypto/rand"
+	"encoding/json"
+	"fmt"
+
+	"github.com/lainio/err2"
+	"github.com/lainio/err2/try"
+)
+
+// ForwardType is the message type of the Aries RFC 0094 forward message.
+const ForwardType = "https://didcomm.org/routing/1.0/forward"
+
+// forwardTypeSov is the legacy type of the forward message.
+const forwardTypeSov = "did:sov:BzCbsNYhMrjHiqZDTUASHg;spec/routing/1.0/forward"
+
+// Forward is the forward message to a mediator. Msg is the packed message for
+// the next hop.
+type Forward struct {
+	Type string          `json:"@type"`
+	ID   string          `json:"@id"`
+	To   string          `json:"to"`
+	Msg  json.RawMessage `json:"msg"`
+}
+
+// Wrap wraps the packed message to forward messages for the routing keys. The
+// message is packed to the recipient key, and the first routing key is the
+// mediator closest to the recipient, like in the routingKeys of the DIDDoc
+// service. Each forward message is anoncrypted to its routing key.
+func Wrap(packed []byte, recipientKey string, routingKeys ...string) (wrapped []byte, err error) {
+	defer err2.Handle(&err, "envelope: wrap")
+
+	to := recipientKey
+	for _, routingKey := range routingKeys {
+		fwd := Forward{Type: ForwardType, ID: try.To1(newID()), To: to, Msg: packed}
+		packed = try.To1(Pack(nil, "", try.To1(json.Marshal(fwd)), routingKey))
+		to = routingKey
+	}
+	return packed, nil
+}
+
+// Unwrap unpacks the message with the keys of the mediator. If it's a forward
+// message, it returns the forward message, whose Msg is packed to the To key.
+// Otherwise the message is for the mediator itself, and it's returned as
+// unpacked.
+func Unwrap(keys KeyProvider, packed []byte) (fwd *Forward, unpacked *Unpacked, err error) {
+	defer err2.Handle(&err, "envelope: unwrap")
+
+	unpacked = try.To1(Unpack(keys, packed))
+	var msg Forward
+	if json.Unmarshal(unpacked.Bytes(), &msg) != nil ||
+		(msg.Type != ForwardType && msg.Type != forwardTypeSov) {
+		return nil, unpacked, nil
+	}
+	if msg.To == "" || len(msg.Msg) == 0 {
+		return nil, nil, fmt.Errorf("forward message %s: to and msg are required", msg.ID)
+	}
+	try.To1(Parse(msg.Msg))
+	return &msg, unpacked, nil
+}
+
+// newID returns a random UUID v4.
+func newID() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	b[6] = b[6]&0x0f | 0x40
+	b[8] = b[8]&0x3f | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[:4], b[4:6], b[6:8], b[8:10], b[10:]), nil
+}
@@ -0,0 +1,252 @@
+package envelope
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/findy-network/findy-wrapper-go/internal/x25519"
+	"github.com/lainio/err2"
+	"github.com/lainio/err2/try"
+	"golang.org/x/crypto/chacha20poly1305"
+	"golang.org/x/crypto/nacl/box"
+)
+
+// Values of the protected header.
+const (
+	Enc           = "xchacha20poly1305_ietf"
+	Typ           = "JWM/1.0"
+	AlgAuthcrypt  = "Authcrypt"
+	AlgAnoncrypt  = "Anoncrypt"
+	nonceSize     = 24
+	cekSize       = chacha20poly1305.KeySize
+	nullStringKey = "\xff" // findy.NullString, which would need cgo
+)
+
+// ErrNoRecipient is returned by Unpack when none of the recipient keys are
+// found from the KeyProvider.
+var ErrNoRecipient = errors.New("envelope: no recipient key found")
+
+// JWE is the packed message. The fields are base64url encoded.
+type JWE struct {
+	Protected  string `json:"protected"`
+	IV         string `json:"iv"`
+	Ciphertext string `json:"ciphertext"`
+	Tag        string `json:"tag"`
+}
+
+// Protected is the decoded protected header of the JWE.
+type Protected struct {
+	Enc        string      `json:"enc"`
+	Typ        string      `json:"typ"`
+	Alg        string      `json:"alg"`
+	Recipients []Recipient `json:"recipients"`
+}
+
+// Recipient has the content encryption key encrypted to one recipient.
+type Recipient struct {
+	EncryptedKey string `json:"encrypted_key"`
+	Header       Header `json:"header"`
+}
+
+// Header is the per recipient header. Sender and IV are only in Authcrypt.
+type Header struct {
+	Kid    string `json:"kid"`
+	IV     string `json:"iv,omitempty"`
+	Sender string `json:"sender,omitempty"`
+}
+
+// Unpacked is the result of Unpack. It's the same JSON as the result of
+// crypto.UnpackMessage. SenderVerkey is empty for anoncrypted messages.
+type Unpacked struct {
+	Message         string `json:"message"`
+	RecipientVerkey string `json:"recipient_verkey"`
+	SenderVerkey    string `json:"sender_verkey,omitempty"`
+}
+
+// Bytes returns the message.
+func (u *Unpacked) Bytes() []byte {
+	return []byte(u.Message)
+}
+
+// Pack encrypts the message to the recipient verkeys. If the sender key is
+// set, the message is authcrypted with its private key from the KeyProvider,
+// otherwise it's anoncrypted, and keys can be nil. The sender key can also be
+// findy.NullString like in crypto.Pack.
+func Pack(keys KeyProvider, senderKey string, msg []byte, recipientKeys ...string) (packed []byte, err error) {
+	defer err2.Handle(&err, "envelope: pack")
+
+	if len(recipientKeys) == 0 {
+		return nil, errors.New("no recipients")
+	}
+	var senderPriv []byte
+	alg := AlgAnoncrypt
+	if senderKey != "" && senderKey != nullStringKey {
+		senderPriv = x25519.PrivateKey(try.To1(keys.PrivateKey(senderKey)))
+		alg = AlgAuthcrypt
+	}
+
+	cek := try.To1(random(cekSize))
+	protected := Protected{Enc: Enc, Typ: Typ, Alg: alg}
+	for _, recipientKey := range recipientKeys {
+		recipient := try.To1(encryptKey(cek, recipientKey, senderKey, senderPriv))
+		protected.Recipients = append(protected.Recipients, recipient)
+	}
+	protectedJSON := encode(try.To1(json.Marshal(protected)))
+
+	aead := try.To1(chacha20poly1305.NewX(cek))
+	iv := try.To1(random(nonceSize))
+	sealed := aead.Seal(nil, iv, msg, []byte(protectedJSON))
+	tagStart := len(sealed) - aead.Overhead()
+
+	return json.Marshal(JWE{
+		Protected:  protectedJSON,
+		IV:         encode(iv),
+		Ciphertext: encode(sealed[:tagStart]),
+		Tag:        encode(sealed[tagStart:]),
+	})
+}
+
+func encryptKey(cek []byte, recipientKey, senderKey string, senderPriv []byte) (r Recipient, err error) {
+	defer err2.Handle(&err, "recipient %s", recipientKey)
+
+	recipientPub := try.To1(x25519Public(recipientKey))
+	if senderPriv == nil {
+		encKey := try.To1(box.SealAnonymous(nil, cek, recipientPub, rand.Reader))
+		return Recipient{EncryptedKey: encode(encKey), Header: Header{Kid: recipientKey}}, nil
+	}
+
+	var nonce [nonceSize]byte
+	copy(nonce[:], try.To1(random(nonceSize)))
+	encKey := box.Seal(nil, cek, &nonce, recipientPub, (*[x25519.KeySize]byte)(senderPriv))
+	sender := try.To1(box.SealAnonymous(nil, []byte(senderKey), recipientPub, rand.Reader))
+	return Recipient{
+		EncryptedKey: encode(encKey),
+		Header: Header{
+			Kid:    recipientKey,
+			IV:     encode(nonce[:]),
+			Sender: encode(sender),
+		},
+	}, nil
+}
+
+// Unpack decrypts the packed message with the first recipient key found from
+// the KeyProvider. The sender of the authcrypted message is authenticated.
+func Unpack(keys KeyProvider, packed []byte) (unpacked *Unpacked, err error) {
+	defer err2.Handle(&err, "envelope: unpack")
+
+	var jwe JWE
+	try.To(json.Unmarshal(packed, &jwe))
+	var protected Protected
+	try.To(json.Unmarshal(try.To1(decode(jwe.Protected)), &protected))
+	if protected.Enc != Enc {
+		return nil, fmt.Errorf("unsupported enc %q", protected.Enc)
+	}
+	if protected.Alg != AlgAuthcrypt && protected.Alg != AlgAnoncrypt {
+		return nil, fmt.Errorf("unsupported alg %q", protected.Alg)
+	}
+
+	for _, recipient := range protected.Recipients {
+		priv, err := keys.PrivateKey(recipient.Header.Kid)
+		if errors.Is(err, ErrKeyNotFound) {
+			continue
+		}
+		try.To(err)
+
+		cek, senderKey := try.To2(decryptKey(protected.Alg, recipient, priv))
+		msg := try.To1(decryptMessage(jwe, cek))
+		return &Unpacked{
+			Message:         string(msg),
+			RecipientVerkey: recipient.Header.Kid,
+			SenderVerkey:    senderKey,
+		}, nil
+	}
+	return nil, ErrNoRecipient
+}
+
+func decryptKey(alg string, recipient Recipient, priv ed25519.PrivateKey) (cek []byte, senderKey string, err error) {
+	defer err2.Handle(&err, "recipient %s", recipient.Header.Kid)
+
+	var pub, privX [x25519.KeySize]byte
+	copy(privX[:], x25519.PrivateKey(priv))
+	copy(pub[:], try.To1(x25519.PublicKey(priv.Public().(ed25519.PublicKey))))
+	encKey := try.To1(decode(recipient.EncryptedKey))
+
+	var ok bool
+	if alg == AlgAnoncrypt {
+		if cek, ok = box.OpenAnonymous(nil, encKey, &pub, &privX); !ok {
+			return nil, "", errors.New("cannot decrypt key")
+		}
+		return cek, "", nil
+	}
+
+	if recipient.Header.Sender == "" || recipient.Header.IV == "" {
+		return nil, "", errors.New("sender and iv are required in authcrypt")
+	}
+	sender, ok := box.OpenAnonymous(nil, try.To1(decode(recipient.Header.Sender)), &pub, &privX)
+	if !ok {
+		return nil, "", errors.New("cannot decrypt sender")
+	}
+	senderKey = string(sender)
+	senderPub := try.To1(x25519Public(senderKey))
+	iv := try.To1(decode(recipient.Header.IV))
+	if len(iv) != nonceSize {
+		return nil, "", fmt.Errorf("invalid iv length %d", len(iv))
+	}
+	cek, ok = box.Open(nil, encKey, (*[nonceSize]byte)(iv), senderPub, &privX)
+	if !ok {
+		return nil, "", errors.New("cannot decrypt key")
+	}
+	return cek, senderKey, nil
+}
+
+func decryptMessage(jwe JWE, cek []byte) (msg []byte, err error) {
+	defer err2.Handle(&err, "message")
+
+	if len(cek) != cekSize {
+		return nil, fmt.Errorf("invalid key length %d", len(cek))
+	}
+	aead := try.To1(chacha20poly1305.NewX(cek))
+	iv := try.To1(decode(jwe.IV))
+	if len(iv) != aead.NonceSize() {
+		return nil, fmt.Errorf("invalid iv length %d", len(iv))
+	}
+	ciphertext := try.To1(decode(jwe.Ciphertext))
+	tag := try.To1(decode(jwe.Tag))
+	return aead.Open(nil, iv, append(ciphertext, tag...), []byte(jwe.Protected))
+}
+
+func x25519Public(verKey string) (*[x25519.KeySize]byte, error) {
+	pub, err := PublicKey(verKey)
+	if err != nil {
+		return nil, err
+	}
+	x, err := x25519.PublicKey(pub)
+	if err != nil {
+		return nil, err
+	}
+	return (*[x25519.KeySize]byte)(x), nil
+}
+
+func random(n int) ([]byte, error) {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		return nil, err
+	}
+	return b, nil
+}
+
+// encode encodes like libindy: base64url with padding.
+func encode(b []byte) string {
+	return base64.URLEncoding.EncodeToString(b)
+}
+
+// decode accepts base64url with and without padding, because other Aries
+// agents don't pad.
+func decode(s string) ([]byte, error) {
+	return base64.RawURLEncoding.DecodeString(strings.TrimRight(s, "="))
+}
@@ -0,0 +1,112 @@
+package envelope
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/lainio/err2"
+	"github.com/lainio/err2/try"
+	"golang.org/x/crypto/chacha20poly1305"
+)
+
+// Info is the information of the packed message which is available without
+// decrypting it. Mediators use it to route the message by the recipient kids.
+type Info struct {
+	Protected Protected
+
+	// Size is the size of the packed message, and CiphertextSize is the size
+	// of the encrypted message, which is the size of the original message.
+	Size           int
+	CiphertextSize int
+}
+
+// Alg returns AlgAuthcrypt or AlgAnoncrypt.
+func (i *Info) Alg() string {
+	return i.Protected.Alg
+}
+
+// HasSender tells if the sender is authenticated, i.e. the message is
+// authcrypted. The sender's verkey is encrypted to the recipients.
+func (i *Info) HasSender() bool {
+	return i.Protected.Alg == AlgAuthcrypt
+}
+
+// Kids returns the recipient verkeys.
+func (i *Info) Kids() []string {
+	kids := make([]string, len(i.Protected.Recipients))
+	for j, r := range i.Protected.Recipients {
+		kids[j] = r.Header.Kid
+	}
+	return kids
+}
+
+// IsRecipient tells if the verkey is one of the recipients.
+func (i *Info) IsRecipient(verKey string) bool {
+	for _, r := range i.Protected.Recipients {
+		if r.Header.Kid == verKey {
+			return true
+		}
+	}
+	return false
+}
+
+// Parse parses and validates the packed message without decrypting it.
+func Parse(packed []byte) (info *Info, err error) {
+	defer err2.Handle(&err, "envelope: parse")
+
+	var jwe JWE
+	try.To(json.Unmarshal(packed, &jwe))
+	info = &Info{Size: len(packed)}
+	try.To(json.Unmarshal(try.To1(decode(jwe.Protected)), &info.Protected))
+	try.To(info.Protected.validate())
+
+	if iv := try.To1(decode(jwe.IV)); len(iv) != chacha20poly1305.NonceSizeX {
+		return nil, fmt.Errorf("invalid iv length %d", len(iv))
+	}
+	if tag := try.To1(decode(jwe.Tag)); len(tag) != chacha20poly1305.Overhead {
+		return nil, fmt.Errorf("invalid tag length %d", len(tag))
+	}
+	info.CiphertextSize = len(try.To1(decode(jwe.Ciphertext)))
+	return info, nil
+}
+
+func (p *Protected) validate() error {
+	if p.Enc != Enc {
+		return fmt.Errorf("unsupported enc %q", p.Enc)
+	}
+	if p.Alg != AlgAuthcrypt && p.Alg != AlgAnoncrypt {
+		return fmt.Errorf("unsupported alg %q", p.Alg)
+	}
+	if len(p.Recipients) == 0 {
+		return errors.New("no recipients")
+	}
+	for _, r := range p.Recipients {
+		if err := r.validate(p.Alg); err != nil {
+			return fmt.Errorf("recipient %s: %w", r.Header.Kid, err)
+		}
+	}
+	return nil
+}
+
+func (r *Recipient) validate(alg string) error {
+	if _, err := PublicKey(r.Header.Kid); err != nil {
+		return err
+	}
+	if _, err := decode(r.EncryptedKey); err != nil || r.EncryptedKey == "" {
+		return errors.New("invalid encrypted_key")
+	}
+	if alg == AlgAnoncrypt {
+		if r.Header.Sender != "" || r.Header.IV != "" {
+			return errors.New("sender and iv aren't allowed in anoncrypt")
+		}
+		return nil
+	}
+	if _, err := decode(r.Header.Sender); err != nil || r.Header.Sender == "" {
+		return errors.New("invalid sender")
+	}
+	if iv, err := decode(r.Header.IV); err != nil || len(iv) != nonceSize {
+		return errors.New("invalid iv")
+	}
+	return nil
+}
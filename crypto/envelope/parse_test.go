@@ -0,0 +1,111 @@
+package envelope
+
+import (
+	"crypto/ed25519"
+	"encoding/json"
+	"testing"
+
+	"github.com/lainio/err2/assert"
+)
+
+func TestParse(t *testing.T) {
+	defer assert.PushTester(t)()
+
+	alice := NewKeys(aliceKey)
+	aliceVerKey := VerKey(aliceKey.Public().(ed25519.PublicKey))
+	bobVerKey := VerKey(bobKey.Public().(ed25519.PublicKey))
+	carolVerKey := VerKey(carolKey.Public().(ed25519.PublicKey))
+	msg := []byte(`{"@type":"https://didcomm.org/basicmessage/1.0/message"}`)
+
+	packed, err := Pack(alice, aliceVerKey, msg, bobVerKey, carolVerKey)
+	assert.NoError(err)
+	info, err := Parse(packed)
+	assert.NoError(err)
+	assert.Equal(info.Alg(), AlgAuthcrypt)
+	assert.That(info.HasSender())
+	assert.DeepEqual(info.Kids(), []string{bobVerKey, carolVerKey})
+	assert.That(info.IsRecipient(carolVerKey))
+	assert.ThatNot(info.IsRecipient(aliceVerKey))
+	assert.Equal(info.Size, len(packed))
+	assert.Equal(info.CiphertextSize, len(msg))
+
+	packed, err = Pack(nil, "", msg, bobVerKey)
+	assert.NoError(err)
+	info, err = Parse(packed)
+	assert.NoError(err)
+	assert.Equal(info.Alg(), AlgAnoncrypt)
+	assert.ThatNot(info.HasSender())
+
+	tests := []struct {
+		name   string
+		modify func(p *Protected, jwe *JWE)
+	}{
+		{"enc", func(p *Protected, _ *JWE) { p.Enc = "A256GCM" }},
+		{"alg", func(p *Protected, _ *JWE) { p.Alg = "ECDH-ES" }},
+		{"no recipients", func(p *Protected, _ *JWE) { p.Recipients = nil }},
+		{"kid", func(p *Protected, _ *JWE) { p.Recipients[0].Header.Kid = "did:sov:123" }},
+		{"encrypted_key", func(p *Protected, _ *JWE) { p.Recipients[0].EncryptedKey = "" }},
+		{"sender in anoncrypt", func(p *Protected, _ *JWE) { p.Recipients[0].Header.Sender = "c2VuZGVy" }},
+		{"iv", func(_ *Protected, jwe *JWE) { jwe.IV = "AAAA" }},
+		{"tag", func(_ *Protected, jwe *JWE) { jwe.Tag = "" }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			defer assert.PushTester(t)()
+
+			var jwe JWE
+			assert.NoError(json.Unmarshal(packed, &jwe))
+			var protected Protected
+			assert.NoError(json.Unmarshal(mustDecode(jwe.Protected), &protected))
+			tt.modify(&protected, &jwe)
+			data, err := json.Marshal(protected)
+			assert.NoError(err)
+			jwe.Protected = encode(data)
+			invalid, err := json.Marshal(jwe)
+			assert.NoError(err)
+
+			_, err = Parse(invalid)
+			assert.Error(err)
+		})
+	}
+}
+
+func TestWrapUnwrap(t *testing.T) {
+	defer assert.PushTester(t)()
+
+	mediator1 := NewKeys(carolKey)
+	mediator1Key := VerKey(carolKey.Public().(ed25519.PublicKey))
+	mediator2 := make(Keys)
+	mediator2Key := mediator2.Add(ed25519.NewKeyFromSeed([]byte("00000000000000000000000Mediator2")))
+	bob := NewKeys(bobKey)
+	bobVerKey := VerKey(bobKey.Public().(ed25519.PublicKey))
+	msg := []byte(`{"@type":"https://didcomm.org/trust_ping/1.0/ping"}`)
+
+	packed, err := Pack(nil, "", msg, bobVerKey)
+	assert.NoError(err)
+	wrapped, err := Wrap(packed, bobVerKey, mediator1Key, mediator2Key)
+	assert.NoError(err)
+
+	// the outermost forward is for the mediator 2, and it's to the mediator 1
+	info, err := Parse(wrapped)
+	assert.NoError(err)
+	assert.DeepEqual(info.Kids(), []string{mediator2Key})
+	fwd, _, err := Unwrap(mediator2, wrapped)
+	assert.NoError(err)
+	assert.Equal(fwd.To, mediator1Key)
+
+	fwd, _, err = Unwrap(mediator1, fwd.Msg)
+	assert.NoError(err)
+	assert.Equal(fwd.To, bobVerKey)
+	assert.Equal(string(fwd.Msg), string(packed))
+
+	// the message isn't a forward for the final recipient
+	fwd, unpacked, err := Unwrap(bob, fwd.Msg)
+	assert.NoError(err)
+	assert.That(fwd == nil)
+	assert.Equal(unpacked.Message, string(msg))
+
+	unwrapped, err := Wrap(packed, bobVerKey)
+	assert.NoError(err)
+	assert.Equal(string(unwrapped), string(packed))
+}
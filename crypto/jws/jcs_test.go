@@ -0,0 +1,64 @@
+package jws
+
+import (
+	"testing"
+
+	"github.com/lainio/err2/assert"
+)
+
+// The test vectors of RFC 8785.
+func TestCanonicalize(t *testing.T) {
+	defer assert.PushTester(t)()
+
+	tests := []struct {
+		name, in, want string
+	}{
+		{"example",
+			`{"numbers": [333333333.33333329, 1E30, 4.50, 2e-3, 0.000000000000000000000000001],
+			  "string": "\u20ac$\u000F\u000aA'\u0042\u0022\u005c\\\"\/",
+			  "literals": [null, true, false]}`,
+			`{"literals":[null,true,false],"numbers":[333333333.3333333,1e+30,4.5,0.002,1e-27],` +
+				`"string":"€$\u000f\nA'B\"\\\\\"/"}`},
+		{"sorting",
+			`{"\u20ac": "Euro Sign", "\r": "Carriage Return", "\ufb33": "Hebrew Letter Dalet With Dagesh",
+			  "1": "One", "\ud83d\ude00": "Emoji: Grinning Face", "\u0080": "Control",
+			  "\u00f6": "Latin Small Letter O With Diaeresis"}`,
+			"{\"\\r\":\"Carriage Return\",\"1\":\"One\",\"\u0080\":\"Control\"," +
+				"\"\u00f6\":\"Latin Small Letter O With Diaeresis\",\"\u20ac\":\"Euro Sign\"," +
+				"\"\U0001f600\":\"Emoji: Grinning Face\",\"\ufb33\":\"Hebrew Letter Dalet With Dagesh\"}"},
+		{"nested", `{ "b": [ {"d": 1, "c": {}} ], "a": "" }`, `{"a":"","b":[{"c":{},"d":1}]}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			defer assert.PushTester(t)()
+			got, err := Canonicalize([]byte(tt.in))
+			assert.NoError(err)
+			assert.Equal(string(got), tt.want)
+		})
+	}
+
+	_, err := Canonicalize([]byte(`{"a":1} {}`))
+	assert.Error(err)
+}
+
+func TestFormatNumber(t *testing.T) {
+	defer assert.PushTester(t)()
+
+	for f, want := range map[float64]string{
+		0:                      "0",
+		-1:                     "-1",
+		1e20:                   "100000000000000000000",
+		1e21:                   "1e+21",
+		123e-9:                 "1.23e-7",
+		0.000001:               "0.000001",
+		-5e-324:                "-5e-324",
+		1.7976931348623157e308: "1.7976931348623157e+308",
+		9007199254740992:       "9007199254740992",
+		295147905179352830000:  "295147905179352830000",
+		0.1:                    "0.1",
+	} {
+		got, err := formatNumber(f)
+		assert.NoError(err)
+		assert.Equal(got, want)
+	}
+}
@@ -0,0 +1,259 @@
+/*
+Package jws creates and verifies compact and detached JSON Web Signatures
+(RFC 7515, RFC 7797) and JWTs with the EdDSA algorithm. The signer is usually
+the wallet: NewSigner and NewDIDSigner sign with crypto.SignMsg, and the key ID
+(kid) is the verkey or the DID. Structured payloads are canonicalized with JCS
+(RFC 8785) before signing.
+
+The signatures are verified either in pure Go with Verify or through libindy
+with VerifyIndy. Both resolve the kid to the verkey with a KeyResolver.
+
+	signer, err := jws.NewDIDSigner(wallet, myDID)
+	token, err := jws.SignJSON(signer, payload)
+	...
+	header, payload, err := jws.Verify(token, jws.WalletResolver(wallet))
+*/
+package jws
+
+import (
+	"crypto/ed25519"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/findy-network/findy-wrapper-go/crypto"
+	"github.com/findy-network/findy-wrapper-go/did"
+	"github.com/findy-network/findy-wrapper-go/identifiers"
+	"github.com/findy-network/findy-wrapper-go/internal/base58"
+	"github.com/lainio/err2"
+	"github.com/lainio/err2/try"
+)
+
+// AlgEdDSA is the only supported algorithm.
+const AlgEdDSA = "EdDSA"
+
+// ErrInvalidSignature is returned when the signature doesn't verify.
+var ErrInvalidSignature = errors.New("jws: invalid signature")
+
+// Signer signs with the key of the key ID. crypto.WalletSigner implements it.
+type Signer interface {
+	KeyID() string
+	Sign(data []byte) ([]byte, error)
+}
+
+// Header is the protected JOSE header. B64 and Crit are set for the
+// unencoded payloads of RFC 7797.
+type Header struct {
+	Alg  string   `json:"alg"`
+	Kid  string   `json:"kid,omitempty"`
+	Typ  string   `json:"typ,omitempty"`
+	B64  *bool    `json:"b64,omitempty"`
+	Crit []string `json:"crit,omitempty"`
+}
+
+// KeyResolver returns the base58 verkey of the kid.
+type KeyResolver func(kid string) (verKey string, err error)
+
+// NewSigner returns a Signer for the wallet's verkey. The kid is the verkey.
+func NewSigner(wallet int, verKey string) Signer {
+	return crypto.WalletSigner{Wallet: wallet, VerKey: verKey, Kid: verKey}
+}
+
+// NewDIDSigner returns a Signer for the DID's verkey in the wallet. The kid
+// is the DID as it's given, e.g. did:sov:<id>.
+func NewDIDSigner(wallet int, didName string) (s Signer, err error) {
+	defer err2.Handle(&err, "jws: signer %s", didName)
+
+	d := try.To1(identifiers.ParseDID(didName))
+	r := <-did.LocalKey(wallet, d.Unqualify().String())
+	try.To(r.Err())
+	return crypto.WalletSigner{Wallet: wallet, VerKey: r.Str1(), Kid: didName}, nil
+}
+
+// WalletResolver resolves the DIDs with did.LocalKey. Other kids are taken as
+// verkeys. The DID URL fragment is ignored.
+func WalletResolver(wallet int) KeyResolver {
+	return func(kid string) (string, error) {
+		didName, _, _ := strings.Cut(kid, "#")
+		d, err := identifiers.ParseDID(didName)
+		if err != nil || (d.Method == identifiers.Unqualified && !isDID(didName)) {
+			return VerKeyResolver(kid)
+		}
+		r := <-did.LocalKey(wallet, d.Unqualify().String())
+		if r.Err() != nil {
+			return "", r.Err()
+		}
+		return r.Str1(), nil
+	}
+}
+
+// VerKeyResolver resolves the kids which are verkeys.
+func VerKeyResolver(kid string) (string, error) {
+	if pub, err := base58.Decode(kid); err != nil || len(pub) != ed25519.PublicKeySize {
+		return "", fmt.Errorf("jws: kid %q isn't a verkey", kid)
+	}
+	return kid, nil
+}
+
+// isDID tells if the unqualified ID is a DID rather than a verkey, which is
+// 32 bytes in base58.
+func isDID(id string) bool {
+	b, err := base58.Decode(id)
+	return err == nil && len(b) == 16
+}
+
+// Sign creates the compact JWS of the payload.
+func Sign(signer Signer, payload []byte) (string, error) {
+	return sign(signer, Header{Alg: AlgEdDSA, Kid: signer.KeyID()}, encode(payload))
+}
+
+// SignJSON creates the compact JWS of the JCS canonicalized JSON of the value.
+func SignJSON(signer Signer, v any) (jws string, err error) {
+	defer err2.Handle(&err, "jws: sign")
+
+	return Sign(signer, try.To1(CanonicalizeValue(v)))
+}
+
+// SignDetached creates the detached JWS of the unencoded payload (RFC 7797),
+// i.e. header..signature. It's the format of e.g. Ed25519Signature2018 proofs.
+func SignDetached(signer Signer, payload []byte) (jws string, err error) {
+	defer err2.Handle(&err, "jws: sign")
+
+	b64 := false
+	h := Header{Alg: AlgEdDSA, Kid: signer.KeyID(), B64: &b64, Crit: []string{"b64"}}
+	protected := encode(try.To1(json.Marshal(h)))
+	sig := try.To1(signer.Sign(append([]byte(protected+"."), payload...)))
+	return protected + ".." + encode(sig), nil
+}
+
+func sign(signer Signer, h Header, payload string) (jws string, err error) {
+	defer err2.Handle(&err, "jws: sign")
+
+	protected := encode(try.To1(json.Marshal(h)))
+	signingInput := protected + "." + payload
+	sig := try.To1(signer.Sign([]byte(signingInput)))
+	return signingInput + "." + encode(sig), nil
+}
+
+// Verify verifies the compact JWS in pure Go and returns the protected header
+// and the payload.
+func Verify(jws string, resolve KeyResolver) (h *Header, payload []byte, err error) {
+	return verify(jws, nil, resolve, verifyEd25519)
+}
+
+// VerifyDetached verifies the detached JWS of the payload in pure Go.
+func VerifyDetached(jws string, payload []byte, resolve KeyResolver) (h *Header, err error) {
+	h, _, err = verify(jws, payload, resolve, verifyEd25519)
+	return h, err
+}
+
+// VerifyIndy is like Verify but the signature is verified with libindy's
+// crypto.VerifySignature.
+func VerifyIndy(jws string, resolve KeyResolver) (h *Header, payload []byte, err error) {
+	return verify(jws, nil, resolve, verifyIndy)
+}
+
+// VerifyDetachedIndy is like VerifyDetached but the signature is verified
+// with libindy's crypto.VerifySignature.
+func VerifyDetachedIndy(jws string, payload []byte, resolve KeyResolver) (h *Header, err error) {
+	h, _, err = verify(jws, payload, resolve, verifyIndy)
+	return h, err
+}
+
+type verifyFunc func(verKey string, msg, sig []byte) (bool, error)
+
+func verifyEd25519(verKey string, msg, sig []byte) (bool, error) {
+	pub, err := base58.Decode(verKey)
+	if err != nil || len(pub) != ed25519.PublicKeySize {
+		return false, fmt.Errorf("invalid verkey %q", verKey)
+	}
+	return ed25519.Verify(pub, msg, sig), nil
+}
+
+func verifyIndy(verKey string, msg, sig []byte) (bool, error) {
+	r := <-crypto.VerifySignature(verKey, msg, sig)
+	return r.Yes(), r.Err()
+}
+
+func verify(jws string, detached []byte, resolve KeyResolver, verifySig verifyFunc) (
+	h *Header, payload []byte, err error,
+) {
+	defer err2.Handle(&err, "jws: verify")
+
+	parts := strings.Split(jws, ".")
+	if len(parts) != 3 {
+		return nil, nil, errors.New("compact JWS must have three parts")
+	}
+	h = new(Header)
+	try.To(json.Unmarshal(try.To1(decode(parts[0])), h))
+	if h.Alg != AlgEdDSA {
+		return nil, nil, fmt.Errorf("unsupported alg %q", h.Alg)
+	}
+	try.To(checkCrit(h))
+	encoded := h.B64 == nil || *h.B64
+	if !encoded && !contains(h.Crit, "b64") {
+		return nil, nil, errors.New("b64 must be in crit")
+	}
+
+	signingPayload := parts[1]
+	switch {
+	case detached != nil && parts[1] != "":
+		return nil, nil, errors.New("detached JWS has a payload")
+	case detached != nil && encoded:
+		signingPayload = encode(detached)
+	case detached != nil:
+		signingPayload = string(detached)
+	case !encoded:
+		return nil, nil, errors.New("unencoded payload must be detached")
+	}
+
+	verKey := try.To1(resolve(h.Kid))
+	sig := try.To1(decode(parts[2]))
+	if !try.To1(verifySig(verKey, []byte(parts[0]+"."+signingPayload), sig)) {
+		return nil, nil, ErrInvalidSignature
+	}
+	if detached != nil {
+		return h, detached, nil
+	}
+	return h, try.To1(decode(parts[1])), nil
+}
+
+// checkCrit checks the crit header of RFC 7515 section 4.1.11. The crit list
+// must not be empty, and it must name only the understood extensions, which
+// are present in the header. Only b64 of RFC 7797 is understood.
+func checkCrit(h *Header) error {
+	if h.Crit == nil {
+		return nil
+	}
+	if len(h.Crit) == 0 {
+		return errors.New("crit is empty")
+	}
+	for _, name := range h.Crit {
+		switch {
+		case name != "b64":
+			return fmt.Errorf("unsupported crit %q", name)
+		case h.B64 == nil:
+			return fmt.Errorf("crit %q is not in header", name)
+		}
+	}
+	return nil
+}
+
+func contains(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
+
+func encode(b []byte) string {
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+func decode(s string) ([]byte, error) {
+	return base64.RawURLEncoding.DecodeString(s)
+}
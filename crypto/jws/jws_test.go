@@ -0,0 +1,199 @@
+package jws
+
+import (
+	"crypto/ed25519"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/lainio/err2/assert"
+
+	"github.com/findy-network/findy-wrapper-go/crypto"
+	"github.com/findy-network/findy-wrapper-go/did"
+	"github.com/findy-network/findy-wrapper-go/helpers"
+	"github.com/findy-network/findy-wrapper-go/internal/base58"
+)
+
+// keySigner signs with a raw key. The kid is the verkey.
+type keySigner ed25519.PrivateKey
+
+func (s keySigner) KeyID() string {
+	return base58.Encode(ed25519.PrivateKey(s).Public().(ed25519.PublicKey))
+}
+
+func (s keySigner) Sign(data []byte) ([]byte, error) {
+	return ed25519.Sign(ed25519.PrivateKey(s), data), nil
+}
+
+// rfc8037Key is the Ed25519 key of RFC 8037 appendix A.
+var rfc8037Key = keySigner(ed25519.NewKeyFromSeed(
+	mustDecode("nWGxne_9WmC6hEr0kuwsxERJxWl7MmkZcDusAxyuf2A")))
+
+func TestSign_rfc8037(t *testing.T) {
+	defer assert.PushTester(t)()
+
+	jws, err := sign(rfc8037Key, Header{Alg: AlgEdDSA}, encode([]byte("Example of Ed25519 signing")))
+	assert.NoError(err)
+	assert.Equal(jws, "eyJhbGciOiJFZERTQSJ9.RXhhbXBsZSBvZiBFZDI1NTE5IHNpZ25pbmc."+
+		"hgyY0il_MGCjP0JzlnLWG1PPOt7-09PGcvMg3AIbQR6dWbhijcNR4ki4iylGjg5BhVsPt9g7sVvpAr_MuM0KAg")
+
+	verKey := rfc8037Key.KeyID()
+	h, payload, err := Verify(jws, func(string) (string, error) { return verKey, nil })
+	assert.NoError(err)
+	assert.Equal(h.Kid, "")
+	assert.Equal(string(payload), "Example of Ed25519 signing")
+}
+
+func TestSignVerify(t *testing.T) {
+	defer assert.PushTester(t)()
+
+	payload := map[string]any{"b": 2, "a": []any{"x", 1.5}}
+	jws, err := SignJSON(rfc8037Key, payload)
+	assert.NoError(err)
+	h, data, err := Verify(jws, VerKeyResolver)
+	assert.NoError(err)
+	assert.Equal(h.Kid, rfc8037Key.KeyID())
+	assert.Equal(string(data), `{"a":["x",1.5],"b":2}`)
+
+	detached, err := SignDetached(rfc8037Key, data)
+	assert.NoError(err)
+	_, err = VerifyDetached(detached, data, VerKeyResolver)
+	assert.NoError(err)
+	_, err = VerifyDetached(detached, []byte(`{"a":[],"b":2}`), VerKeyResolver)
+	assert.That(errors.Is(err, ErrInvalidSignature))
+	_, _, err = Verify(detached, VerKeyResolver)
+	assert.Error(err)
+
+	other := keySigner(ed25519.NewKeyFromSeed(make([]byte, ed25519.SeedSize)))
+	_, _, err = Verify(jws, func(string) (string, error) { return other.KeyID(), nil })
+	assert.That(errors.Is(err, ErrInvalidSignature))
+	_, _, err = Verify("a.b", VerKeyResolver)
+	assert.Error(err)
+	_, err = VerKeyResolver("did:sov:5Lgx4KLRTNgexDqT7WDALu")
+	assert.Error(err)
+}
+
+func TestVerify_crit(t *testing.T) {
+	defer assert.PushTester(t)()
+
+	signRaw := func(header string) string {
+		input := encode([]byte(header)) + "." + encode([]byte("payload"))
+		sig, err := rfc8037Key.Sign([]byte(input))
+		assert.NoError(err)
+		return input + "." + encode(sig)
+	}
+	verKey := func(string) (string, error) { return rfc8037Key.KeyID(), nil }
+
+	_, payload, err := Verify(signRaw(`{"alg":"EdDSA","b64":true,"crit":["b64"]}`), verKey)
+	assert.NoError(err)
+	assert.Equal(string(payload), "payload")
+
+	tests := []struct {
+		name   string
+		header string
+	}{
+		{"empty", `{"alg":"EdDSA","crit":[]}`},
+		{"unknown", `{"alg":"EdDSA","exp":1,"crit":["exp"]}`},
+		{"unknown with b64", `{"alg":"EdDSA","b64":true,"crit":["b64","x"]}`},
+		{"not in header", `{"alg":"EdDSA","crit":["b64"]}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			defer assert.PushTester(t)()
+			_, _, err := Verify(signRaw(tt.header), verKey)
+			assert.Error(err)
+		})
+	}
+}
+
+func TestJWT(t *testing.T) {
+	defer assert.PushTester(t)()
+
+	defer func() { now = time.Now }()
+	now = func() time.Time { return time.Unix(1700000000, 0) }
+
+	type claims struct {
+		Claims
+		Name string `json:"name"`
+	}
+	token, err := SignJWT(rfc8037Key, claims{
+		Claims: Claims{Issuer: rfc8037Key.KeyID(), ExpiresAt: 1700000100, IssuedAt: 1700000000},
+		Name:   "Alice",
+	})
+	assert.NoError(err)
+
+	var got claims
+	h, err := VerifyJWT(token, VerKeyResolver, &got)
+	assert.NoError(err)
+	assert.Equal(h.Typ, TypJWT)
+	assert.Equal(got.Name, "Alice")
+	assert.Equal(got.Issuer, rfc8037Key.KeyID())
+
+	now = func() time.Time { return time.Unix(1700000200, 0) }
+	_, err = VerifyJWT(token, VerKeyResolver, &got)
+	assert.That(errors.Is(err, ErrExpired))
+}
+
+func TestAudience(t *testing.T) {
+	defer assert.PushTester(t)()
+
+	var c Claims
+	assert.NoError(json.Unmarshal([]byte(`{"aud":"did:example:bob"}`), &c))
+	assert.DeepEqual(c.Audience, Audience{"did:example:bob"})
+	assert.That(c.Audience.Contains("did:example:bob"))
+	data, err := json.Marshal(c)
+	assert.NoError(err)
+	assert.Equal(string(data), `{"aud":"did:example:bob"}`)
+
+	c = Claims{}
+	assert.NoError(json.Unmarshal([]byte(`{"aud":["did:example:bob","did:example:carol"]}`), &c))
+	assert.DeepEqual(c.Audience, Audience{"did:example:bob", "did:example:carol"})
+	assert.That(c.Audience.Contains("did:example:carol"))
+	assert.ThatNot(c.Audience.Contains("did:example:alice"))
+	data, err = json.Marshal(c)
+	assert.NoError(err)
+	assert.Equal(string(data), `{"aud":["did:example:bob","did:example:carol"]}`)
+
+	data, err = json.Marshal(Claims{})
+	assert.NoError(err)
+	assert.Equal(string(data), `{}`)
+	assert.Error(json.Unmarshal([]byte(`{"aud":1}`), &c))
+}
+
+func TestWalletSigner(t *testing.T) {
+	defer assert.PushTester(t)()
+
+	w, name := helpers.CreateAndOpenTestWallet(t)
+	r := <-did.CreateAndStore(w, did.Did{Seed: "00000000000000000000000000Alice1"})
+	assert.NoError(r.Err())
+	myDID, verKey := r.Str1(), r.Str2()
+
+	signer, err := NewDIDSigner(w, "did:sov:"+myDID)
+	assert.NoError(err)
+	assert.Equal(signer.(crypto.WalletSigner).VerKey, verKey)
+	token, err := SignJSON(signer, map[string]string{"hello": "world"})
+	assert.NoError(err)
+
+	h, payload, err := VerifyIndy(token, WalletResolver(w))
+	assert.NoError(err)
+	assert.Equal(h.Kid, "did:sov:"+myDID)
+	assert.Equal(string(payload), `{"hello":"world"}`)
+	_, _, err = Verify(token, WalletResolver(w))
+	assert.NoError(err)
+
+	detached, err := SignDetached(NewSigner(w, verKey), payload)
+	assert.NoError(err)
+	_, err = VerifyDetachedIndy(detached, payload, WalletResolver(w))
+	assert.NoError(err)
+
+	helpers.CloseAndDeleteTestWallet(w, name, t)
+}
+
+func mustDecode(s string) []byte {
+	b, err := decode(s)
+	if err != nil {
+		panic(err)
+	}
+	return b
+}
@@ -0,0 +1,106 @@
+package jws
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/lainio/err2"
+	"github.com/lainio/err2/try"
+)
+
+// TypJWT is the typ of the JWT header.
+const TypJWT = "JWT"
+
+// ErrExpired is returned when the JWT is expired or not yet valid.
+var ErrExpired = errors.New("jwt: expired or not yet valid")
+
+// Claims are the registered claims of RFC 7519. Embed it to the custom claims
+// struct to get the time checks in VerifyJWT.
+type Claims struct {
+	Issuer    string   `json:"iss,omitempty"`
+	Subject   string   `json:"sub,omitempty"`
+	Audience  Audience `json:"aud,omitempty"`
+	ExpiresAt int64    `json:"exp,omitempty"`
+	NotBefore int64    `json:"nbf,omitempty"`
+	IssuedAt  int64    `json:"iat,omitempty"`
+	ID        string   `json:"jti,omitempty"`
+}
+
+// Audience is the aud claim. It's a string or an array of strings in the JWT
+// (RFC 7519 section 4.1.3), and one audience is marshaled as a string.
+type Audience []string
+
+// MarshalJSON marshals one audience as a string and others as an array.
+func (a Audience) MarshalJSON() ([]byte, error) {
+	if len(a) == 1 {
+		return json.Marshal(a[0])
+	}
+	return json.Marshal([]string(a))
+}
+
+// UnmarshalJSON accepts a string or an array of strings.
+func (a *Audience) UnmarshalJSON(data []byte) error {
+	var aud string
+	if err := json.Unmarshal(data, &aud); err == nil {
+		*a = Audience{aud}
+		return nil
+	}
+	var auds []string
+	if err := json.Unmarshal(data, &auds); err != nil {
+		return fmt.Errorf("jwt: aud must be a string or an array of strings: %w", err)
+	}
+	*a = auds
+	return nil
+}
+
+// Contains tells if the audience has the aud.
+func (a Audience) Contains(aud string) bool {
+	for _, s := range a {
+		if s == aud {
+			return true
+		}
+	}
+	return false
+}
+
+// Leeway is the allowed clock skew in the time checks of the JWT.
+var Leeway = time.Minute
+
+// now is replaced in the tests.
+var now = time.Now
+
+// Valid checks the exp and nbf claims.
+func (c Claims) Valid() error {
+	t := now()
+	if c.ExpiresAt != 0 && t.After(time.Unix(c.ExpiresAt, 0).Add(Leeway)) {
+		return fmt.Errorf("%w: exp %d", ErrExpired, c.ExpiresAt)
+	}
+	if c.NotBefore != 0 && t.Before(time.Unix(c.NotBefore, 0).Add(-Leeway)) {
+		return fmt.Errorf("%w: nbf %d", ErrExpired, c.NotBefore)
+	}
+	return nil
+}
+
+// SignJWT creates the JWT of the claims, which are canonicalized with JCS.
+func SignJWT(signer Signer, claims any) (token string, err error) {
+	defer err2.Handle(&err, "jwt: sign")
+
+	payload := try.To1(CanonicalizeValue(claims))
+	h := Header{Alg: AlgEdDSA, Kid: signer.KeyID(), Typ: TypJWT}
+	return sign(signer, h, encode(payload))
+}
+
+// VerifyJWT verifies the JWT in pure Go, unmarshals its payload to the
+// claims, and checks the exp and nbf claims.
+func VerifyJWT(token string, resolve KeyResolver, claims any) (h *Header, err error) {
+	defer err2.Handle(&err, "jwt: verify")
+
+	h, payload := try.To2(Verify(token, resolve))
+	try.To(json.Unmarshal(payload, claims))
+	var registered Claims
+	try.To(json.Unmarshal(payload, &registered))
+	try.To(registered.Valid())
+	return h, nil
+}
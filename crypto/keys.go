@@ -0,0 +1,173 @@
+package crypto
+
+import (
+	"crypto/ed25519"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/findy-network/findy-wrapper-go/dto"
+	"github.com/findy-network/findy-wrapper-go/internal/base58"
+	"github.com/findy-network/findy-wrapper-go/internal/c2go"
+	"github.com/findy-network/findy-wrapper-go/internal/ctx"
+	"github.com/findy-network/findy-wrapper-go/internal/verkey"
+	"github.com/findy-network/findy-wrapper-go/internal/x25519"
+	"github.com/lainio/err2"
+	"github.com/lainio/err2/try"
+)
+
+// Key is the configuration of CreateKey. Seed is optional, and it's
+// generated by indy if empty.
+type Key struct {
+	Seed       string `json:"seed,omitempty"`
+	CryptoType string `json:"crypto_type,omitempty"`
+}
+
+// CreateKey creates a new key pair to the wallet without a DID. The verkey is
+// returned in Str1.
+func CreateKey(wallet int, key Key) ctx.Channel {
+	return c2go.FindyCreateKey(wallet, dto.ToJSON(key))
+}
+
+// SetKeyMetadata sets the metadata string of the wallet's key.
+func SetKeyMetadata(wallet int, verKey, meta string) ctx.Channel {
+	return c2go.FindySetKeyMetadata(wallet, verKey, meta)
+}
+
+// KeyMetadata returns the metadata string of the wallet's key in Str1.
+func KeyMetadata(wallet int, verKey string) ctx.Channel {
+	return c2go.FindyGetKeyMetadata(wallet, verKey)
+}
+
+// AbbreviateVerKey returns the abbreviated verkey of the DID in Str1, or the
+// full verkey if it cannot be abbreviated. It's libindy's version of
+// VerKey.Abbreviate.
+func AbbreviateVerKey(did, fullVerKey string) ctx.Channel {
+	return c2go.FindyAbbreviateVerkey(did, fullVerKey)
+}
+
+// NewKey creates a new key pair to the wallet, and sets the metadata if it's
+// not nil. The metadata is stored as JSON, see KeyMeta.
+func NewKey(wallet int, seed string, meta any) (verKey VerKey, err error) {
+	defer err2.Handle(&err, "new key")
+
+	r := <-CreateKey(wallet, Key{Seed: seed})
+	try.To(r.Err())
+	verKey = VerKey(r.Str1())
+	if meta != nil {
+		r = <-SetKeyMetadata(wallet, string(verKey), dto.ToJSON(meta))
+		try.To(r.Err())
+	}
+	return verKey, nil
+}
+
+// KeyMeta reads the JSON metadata of the wallet's key to the meta.
+func KeyMeta(wallet int, verKey VerKey, meta any) (err error) {
+	defer err2.Handle(&err, "key %s metadata", verKey)
+
+	r := <-KeyMetadata(wallet, string(verKey))
+	try.To(r.Err())
+	return json.Unmarshal([]byte(r.Str1()), meta)
+}
+
+// abbreviatedPrefix is the prefix of the abbreviated verkeys.
+const abbreviatedPrefix = verkey.AbbreviatedPrefix
+
+// ErrInvalidVerKey is returned when the verkey isn't a valid full or
+// abbreviated Ed25519 verkey.
+var ErrInvalidVerKey = errors.New("invalid verkey")
+
+// VerKey is the base58 Ed25519 verkey, which can be abbreviated, i.e.
+// ~<base58 of the last 16 bytes>, when the first 16 bytes are the DID.
+type VerKey string
+
+// IsAbbreviated tells if the verkey is abbreviated.
+func (k VerKey) IsAbbreviated() bool {
+	return verkey.IsAbbreviated(string(k))
+}
+
+// Validate checks that the full verkey is 32 bytes, and the abbreviated one
+// 16 bytes.
+func (k VerKey) Validate() error {
+	want := ed25519.PublicKeySize
+	data := strings.TrimPrefix(string(k), abbreviatedPrefix)
+	if k.IsAbbreviated() {
+		want = ed25519.PublicKeySize - 16
+	}
+	b, err := base58.Decode(data)
+	if err != nil {
+		return fmt.Errorf("%w %q: %v", ErrInvalidVerKey, k, err)
+	}
+	if len(b) != want {
+		return fmt.Errorf("%w %q: length %d", ErrInvalidVerKey, k, len(b))
+	}
+	return nil
+}
+
+// Abbreviate returns the abbreviated verkey if the DID is the first 16 bytes
+// of the verkey, otherwise the full verkey like libindy does.
+func (k VerKey) Abbreviate(did string) (VerKey, error) {
+	if k.IsAbbreviated() {
+		return k, k.Validate()
+	}
+	pub, err := k.PublicKey()
+	if err != nil {
+		return "", err
+	}
+	if base58.Encode(pub[:16]) != did {
+		return k, nil
+	}
+	return VerKey(abbreviatedPrefix + base58.Encode(pub[16:])), nil
+}
+
+// Expand returns the full verkey of the abbreviated verkey of the DID. Full
+// verkeys are returned as they are.
+func (k VerKey) Expand(did string) (VerKey, error) {
+	if err := k.Validate(); err != nil {
+		return "", err
+	}
+	full, err := verkey.Expand(did, string(k))
+	if err != nil {
+		return "", fmt.Errorf("%w: %v", ErrInvalidVerKey, err)
+	}
+	return VerKey(full), nil
+}
+
+// PublicKey returns the Ed25519 public key of the full verkey.
+func (k VerKey) PublicKey() (ed25519.PublicKey, error) {
+	if k.IsAbbreviated() {
+		return nil, fmt.Errorf("%w %q: abbreviated, use Expand", ErrInvalidVerKey, k)
+	}
+	if err := k.Validate(); err != nil {
+		return nil, err
+	}
+	b, _ := base58.Decode(string(k))
+	return b, nil
+}
+
+// X25519 converts the full verkey to the X25519 public key like libindy does
+// for its box encryption.
+func (k VerKey) X25519() ([]byte, error) {
+	pub, err := k.PublicKey()
+	if err != nil {
+		return nil, err
+	}
+	return x25519.PublicKey(pub)
+}
+
+// X25519VerKey is like X25519 but the key is returned in base58.
+func (k VerKey) X25519VerKey() (string, error) {
+	x, err := k.X25519()
+	if err != nil {
+		return "", err
+	}
+	return base58.Encode(x), nil
+}
+
+// X25519PrivateKey converts the Ed25519 private key to the X25519 private key
+// like libindy does. Wallet keys cannot be exported, but this is needed for
+// the keys which are derived from known seeds.
+func X25519PrivateKey(priv ed25519.PrivateKey) []byte {
+	return x25519.PrivateKey(priv)
+}
@@ -0,0 +1,92 @@
+package crypto
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"errors"
+	"testing"
+
+	"github.com/lainio/err2/assert"
+	"golang.org/x/crypto/curve25519"
+
+	"github.com/findy-network/findy-wrapper-go/did"
+	"github.com/findy-network/findy-wrapper-go/helpers"
+	"github.com/findy-network/findy-wrapper-go/internal/base58"
+)
+
+func TestVerKey(t *testing.T) {
+	defer assert.PushTester(t)()
+
+	priv := ed25519.NewKeyFromSeed([]byte("000000000000000000000000Steward1"))
+	pub := priv.Public().(ed25519.PublicKey)
+	verKey := VerKey(base58.Encode(pub))
+	myDID := base58.Encode(pub[:16])
+	assert.NoError(verKey.Validate())
+	assert.ThatNot(verKey.IsAbbreviated())
+
+	abbr, err := verKey.Abbreviate(myDID)
+	assert.NoError(err)
+	assert.That(abbr.IsAbbreviated())
+	assert.NoError(abbr.Validate())
+	full, err := abbr.Expand(myDID)
+	assert.NoError(err)
+	assert.Equal(full, verKey)
+
+	other, err := verKey.Abbreviate("V4SGRU86Z58d6TV7PBUe6f")
+	assert.NoError(err)
+	assert.Equal(other, verKey)
+	_, err = abbr.PublicKey()
+	assert.That(errors.Is(err, ErrInvalidVerKey))
+	_, err = abbr.Expand("invalid")
+	assert.That(errors.Is(err, ErrInvalidVerKey))
+	assert.That(errors.Is(VerKey("~abc").Validate(), ErrInvalidVerKey))
+
+	x, err := verKey.X25519()
+	assert.NoError(err)
+	want, err := curve25519.X25519(X25519PrivateKey(priv), curve25519.Basepoint)
+	assert.NoError(err)
+	assert.That(bytes.Equal(x, want))
+	xVerKey, err := verKey.X25519VerKey()
+	assert.NoError(err)
+	assert.Equal(xVerKey, base58.Encode(want))
+}
+
+func TestCreateKey(t *testing.T) {
+	defer assert.PushTester(t)()
+
+	w, name := helpers.CreateAndOpenTestWallet(t)
+
+	type routingMeta struct {
+		Connection string `json:"connection"`
+		Index      int    `json:"index"`
+	}
+	verKey, err := NewKey(w, "", routingMeta{Connection: "conn-1", Index: 1})
+	assert.NoError(err)
+	assert.NoError(verKey.Validate())
+	var meta routingMeta
+	assert.NoError(KeyMeta(w, verKey, &meta))
+	assert.Equal(meta.Connection, "conn-1")
+
+	r := <-CreateKey(w, Key{Seed: "000000000000000000000000Steward1"})
+	assert.NoError(r.Err())
+	seeded := VerKey(r.Str1())
+	priv := ed25519.NewKeyFromSeed([]byte("000000000000000000000000Steward1"))
+	assert.Equal(string(seeded), base58.Encode(priv.Public().(ed25519.PublicKey)))
+
+	r = <-SetKeyMetadata(w, string(seeded), "plain meta")
+	assert.NoError(r.Err())
+	r = <-KeyMetadata(w, string(seeded))
+	assert.NoError(r.Err())
+	assert.Equal(r.Str1(), "plain meta")
+
+	r = <-did.CreateAndStore(w, did.Did{Seed: "00000000000000000000000000Alice1"})
+	assert.NoError(r.Err())
+	myDID, fullVerKey := r.Str1(), r.Str2()
+	r = <-AbbreviateVerKey(myDID, fullVerKey)
+	assert.NoError(r.Err())
+	abbr, err := VerKey(fullVerKey).Abbreviate(myDID)
+	assert.NoError(err)
+	assert.Equal(string(abbr), r.Str1())
+
+	helpers.CloseAndDeleteTestWallet(w, name, t)
+}
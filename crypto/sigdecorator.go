@@ -0,0 +1,120 @@
+package crypto
+
+import (
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/lainio/err2"
+	"github.com/lainio/err2/try"
+)
+
+// Message types of the Aries RFC 0234 signature decorator.
+const (
+	SigDecoratorType       = "https://didcomm.org/signature/1.0/ed25519Sha512_single"
+	SigDecoratorTypeLegacy = "did:sov:BzCbsNYhMrjHiqZDTUASHg;spec/signature/1.0/ed25519Sha512_single"
+)
+
+// sigTimestampSize is the size of the big endian timestamp in the sig_data.
+const sigTimestampSize = 8
+
+// ErrSigTimestamp is returned when the timestamp of the signature decorator
+// isn't acceptable.
+var ErrSigTimestamp = errors.New("signature decorator timestamp")
+
+// SigTimestampLeeway is the allowed clock skew of the signature timestamps.
+var SigTimestampLeeway = time.Minute
+
+// sigNow is replaced in the tests.
+var sigNow = time.Now
+
+// SigDecorator is the ~sig decorator of Aries RFC 0234, e.g. connection~sig
+// of the connection response. SigData is the timestamp and the payload, and
+// it's signed by the Signer verkey.
+type SigDecorator struct {
+	Type      string `json:"@type"`
+	Signature string `json:"signature"`
+	SigData   string `json:"sig_data"`
+	Signer    string `json:"signer"`
+}
+
+// NewSigDecorator signs the payload with the wallet's verkey and the current
+// time.
+func NewSigDecorator(wallet int, verKey string, payload []byte) (d *SigDecorator, err error) {
+	defer err2.Handle(&err, "sign decorator")
+
+	sigData := make([]byte, sigTimestampSize, sigTimestampSize+len(payload))
+	binary.BigEndian.PutUint64(sigData, uint64(sigNow().Unix()))
+	sigData = append(sigData, payload...)
+
+	r := <-SignMsg(wallet, verKey, sigData)
+	try.To(r.Err())
+	return &SigDecorator{
+		Type:      SigDecoratorType,
+		Signature: base64.URLEncoding.EncodeToString(r.Bytes()),
+		SigData:   base64.URLEncoding.EncodeToString(sigData),
+		Signer:    verKey,
+	}, nil
+}
+
+// NewSigDecoratorJSON is like NewSigDecorator but the payload is the JSON of
+// the value, e.g. the connection field of the connection response.
+func NewSigDecoratorJSON(wallet int, verKey string, v any) (d *SigDecorator, err error) {
+	defer err2.Handle(&err, "sign decorator")
+
+	return NewSigDecorator(wallet, verKey, try.To1(json.Marshal(v)))
+}
+
+// Verify verifies the signature with VerifySignature and returns the payload
+// and the timestamp. The timestamp cannot be in the future, and if maxAge
+// isn't zero, it cannot be older than it.
+func (d *SigDecorator) Verify(maxAge time.Duration) (payload []byte, timestamp time.Time, err error) {
+	defer err2.Handle(&err, "verify decorator")
+
+	if d.Type != SigDecoratorType && d.Type != SigDecoratorTypeLegacy {
+		return nil, timestamp, fmt.Errorf("unsupported type %q", d.Type)
+	}
+	sigData := try.To1(decodeSig(d.SigData))
+	if len(sigData) < sigTimestampSize {
+		return nil, timestamp, errors.New("sig_data is too short")
+	}
+	sig := try.To1(decodeSig(d.Signature))
+
+	r := <-VerifySignature(d.Signer, sigData, sig)
+	try.To(r.Err())
+	if !r.Yes() {
+		return nil, timestamp, fmt.Errorf("invalid signature of %s", d.Signer)
+	}
+
+	timestamp = time.Unix(int64(binary.BigEndian.Uint64(sigData)), 0)
+	now := sigNow()
+	if timestamp.After(now.Add(SigTimestampLeeway)) {
+		return nil, timestamp, fmt.Errorf("%w %v is in the future", ErrSigTimestamp, timestamp)
+	}
+	if maxAge != 0 && timestamp.Before(now.Add(-maxAge-SigTimestampLeeway)) {
+		return nil, timestamp, fmt.Errorf("%w %v is older than %v", ErrSigTimestamp, timestamp, maxAge)
+	}
+	return sigData[sigTimestampSize:], timestamp, nil
+}
+
+// VerifyJSON is like Verify but the payload JSON is unmarshaled to the value.
+func (d *SigDecorator) VerifyJSON(maxAge time.Duration, v any) (timestamp time.Time, err error) {
+	payload, timestamp, err := d.Verify(maxAge)
+	if err != nil {
+		return timestamp, err
+	}
+	if err = json.Unmarshal(payload, v); err != nil {
+		return timestamp, fmt.Errorf("verify decorator: %w", err)
+	}
+	return timestamp, nil
+}
+
+// decodeSig accepts base64url with and without padding, because agents
+// differ.
+func decodeSig(s string) ([]byte, error) {
+	return base64.RawURLEncoding.DecodeString(strings.TrimRight(s, "="))
+}
@@ -0,0 +1,74 @@
+package crypto
+
+import (
+	"encoding/base64"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/lainio/err2/assert"
+
+	"github.com/findy-network/findy-wrapper-go/did"
+	"github.com/findy-network/findy-wrapper-go/helpers"
+)
+
+func TestSigDecorator(t *testing.T) {
+	defer assert.PushTester(t)()
+
+	w, name := helpers.CreateAndOpenTestWallet(t)
+	r := <-did.Create(w)
+	assert.NoError(r.Err())
+	verKey := r.Str2()
+
+	type connection struct {
+		DID    string `json:"DID"`
+		DIDDoc any    `json:"DIDDoc"`
+	}
+	conn := connection{DID: r.Str1(), DIDDoc: map[string]string{"id": "did:sov:" + r.Str1()}}
+
+	defer func() { sigNow = time.Now }()
+	signed := time.Unix(1700000000, 0)
+	sigNow = func() time.Time { return signed }
+	d, err := NewSigDecoratorJSON(w, verKey, conn)
+	assert.NoError(err)
+	assert.Equal(d.Signer, verKey)
+
+	var got connection
+	timestamp, err := d.VerifyJSON(time.Hour, &got)
+	assert.NoError(err)
+	assert.Equal(timestamp.Unix(), signed.Unix())
+	assert.Equal(got.DID, conn.DID)
+
+	// unpadded base64 of other agents
+	d2 := *d
+	d2.SigData = base64.RawURLEncoding.EncodeToString(mustDecodeSig(d.SigData))
+	d2.Type = SigDecoratorTypeLegacy
+	_, _, err = d2.Verify(0)
+	assert.NoError(err)
+
+	sigNow = func() time.Time { return signed.Add(2 * time.Hour) }
+	_, _, err = d.Verify(time.Hour)
+	assert.That(errors.Is(err, ErrSigTimestamp))
+	_, _, err = d.Verify(0)
+	assert.NoError(err)
+	sigNow = func() time.Time { return signed.Add(-time.Hour) }
+	_, _, err = d.Verify(0)
+	assert.That(errors.Is(err, ErrSigTimestamp))
+	sigNow = func() time.Time { return signed }
+
+	sigData := mustDecodeSig(d.SigData)
+	sigData[len(sigData)-2] ^= 1
+	d2.SigData = base64.URLEncoding.EncodeToString(sigData)
+	_, _, err = d2.Verify(0)
+	assert.Error(err)
+
+	helpers.CloseAndDeleteTestWallet(w, name, t)
+}
+
+func mustDecodeSig(s string) []byte {
+	b, err := decodeSig(s)
+	if err != nil {
+		panic(err)
+	}
+	return b
+}
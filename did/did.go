@@ -36,6 +36,21 @@ func Key(pool, wallet int, didName string) ctx.Channel {
 	return c2go.KeyForDid(pool, wallet, didName)
 }
 
+// ReplaceKeysStart generates a new temporary key for the DID, which is
+// returned in Str1. The key isn't used before ReplaceKeysApply. The seed and
+// the crypto type of the new key are given in the identity, and the other
+// fields are ignored. See package rotation for the whole key rotation flow.
+func ReplaceKeysStart(wallet int, did string, identity Did) ctx.Channel {
+	return c2go.FindyReplaceKeysStart(wallet, did, dto.ToJSON(identity))
+}
+
+// ReplaceKeysApply replaces the DID's key with the temporary key of
+// ReplaceKeysStart. Write the new key to the ledger before applying it,
+// because the NYM transaction is signed with the current key.
+func ReplaceKeysApply(wallet int, did string) ctx.Channel {
+	return c2go.FindyReplaceKeysApply(wallet, did)
+}
+
 // StoreTheir saves their DID to wallet. Use did.Did configuration struct to
 // build the JSON string.
 func StoreTheir(wallet int, idJSON string) ctx.Channel {
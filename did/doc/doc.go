@@ -0,0 +1,339 @@
+/*
+Package doc is the DID Document of the DID Core spec. The did:key and did:peer
+DIDs are resolved to it, and VerKeys maps it back to the base58 verkeys which
+crypto.Pack and the envelope package use.
+
+	d, err := peer.Resolve(theirDID)
+	verKeys, err := d.VerKeys()
+*/
+package doc
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/findy-network/findy-wrapper-go/internal/base58"
+	"github.com/findy-network/findy-wrapper-go/internal/multibase"
+)
+
+// Context is the JSON-LD context of the DID Documents.
+const Context = "https://www.w3.org/ns/did/v1"
+
+// Verification method types.
+const (
+	TypeEd25519VerificationKey2018 = "Ed25519VerificationKey2018"
+	TypeEd25519VerificationKey2020 = "Ed25519VerificationKey2020"
+	TypeX25519KeyAgreementKey2019  = "X25519KeyAgreementKey2019"
+	TypeX25519KeyAgreementKey2020  = "X25519KeyAgreementKey2020"
+	TypeMultikey                   = "Multikey"
+)
+
+// Service types.
+const (
+	ServiceDIDCommV1 = "did-communication"
+	ServiceDIDCommV2 = "DIDCommMessaging"
+)
+
+// ErrNotFound is returned when the document doesn't have the method or key.
+var ErrNotFound = errors.New("doc: not found")
+
+// Doc is the DID Document.
+type Doc struct {
+	Context            []string             `json:"@context"`
+	ID                 string               `json:"id"`
+	AlsoKnownAs        []string             `json:"alsoKnownAs,omitempty"`
+	Controller         []string             `json:"controller,omitempty"`
+	VerificationMethod []VerificationMethod `json:"verificationMethod,omitempty"`
+
+	Authentication       []Relationship `json:"authentication,omitempty"`
+	AssertionMethod      []Relationship `json:"assertionMethod,omitempty"`
+	KeyAgreement         []Relationship `json:"keyAgreement,omitempty"`
+	CapabilityInvocation []Relationship `json:"capabilityInvocation,omitempty"`
+	CapabilityDelegation []Relationship `json:"capabilityDelegation,omitempty"`
+
+	Service []Service `json:"service,omitempty"`
+}
+
+// Clone returns a deep copy of the document.
+func (d *Doc) Clone() *Doc {
+	if d == nil {
+		return nil
+	}
+	c := *d
+	c.Context = slices.Clone(d.Context)
+	c.AlsoKnownAs = slices.Clone(d.AlsoKnownAs)
+	c.Controller = slices.Clone(d.Controller)
+	c.VerificationMethod = slices.Clone(d.VerificationMethod)
+	c.Authentication = cloneRelationships(d.Authentication)
+	c.AssertionMethod = cloneRelationships(d.AssertionMethod)
+	c.KeyAgreement = cloneRelationships(d.KeyAgreement)
+	c.CapabilityInvocation = cloneRelationships(d.CapabilityInvocation)
+	c.CapabilityDelegation = cloneRelationships(d.CapabilityDelegation)
+	if d.Service != nil {
+		c.Service = make([]Service, len(d.Service))
+		for i, s := range d.Service {
+			c.Service[i] = s.clone()
+		}
+	}
+	return &c
+}
+
+func cloneRelationships(rels []Relationship) []Relationship {
+	if rels == nil {
+		return nil
+	}
+	c := make([]Relationship, len(rels))
+	for i, r := range rels {
+		c[i] = r
+		if r.Method != nil {
+			m := *r.Method
+			c[i].Method = &m
+		}
+	}
+	return c
+}
+
+func (s Service) clone() Service {
+	s.RecipientKeys = slices.Clone(s.RecipientKeys)
+	s.RoutingKeys = slices.Clone(s.RoutingKeys)
+	s.Accept = slices.Clone(s.Accept)
+	return s
+}
+
+// VerificationMethod is the public key of the document. The key is either in
+// PublicKeyBase58 or PublicKeyMultibase.
+type VerificationMethod struct {
+	ID                 string `json:"id"`
+	Type               string `json:"type"`
+	Controller         string `json:"controller"`
+	PublicKeyBase58    string `json:"publicKeyBase58,omitempty"`
+	PublicKeyMultibase string `json:"publicKeyMultibase,omitempty"`
+}
+
+// Relationship is a verification relationship, which either refers to the
+// verification method by its ID or embeds it.
+type Relationship struct {
+	Ref    string
+	Method *VerificationMethod
+}
+
+// Service is the service endpoint. RecipientKeys and RoutingKeys are the
+// verkeys or the did:key references of DIDComm v1.
+//
+// The DIDComm v2 endpoint can be an object which has the uri, accept and
+// routingKeys. Its fields are read to ServiceEndpoint, Accept and
+// RoutingKeys, and EndpointObject tells to write them back as the object.
+type Service struct {
+	ID              string   `json:"id"`
+	Type            string   `json:"type"`
+	ServiceEndpoint string   `json:"serviceEndpoint"`
+	RecipientKeys   []string `json:"recipientKeys,omitempty"`
+	RoutingKeys     []string `json:"routingKeys,omitempty"`
+	Accept          []string `json:"accept,omitempty"`
+	Priority        int      `json:"priority,omitempty"`
+	EndpointObject  bool     `json:"-"`
+}
+
+// serviceFields is Service without the JSON methods.
+type serviceFields Service
+
+// endpointObject is the object form of the service endpoint.
+type endpointObject struct {
+	URI         string   `json:"uri"`
+	Accept      []string `json:"accept,omitempty"`
+	RoutingKeys []string `json:"routingKeys,omitempty"`
+}
+
+// MarshalJSON marshals the endpoint as a string, or as an object if
+// EndpointObject is set.
+func (s Service) MarshalJSON() ([]byte, error) {
+	if !s.EndpointObject {
+		return json.Marshal(serviceFields(s))
+	}
+	endpoint := endpointObject{URI: s.ServiceEndpoint, Accept: s.Accept, RoutingKeys: s.RoutingKeys}
+	s.Accept, s.RoutingKeys = nil, nil
+	return json.Marshal(struct {
+		serviceFields
+		ServiceEndpoint endpointObject `json:"serviceEndpoint"`
+	}{serviceFields(s), endpoint})
+}
+
+// UnmarshalJSON unmarshals the service whose endpoint is a string or an
+// object.
+func (s *Service) UnmarshalJSON(data []byte) error {
+	var fields struct {
+		serviceFields
+		ServiceEndpoint json.RawMessage `json:"serviceEndpoint"`
+	}
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return err
+	}
+	*s = Service(fields.serviceFields)
+	endpoint := fields.ServiceEndpoint
+	if len(endpoint) == 0 || endpoint[0] != '{' {
+		s.EndpointObject = false
+		if len(endpoint) == 0 || string(endpoint) == "null" {
+			return nil
+		}
+		return json.Unmarshal(endpoint, &s.ServiceEndpoint)
+	}
+	var obj endpointObject
+	if err := json.Unmarshal(endpoint, &obj); err != nil {
+		return err
+	}
+	s.ServiceEndpoint = obj.URI
+	s.Accept = append(s.Accept, obj.Accept...)
+	s.RoutingKeys = append(s.RoutingKeys, obj.RoutingKeys...)
+	s.EndpointObject = true
+	return nil
+}
+
+// MarshalJSON marshals the reference as a string and the embedded method as
+// an object.
+func (r Relationship) MarshalJSON() ([]byte, error) {
+	if r.Method != nil {
+		return json.Marshal(r.Method)
+	}
+	return json.Marshal(r.Ref)
+}
+
+// UnmarshalJSON is the opposite of MarshalJSON.
+func (r *Relationship) UnmarshalJSON(data []byte) error {
+	if len(data) > 0 && data[0] == '"' {
+		r.Method = nil
+		return json.Unmarshal(data, &r.Ref)
+	}
+	r.Ref = ""
+	r.Method = new(VerificationMethod)
+	return json.Unmarshal(data, r.Method)
+}
+
+// ID returns the ID of the verification method of the relationship.
+func (r Relationship) ID() string {
+	if r.Method != nil {
+		return r.Method.ID
+	}
+	return r.Ref
+}
+
+// RawKey returns the raw public key of the method.
+func (m *VerificationMethod) RawKey() ([]byte, error) {
+	switch {
+	case m.PublicKeyBase58 != "":
+		return base58.Decode(m.PublicKeyBase58)
+	case m.PublicKeyMultibase != "":
+		_, key, err := multibase.Decode(m.PublicKeyMultibase)
+		return key, err
+	}
+	return nil, fmt.Errorf("doc: method %s has no supported key", m.ID)
+}
+
+// IsEd25519 tells if the method is an Ed25519 key.
+func (m *VerificationMethod) IsEd25519() bool {
+	switch m.Type {
+	case TypeEd25519VerificationKey2018, TypeEd25519VerificationKey2020:
+		return true
+	case TypeMultikey:
+		codec, _, err := multibase.Decode(m.PublicKeyMultibase)
+		return err == nil && codec == multibase.Ed25519Pub
+	}
+	return false
+}
+
+// VerKey returns the base58 verkey of the Ed25519 method.
+func (m *VerificationMethod) VerKey() (string, error) {
+	if !m.IsEd25519() {
+		return "", fmt.Errorf("doc: method %s isn't Ed25519 but %s", m.ID, m.Type)
+	}
+	key, err := m.RawKey()
+	if err != nil {
+		return "", err
+	}
+	return base58.Encode(key), nil
+}
+
+// Method returns the verification method by its ID. Relative IDs, i.e.
+// #key-1, are resolved against the document ID.
+func (d *Doc) Method(id string) (*VerificationMethod, error) {
+	id = d.absolute(id)
+	for i := range d.VerificationMethod {
+		if d.absolute(d.VerificationMethod[i].ID) == id {
+			return &d.VerificationMethod[i], nil
+		}
+	}
+	for _, rels := range [][]Relationship{d.Authentication, d.AssertionMethod,
+		d.KeyAgreement, d.CapabilityInvocation, d.CapabilityDelegation} {
+		for _, r := range rels {
+			if r.Method != nil && d.absolute(r.Method.ID) == id {
+				return r.Method, nil
+			}
+		}
+	}
+	return nil, fmt.Errorf("%w: method %s", ErrNotFound, id)
+}
+
+// Methods resolves the methods of the relationships.
+func (d *Doc) Methods(rels []Relationship) ([]*VerificationMethod, error) {
+	methods := make([]*VerificationMethod, 0, len(rels))
+	for _, r := range rels {
+		if r.Method != nil {
+			methods = append(methods, r.Method)
+			continue
+		}
+		m, err := d.Method(r.Ref)
+		if err != nil {
+			return nil, err
+		}
+		methods = append(methods, m)
+	}
+	return methods, nil
+}
+
+// VerKeys returns the base58 verkeys of the Ed25519 authentication methods.
+// If there aren't any, the verkeys of all of the Ed25519 methods are
+// returned.
+func (d *Doc) VerKeys() (verKeys []string, err error) {
+	methods, err := d.Methods(d.Authentication)
+	if err != nil {
+		return nil, err
+	}
+	if len(methods) == 0 {
+		for i := range d.VerificationMethod {
+			methods = append(methods, &d.VerificationMethod[i])
+		}
+	}
+	for _, m := range methods {
+		if !m.IsEd25519() {
+			continue
+		}
+		verKey, err := m.VerKey()
+		if err != nil {
+			return nil, err
+		}
+		verKeys = append(verKeys, verKey)
+	}
+	if len(verKeys) == 0 {
+		return nil, fmt.Errorf("%w: Ed25519 keys of %s", ErrNotFound, d.ID)
+	}
+	return verKeys, nil
+}
+
+// ServiceByType returns the first service of the type.
+func (d *Doc) ServiceByType(typ string) (*Service, error) {
+	for i := range d.Service {
+		if d.Service[i].Type == typ {
+			return &d.Service[i], nil
+		}
+	}
+	return nil, fmt.Errorf("%w: %s service of %s", ErrNotFound, typ, d.ID)
+}
+
+func (d *Doc) absolute(id string) string {
+	if strings.HasPrefix(id, "#") {
+		return d.ID + id
+	}
+	return id
+}
@@ -0,0 +1,98 @@
+package doc
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/lainio/err2/assert"
+)
+
+const testDoc = `{
+  "@context": ["https://www.w3.org/ns/did/v1"],
+  "id": "did:example:123",
+  "verificationMethod": [{
+    "id": "did:example:123#key-1",
+    "type": "Ed25519VerificationKey2018",
+    "controller": "did:example:123",
+    "publicKeyBase58": "8HH5gYEeNc3z7PYXmd54d4x6qAfCNrqQqEB3nS7Zfu7K"
+  }],
+  "authentication": [
+    "#key-1",
+    {
+      "id": "did:example:123#key-2",
+      "type": "Multikey",
+      "controller": "did:example:123",
+      "publicKeyMultibase": "z6MkiTBz1ymuepAQ4HEHYSF1H8quG5GLVVQR3djdX3mDooWp"
+    }
+  ],
+  "keyAgreement": [{
+    "id": "#key-3",
+    "type": "X25519KeyAgreementKey2020",
+    "controller": "did:example:123",
+    "publicKeyMultibase": "z6LShs9GGnqk85isEBzzshkuVWrVKsRp24GnDuHk8QWkARMW"
+  }],
+  "service": [{
+    "id": "#didcomm",
+    "type": "did-communication",
+    "serviceEndpoint": "http://localhost:8080",
+    "recipientKeys": ["8HH5gYEeNc3z7PYXmd54d4x6qAfCNrqQqEB3nS7Zfu7K"]
+  }]
+}`
+
+func TestDoc(t *testing.T) {
+	defer assert.PushTester(t)()
+
+	var d Doc
+	assert.NoError(json.Unmarshal([]byte(testDoc), &d))
+	assert.Equal(d.Authentication[0].Ref, "#key-1")
+	assert.Equal(d.Authentication[1].ID(), "did:example:123#key-2")
+
+	verKeys, err := d.VerKeys()
+	assert.NoError(err)
+	assert.DeepEqual(verKeys, []string{
+		"8HH5gYEeNc3z7PYXmd54d4x6qAfCNrqQqEB3nS7Zfu7K",
+		"4zvwRjXUKGfvwnParsHAS3HuSVzV5cA4McphgmoCtajS",
+	})
+
+	m, err := d.Method("did:example:123#key-3")
+	assert.NoError(err)
+	assert.ThatNot(m.IsEd25519())
+	_, err = m.VerKey()
+	assert.Error(err)
+	_, err = d.Method("#key-4")
+	assert.That(errors.Is(err, ErrNotFound))
+
+	s, err := d.ServiceByType(ServiceDIDCommV1)
+	assert.NoError(err)
+	assert.Equal(s.ServiceEndpoint, "http://localhost:8080")
+	_, err = d.ServiceByType(ServiceDIDCommV2)
+	assert.That(errors.Is(err, ErrNotFound))
+
+	data, err := json.Marshal(d)
+	assert.NoError(err)
+	var d2 Doc
+	assert.NoError(json.Unmarshal(data, &d2))
+	assert.DeepEqual(d2, d)
+}
+
+func TestDoc_Clone(t *testing.T) {
+	defer assert.PushTester(t)()
+
+	var d Doc
+	assert.NoError(json.Unmarshal([]byte(testDoc), &d))
+	c := d.Clone()
+	assert.DeepEqual(*c, d)
+
+	c.Service[0].RecipientKeys[0] = "changed"
+	c.Service = append(c.Service, Service{ID: "#other"})
+	c.Authentication[1].Method.ID = "changed"
+	c.VerificationMethod[0].ID = "changed"
+	assert.Equal(d.Service[0].RecipientKeys[0], "8HH5gYEeNc3z7PYXmd54d4x6qAfCNrqQqEB3nS7Zfu7K")
+	assert.SLen(d.Service, 1)
+	assert.Equal(d.Authentication[1].ID(), "did:example:123#key-2")
+	assert.Equal(d.VerificationMethod[0].ID, "did:example:123#key-1")
+
+	var nilDoc *Doc
+	assert.That(nilDoc.Clone() == nil)
+}
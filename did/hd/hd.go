@@ -0,0 +1,136 @@
+/*
+Package hd derives the DIDs deterministically from one master secret with the
+SLIP-0010 Ed25519 derivation. The child seeds are derived by a path, e.g. one
+child per connection, and the DIDs are created from them with
+did.CreateAndStore. All of the DIDs can be rebuilt to a fresh wallet from the
+master secret and the paths:
+
+	master, err := hd.NewMaster(backupSecret)
+	child, err := master.Create(wallet, hd.ConnectionPath(7))
+	...
+	children, err := master.Recover(newWallet, paths, hd.LedgerCheck(pool, submitter))
+
+Ed25519 supports only hardened derivation, so every index of the path is
+hardened whether it's marked with ' or not.
+
+Note that a DID whose key is rotated with the rotation package no longer has
+the derived key, and its recovery check fails.
+*/
+package hd
+
+import (
+	"crypto/ed25519"
+	"crypto/hmac"
+	"crypto/sha512"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/findy-network/findy-wrapper-go/did"
+	"github.com/findy-network/findy-wrapper-go/internal/base58"
+	"github.com/lainio/err2"
+	"github.com/lainio/err2/try"
+)
+
+// hardened is the offset of the hardened indexes.
+const hardened uint32 = 1 << 31
+
+// Limits of the master secret size in bytes.
+const (
+	MinSecretSize = 16
+	MaxSecretSize = 64
+)
+
+// ErrInvalidPath is returned when the path cannot be parsed.
+var ErrInvalidPath = errors.New("hd: invalid path")
+
+// Master is the master key derived from the master secret.
+type Master struct {
+	key       []byte
+	chainCode []byte
+}
+
+// Child is the derived DID. Seed is the hex seed given to
+// did.CreateAndStore, and DID and VerKey are computed from it.
+type Child struct {
+	Path   string
+	Seed   string
+	DID    string
+	VerKey string
+}
+
+// NewMaster derives the master key from the secret, which is 16 to 64 bytes.
+func NewMaster(secret []byte) (*Master, error) {
+	if len(secret) < MinSecretSize || len(secret) > MaxSecretSize {
+		return nil, fmt.Errorf("hd: master secret must be %d to %d bytes", MinSecretSize, MaxSecretSize)
+	}
+	key, chainCode := hmacSplit([]byte("ed25519 seed"), secret)
+	return &Master{key: key, chainCode: chainCode}, nil
+}
+
+// ConnectionPath returns the path of the connection's DID: m/1'/<index>'.
+func ConnectionPath(index uint32) string {
+	return fmt.Sprintf("m/1'/%d'", index)
+}
+
+// Derive derives the child of the path, e.g. m/1'/7'.
+func (m *Master) Derive(path string) (c Child, err error) {
+	defer err2.Handle(&err)
+
+	indexes := try.To1(parsePath(path))
+	key, chainCode := m.key, m.chainCode
+	for _, i := range indexes {
+		data := make([]byte, 0, 1+len(key)+4)
+		data = append(data, 0)
+		data = append(data, key...)
+		data = binary.BigEndian.AppendUint32(data, i|hardened)
+		key, chainCode = hmacSplit(chainCode, data)
+	}
+
+	pub := ed25519.NewKeyFromSeed(key).Public().(ed25519.PublicKey)
+	return Child{
+		Path:   path,
+		Seed:   hex.EncodeToString(key),
+		DID:    base58.Encode(pub[:16]),
+		VerKey: base58.Encode(pub),
+	}, nil
+}
+
+// Create derives the child of the path and creates its DID to the wallet.
+func (m *Master) Create(wallet int, path string) (c Child, err error) {
+	defer err2.Handle(&err, "hd: create %s", path)
+
+	c = try.To1(m.Derive(path))
+	r := <-did.CreateAndStore(wallet, did.Did{Seed: c.Seed})
+	try.To(r.Err())
+	if r.Str1() != c.DID || r.Str2() != c.VerKey {
+		return c, fmt.Errorf("wallet created %s with %s, want %s", r.Str1(), r.Str2(), c.DID)
+	}
+	return c, nil
+}
+
+func hmacSplit(key, data []byte) (left, right []byte) {
+	mac := hmac.New(sha512.New, key)
+	mac.Write(data)
+	sum := mac.Sum(nil)
+	return sum[:32], sum[32:]
+}
+
+func parsePath(path string) ([]uint32, error) {
+	parts := strings.Split(path, "/")
+	if parts[0] != "m" {
+		return nil, fmt.Errorf("%w %q: must start with m", ErrInvalidPath, path)
+	}
+	indexes := make([]uint32, 0, len(parts)-1)
+	for _, part := range parts[1:] {
+		i, err := strconv.ParseUint(strings.TrimSuffix(part, "'"), 10, 31)
+		if err != nil {
+			return nil, fmt.Errorf("%w %q: %v", ErrInvalidPath, path, err)
+		}
+		indexes = append(indexes, uint32(i))
+	}
+	return indexes, nil
+}
@@ -0,0 +1,101 @@
+package hd
+
+import (
+	"encoding/hex"
+	"errors"
+	"testing"
+
+	"github.com/lainio/err2/assert"
+
+	"github.com/findy-network/findy-wrapper-go"
+	"github.com/findy-network/findy-wrapper-go/did"
+	"github.com/findy-network/findy-wrapper-go/helpers"
+	"github.com/findy-network/findy-wrapper-go/internal/base58"
+	"github.com/findy-network/findy-wrapper-go/ledger"
+	"github.com/findy-network/findy-wrapper-go/pairwise"
+)
+
+// the test vector 1 of SLIP-0010 for ed25519
+const testSecret = "000102030405060708090a0b0c0d0e0f"
+
+func TestDerive(t *testing.T) {
+	defer assert.PushTester(t)()
+
+	secret, _ := hex.DecodeString(testSecret)
+	m, err := NewMaster(secret)
+	assert.NoError(err)
+	assert.Equal(hex.EncodeToString(m.key), "2b4be7f19ee27bbf30c667b642d5f4aa69fd169872f8fc3059c08ebae2eb19e7")
+	assert.Equal(hex.EncodeToString(m.chainCode), "90046a93de5380a72b5e45010748567d5ea02bbf6522f979e05c0d8d8ca9fffb")
+
+	for path, want := range map[string]string{
+		"m":       "2b4be7f19ee27bbf30c667b642d5f4aa69fd169872f8fc3059c08ebae2eb19e7",
+		"m/0'":    "68e0fe46dfb67e368c75379acec591dad19df3cde26e63b93a8e704f1dade7a3",
+		"m/0'/1'": "b1d0bad404bf35da785a64ca1ac54b2617211d2777696fbffaf208f746ae84f2",
+		"m/0/1":   "b1d0bad404bf35da785a64ca1ac54b2617211d2777696fbffaf208f746ae84f2",
+	} {
+		c, err := m.Derive(path)
+		assert.NoError(err, path)
+		assert.Equal(c.Seed, want, path)
+		pub, err := base58.Decode(c.VerKey)
+		assert.NoError(err)
+		assert.Equal(c.DID, base58.Encode(pub[:16]))
+	}
+
+	c1, err := m.Derive(ConnectionPath(1))
+	assert.NoError(err)
+	c2, err := m.Derive(ConnectionPath(2))
+	assert.NoError(err)
+	assert.NotEqual(c1.DID, c2.DID)
+	assert.Equal(c1.Path, "m/1'/1'")
+
+	for _, path := range []string{"", "0'/1'", "m/x", "m/2147483648'", "m//1"} {
+		_, err = m.Derive(path)
+		assert.That(errors.Is(err, ErrInvalidPath), path)
+	}
+	_, err = NewMaster(secret[:8])
+	assert.Error(err)
+}
+
+func TestRecover(t *testing.T) {
+	defer assert.PushTester(t)()
+
+	pool := helpers.OpenTestPool(t)
+	w, name := helpers.CreateAndOpenTestWallet(t)
+
+	r := <-did.CreateAndStore(w, did.Did{Seed: "000000000000000000000000Steward1"})
+	assert.NoError(r.Err())
+	stewardDID := r.Str1()
+	assert.NoError(ledger.WriteDID(pool, w, stewardDID, stewardDID, r.Str2(),
+		findy.NullString, findy.NullString))
+
+	secret, _ := hex.DecodeString(testSecret)
+	m, err := NewMaster(secret)
+	assert.NoError(err)
+	paths := []string{ConnectionPath(0), ConnectionPath(1)}
+	var pws []pairwise.Data
+	for _, path := range paths {
+		c, err := m.Create(w, path)
+		assert.NoError(err)
+		assert.NoError(ledger.WriteDID(pool, w, stewardDID, c.DID, c.VerKey,
+			findy.NullString, findy.NullString))
+		pws = append(pws, pairwise.Data{MyDid: c.DID, TheirDid: stewardDID})
+	}
+
+	w2, name2 := helpers.CreateAndOpenTestWallet(t)
+	children, err := m.Recover(w2, paths, LedgerCheck(pool, stewardDID))
+	assert.NoError(err)
+	assert.SLen(children, 2)
+	r = <-did.LocalKey(w2, children[1].DID)
+	assert.NoError(r.Err())
+	assert.Equal(r.Str1(), children[1].VerKey)
+
+	w3, name3 := helpers.CreateAndOpenTestWallet(t)
+	children, err = m.Recover(w3, append(paths, ConnectionPath(2)), PairwiseCheck(pws))
+	assert.That(errors.Is(err, ErrMismatch))
+	assert.SLen(children, 2)
+
+	helpers.CloseAndDeleteTestWallet(w, name, t)
+	helpers.CloseAndDeleteTestWallet(w2, name2, t)
+	helpers.CloseAndDeleteTestWallet(w3, name3, t)
+	helpers.CloseTestPool(pool, t)
+}
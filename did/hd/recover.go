@@ -0,0 +1,67 @@
+package hd
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/findy-network/findy-wrapper-go/ledger"
+	"github.com/findy-network/findy-wrapper-go/pairwise"
+)
+
+// ErrMismatch is returned when the recovered DID doesn't match the check.
+var ErrMismatch = errors.New("hd: recovered DID doesn't match")
+
+// Check checks the recovered DID and its verkey against the known data.
+type Check func(did, verKey string) error
+
+// LedgerCheck compares the verkey to the one of ledger.ReadDID.
+func LedgerCheck(pool int, submitter string) Check {
+	return func(did, verKey string) error {
+		ledgerKey, err := ledger.ReadDID(pool, submitter, did)
+		if err != nil {
+			return err
+		}
+		if ledgerKey != verKey {
+			return fmt.Errorf("%w: ledger has %s for %s", ErrMismatch, ledgerKey, did)
+		}
+		return nil
+	}
+}
+
+// PairwiseCheck checks that the DID is MyDid of the stored pairwise records,
+// e.g. of the backup of pairwise.List. The verkey is implicitly checked,
+// because the DID is its first 16 bytes.
+func PairwiseCheck(pws []pairwise.Data) Check {
+	myDIDs := make(map[string]bool, len(pws))
+	for _, pw := range pws {
+		myDIDs[pw.MyDid] = true
+	}
+	return func(did, _ string) error {
+		if !myDIDs[did] {
+			return fmt.Errorf("%w: no pairwise for %s", ErrMismatch, did)
+		}
+		return nil
+	}
+}
+
+// Recover creates the DIDs of the paths to the wallet, which is usually a
+// fresh one, and checks them if the check isn't nil. All of the paths are
+// processed, and the errors are joined. The children are returned in the
+// order of the paths, and the failed ones are left out.
+func (m *Master) Recover(wallet int, paths []string, check Check) (children []Child, err error) {
+	var errs []error
+	for _, path := range paths {
+		c, err := m.Create(wallet, path)
+		if err == nil && check != nil {
+			if err = check(c.DID, c.VerKey); err != nil {
+				err = fmt.Errorf("hd: recover %s: %w", path, err)
+			}
+		}
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		children = append(children, c)
+	}
+	return children, errors.Join(errs...)
+}
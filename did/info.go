@@ -0,0 +1,201 @@
+package did
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+
+	"github.com/lainio/err2"
+	"github.com/lainio/err2/try"
+)
+
+// MetaVersion is the version of the metadata envelope of SetMetaValue.
+const MetaVersion = 1
+
+// ErrNoMeta is returned when the DID doesn't have metadata.
+var ErrNoMeta = errors.New("did: no metadata")
+
+// DidInfo is the wallet's DID with its verkey and metadata, i.e. the result
+// of List and KeyAndMeta. TempVerKey is set between ReplaceKeysStart and
+// ReplaceKeysApply.
+//
+//nolint:revive // the name follows the Did struct of this package
+type DidInfo struct {
+	Did        string `json:"did"`
+	VerKey     string `json:"verkey"`
+	TempVerKey string `json:"tempVerkey,omitempty"`
+	Metadata   string `json:"metadata,omitempty"`
+}
+
+// metaEnvelope is the stored form of the metadata values. Type is the Go
+// type name of the value given to SetMetaValue.
+type metaEnvelope struct {
+	Version int             `json:"version"`
+	Type    string          `json:"type,omitempty"`
+	Data    json.RawMessage `json:"data"`
+}
+
+// metaTypeName returns the name stored to the envelope's Type for the type.
+// The pointers are named by their element types.
+func metaTypeName(t reflect.Type) string {
+	for t != nil && t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t == nil {
+		return ""
+	}
+	if t.Name() != "" && t.PkgPath() != "" {
+		return t.PkgPath() + "." + t.Name()
+	}
+	return t.String()
+}
+
+// metaType returns the type name of the envelope of the metadata, or the
+// empty string if the metadata isn't typed.
+func (i DidInfo) metaType() string {
+	var e metaEnvelope
+	if json.Unmarshal([]byte(i.Metadata), &e) != nil {
+		return ""
+	}
+	return e.Type
+}
+
+// Info returns the typed DidInfo of the wallet's DID.
+func Info(wallet int, did string) (info DidInfo, err error) {
+	defer err2.Handle(&err, "did info %s", did)
+
+	r := <-KeyAndMeta(wallet, did)
+	try.To(r.Err())
+	try.To(json.Unmarshal([]byte(r.Str1()), &info))
+	return info, nil
+}
+
+// Infos returns the typed DidInfos of all of the wallet's DIDs.
+func Infos(wallet int) (infos []DidInfo, err error) {
+	defer err2.Handle(&err, "did infos")
+
+	r := <-List(wallet)
+	try.To(r.Err())
+	if r.Str1() != "" {
+		try.To(json.Unmarshal([]byte(r.Str1()), &infos))
+	}
+	return infos, nil
+}
+
+// Iterator iterates the wallet's DIDs like bufio.Scanner:
+//
+//	it := did.Iterate(wallet)
+//	for it.Next() {
+//		info := it.Info()
+//	}
+//	if err := it.Err(); err != nil {
+type Iterator struct {
+	infos []DidInfo
+	i     int
+	err   error
+}
+
+// Iterate lists the wallet's DIDs and returns the Iterator of them.
+func Iterate(wallet int) *Iterator {
+	infos, err := Infos(wallet)
+	return &Iterator{infos: infos, i: -1, err: err}
+}
+
+// Next moves to the next DID and tells if there was one.
+func (it *Iterator) Next() bool {
+	if it.err != nil || it.i+1 >= len(it.infos) {
+		return false
+	}
+	it.i++
+	return true
+}
+
+// Info returns the current DID.
+func (it *Iterator) Info() DidInfo {
+	return it.infos[it.i]
+}
+
+// Err returns the listing error.
+func (it *Iterator) Err() error {
+	return it.err
+}
+
+// Meta decodes the metadata to the value and returns its version. Metadata
+// which isn't stored with SetMetaValue is decoded as JSON, and its version is
+// zero.
+func (i DidInfo) Meta(v any) (version int, err error) {
+	if i.Metadata == "" {
+		return 0, fmt.Errorf("%w: %s", ErrNoMeta, i.Did)
+	}
+	var e metaEnvelope
+	if json.Unmarshal([]byte(i.Metadata), &e) == nil && e.Version > 0 && e.Data != nil {
+		if err = json.Unmarshal(e.Data, v); err != nil {
+			return 0, fmt.Errorf("did: metadata of %s: %w", i.Did, err)
+		}
+		return e.Version, nil
+	}
+	if err = json.Unmarshal([]byte(i.Metadata), v); err != nil {
+		return 0, fmt.Errorf("did: metadata of %s: %w", i.Did, err)
+	}
+	return 0, nil
+}
+
+// SetMetaValue stores the value as the JSON metadata of the DID in the
+// versioned envelope tagged with the type name of the value.
+func SetMetaValue(wallet int, did string, v any) (err error) {
+	defer err2.Handle(&err, "set metadata %s", did)
+
+	e := metaEnvelope{
+		Version: MetaVersion,
+		Type:    metaTypeName(reflect.TypeOf(v)),
+		Data:    try.To1(json.Marshal(v)),
+	}
+	r := <-SetMeta(wallet, did, string(try.To1(json.Marshal(e))))
+	return r.Err()
+}
+
+// MetaValue reads the metadata of the DID to the value, see DidInfo.Meta.
+func MetaValue(wallet int, did string, v any) (version int, err error) {
+	info, err := Info(wallet, did)
+	if err != nil {
+		return 0, err
+	}
+	return info.Meta(v)
+}
+
+// Find returns the wallet's DIDs which match the predicate.
+func Find(wallet int, match func(info DidInfo) bool) (infos []DidInfo, err error) {
+	it := Iterate(wallet)
+	for it.Next() {
+		if match(it.Info()) {
+			infos = append(infos, it.Info())
+		}
+	}
+	return infos, it.Err()
+}
+
+// FindByMeta returns the wallet's DIDs whose metadata is decoded to T and
+// matches the predicate. The DIDs without metadata, with the metadata
+// SetMetaValue stored from the other type, or with the metadata which doesn't
+// decode to T are skipped. The untyped metadata, e.g. set with SetMeta, is
+// matched if it decodes to T.
+func FindByMeta[T any](wallet int, match func(info DidInfo, meta T) bool) ([]DidInfo, error) {
+	return Find(wallet, func(info DidInfo) bool {
+		return matchMeta(info, match)
+	})
+}
+
+// matchMeta decodes the metadata of the DID to T and calls the predicate if
+// the metadata isn't typed as the other type.
+func matchMeta[T any](info DidInfo, match func(info DidInfo, meta T) bool) bool {
+	var meta T
+	typ := info.metaType()
+	if typ != "" && typ != metaTypeName(reflect.TypeOf(&meta)) {
+		return false
+	}
+	if _, err := info.Meta(&meta); err != nil {
+		return false
+	}
+	return match(info, meta)
+}
@@ -0,0 +1,109 @@
+package did
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/findy-network/findy-wrapper-go/helpers"
+	"github.com/lainio/err2/assert"
+)
+
+type testMeta struct {
+	Label string `json:"label"`
+	Role  string `json:"role"`
+}
+
+func TestDidInfoMeta(t *testing.T) {
+	defer assert.PushTester(t)()
+
+	var meta testMeta
+	version, err := DidInfo{Metadata: `{"version":1,"data":{"label":"alice","role":"holder"}}`}.Meta(&meta)
+	assert.NoError(err)
+	assert.Equal(version, 1)
+	assert.Equal(meta, testMeta{Label: "alice", Role: "holder"})
+
+	meta = testMeta{}
+	version, err = DidInfo{Metadata: `{"label":"bob"}`}.Meta(&meta)
+	assert.NoError(err)
+	assert.Equal(version, 0)
+	assert.Equal(meta.Label, "bob")
+
+	_, err = DidInfo{Metadata: "plain text"}.Meta(&meta)
+	assert.Error(err)
+	_, err = DidInfo{Did: "V4SGRU86Z58d6TV7PBUe6f"}.Meta(&meta)
+	assert.That(errors.Is(err, ErrNoMeta))
+}
+
+type otherMeta struct {
+	Label string `json:"label"`
+}
+
+func TestMatchMeta(t *testing.T) {
+	defer assert.PushTester(t)()
+
+	typed := func(typ string) DidInfo {
+		return DidInfo{Metadata: `{"version":1,"type":"` + typ + `","data":{"label":"alice"}}`}
+	}
+	all := func(DidInfo, testMeta) bool { return true }
+
+	assert.Equal(metaTypeName(reflect.TypeOf(testMeta{})), metaTypeName(reflect.TypeOf(&testMeta{})))
+	assert.That(matchMeta(typed(metaTypeName(reflect.TypeOf(testMeta{}))), all))
+	assert.ThatNot(matchMeta(typed(metaTypeName(reflect.TypeOf(otherMeta{}))), all))
+	assert.That(matchMeta(DidInfo{Metadata: `{"version":1,"data":{"label":"alice"}}`}, all))
+	assert.That(matchMeta(DidInfo{Metadata: `{"label":"alice"}`}, all))
+	assert.ThatNot(matchMeta(DidInfo{Metadata: "plain text"}, all))
+	assert.ThatNot(matchMeta(DidInfo{}, all))
+}
+
+func TestInfos(t *testing.T) {
+	defer assert.PushTester(t)()
+
+	w, name := helpers.CreateAndOpenTestWallet(t)
+
+	dids := make(map[string]string)
+	for _, label := range []string{"alice", "bob", "", "other"} {
+		r := <-Create(w)
+		assert.NoError(r.Err())
+		dids[r.Str1()] = r.Str2()
+		switch label {
+		case "":
+		case "other":
+			assert.NoError(SetMetaValue(w, r.Str1(), otherMeta{Label: "bob"}))
+		default:
+			assert.NoError(SetMetaValue(w, r.Str1(), testMeta{Label: label, Role: "holder"}))
+		}
+	}
+
+	count := 0
+	it := Iterate(w)
+	for it.Next() {
+		info := it.Info()
+		assert.Equal(info.VerKey, dids[info.Did])
+		count++
+	}
+	assert.NoError(it.Err())
+	assert.Equal(count, len(dids))
+
+	found, err := FindByMeta(w, func(_ DidInfo, meta testMeta) bool {
+		return meta.Label == "bob"
+	})
+	assert.NoError(err)
+	assert.SLen(found, 1)
+
+	var meta testMeta
+	version, err := MetaValue(w, found[0].Did, &meta)
+	assert.NoError(err)
+	assert.Equal(version, MetaVersion)
+	assert.Equal(meta.Label, "bob")
+
+	info, err := Info(w, found[0].Did)
+	assert.NoError(err)
+	assert.Equal(info.VerKey, dids[found[0].Did])
+
+	withMeta, err := Find(w, func(info DidInfo) bool { return info.Metadata != "" })
+	assert.NoError(err)
+	assert.SLen(withMeta, 3)
+
+	helpers.CloseAndDeleteTestWallet(w, name, t)
+}
@@ -0,0 +1,136 @@
+/*
+Package key implements the did:key method for the Ed25519 keys. The DID is
+the multibase fingerprint of the verkey, so it's created and resolved without
+a ledger:
+
+	didKey, err := key.Create(wallet, "")
+	...
+	verKey, err := key.VerKey(theirDIDKey)
+*/
+package key
+
+import (
+	"crypto/ed25519"
+	"fmt"
+	"strings"
+
+	"github.com/findy-network/findy-wrapper-go/crypto"
+	"github.com/findy-network/findy-wrapper-go/did/doc"
+	"github.com/findy-network/findy-wrapper-go/identifiers"
+	"github.com/findy-network/findy-wrapper-go/internal/base58"
+	"github.com/findy-network/findy-wrapper-go/internal/multibase"
+	"github.com/findy-network/findy-wrapper-go/internal/x25519"
+	"github.com/lainio/err2"
+	"github.com/lainio/err2/try"
+)
+
+// Prefix is the prefix of the did:key DIDs.
+const Prefix = "did:key:"
+
+// Create creates a new key to the wallet and returns its did:key. The seed is
+// optional like in crypto.CreateKey.
+func Create(wallet int, seed string) (didKey string, err error) {
+	defer err2.Handle(&err, "did:key create")
+
+	verKey := try.To1(crypto.NewKey(wallet, seed, nil))
+	return FromVerKey(string(verKey))
+}
+
+// FromVerKey returns the did:key of the base58 Ed25519 verkey.
+func FromVerKey(verKey string) (string, error) {
+	fingerprint, err := Fingerprint(verKey)
+	if err != nil {
+		return "", err
+	}
+	return Prefix + fingerprint, nil
+}
+
+// Fingerprint returns the multibase fingerprint of the base58 Ed25519
+// verkey, i.e. z6Mk...
+func Fingerprint(verKey string) (string, error) {
+	pub, err := base58.Decode(verKey)
+	if err != nil || len(pub) != ed25519.PublicKeySize {
+		return "", fmt.Errorf("%w: verkey %q", identifiers.ErrInvalid, verKey)
+	}
+	return multibase.Encode(multibase.Ed25519Pub, pub), nil
+}
+
+// VerKey returns the base58 verkey of the did:key. The DID URL fragment is
+// ignored, which makes it work with the did:key references of the DIDComm
+// services as well.
+func VerKey(didKey string) (string, error) {
+	pub, err := parse(didKey)
+	if err != nil {
+		return "", err
+	}
+	return base58.Encode(pub), nil
+}
+
+// Resolve resolves the did:key to its DID Document. The Ed25519 key is the
+// verification method of all of the relationships but the key agreement,
+// which is its X25519 conversion.
+func Resolve(didKey string) (*doc.Doc, error) {
+	if _, err := parse(didKey); err != nil {
+		return nil, err
+	}
+	didKey, _, _ = strings.Cut(didKey, "#")
+	return NewDoc(didKey, strings.TrimPrefix(didKey, Prefix))
+}
+
+// NewDoc returns the did:key style DID Document of the Ed25519 fingerprint
+// with the DID. did:peer numalgo 0 uses it as well.
+func NewDoc(id, fingerprint string) (d *doc.Doc, err error) {
+	defer err2.Handle(&err, "did:key %s", id)
+
+	pub := try.To1(decodeEd25519(fingerprint))
+	xPub := try.To1(x25519.PublicKey(pub))
+	xFingerprint := multibase.Encode(multibase.X25519Pub, xPub)
+
+	signing := id + "#" + fingerprint
+	agreement := id + "#" + xFingerprint
+	refs := []doc.Relationship{{Ref: signing}}
+	return &doc.Doc{
+		Context: []string{doc.Context},
+		ID:      id,
+		VerificationMethod: []doc.VerificationMethod{{
+			ID:              signing,
+			Type:            doc.TypeEd25519VerificationKey2018,
+			Controller:      id,
+			PublicKeyBase58: base58.Encode(pub),
+		}, {
+			ID:              agreement,
+			Type:            doc.TypeX25519KeyAgreementKey2019,
+			Controller:      id,
+			PublicKeyBase58: base58.Encode(xPub),
+		}},
+		Authentication:       refs,
+		AssertionMethod:      refs,
+		CapabilityInvocation: refs,
+		CapabilityDelegation: refs,
+		KeyAgreement:         []doc.Relationship{{Ref: agreement}},
+	}, nil
+}
+
+// IsDIDKey tells if the string is a did:key.
+func IsDIDKey(s string) bool {
+	return strings.HasPrefix(s, Prefix)
+}
+
+func parse(didKey string) (ed25519.PublicKey, error) {
+	if !IsDIDKey(didKey) {
+		return nil, fmt.Errorf("%w: %q isn't did:key", identifiers.ErrInvalid, didKey)
+	}
+	didKey, _, _ = strings.Cut(didKey, "#")
+	return decodeEd25519(strings.TrimPrefix(didKey, Prefix))
+}
+
+func decodeEd25519(fingerprint string) (ed25519.PublicKey, error) {
+	codec, pub, err := multibase.Decode(fingerprint)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", identifiers.ErrInvalid, err)
+	}
+	if codec != multibase.Ed25519Pub || len(pub) != ed25519.PublicKeySize {
+		return nil, fmt.Errorf("%w: %q isn't Ed25519 key", identifiers.ErrInvalid, fingerprint)
+	}
+	return pub, nil
+}
@@ -0,0 +1,71 @@
+package key
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/findy-network/findy-wrapper-go/helpers"
+	"github.com/findy-network/findy-wrapper-go/identifiers"
+	"github.com/lainio/err2/assert"
+)
+
+const (
+	testVerKey = "8HH5gYEeNc3z7PYXmd54d4x6qAfCNrqQqEB3nS7Zfu7K"
+	testDIDKey = "did:key:z6MkmjY8GnV5i9YTDtPETC2uUAW6ejw3nk5mXF5yci5ab7th"
+)
+
+func TestFromVerKey(t *testing.T) {
+	defer assert.PushTester(t)()
+
+	didKey, err := FromVerKey(testVerKey)
+	assert.NoError(err)
+	assert.Equal(didKey, testDIDKey)
+
+	verKey, err := VerKey(testDIDKey + "#z6MkmjY8GnV5i9YTDtPETC2uUAW6ejw3nk5mXF5yci5ab7th")
+	assert.NoError(err)
+	assert.Equal(verKey, testVerKey)
+
+	_, err = FromVerKey("8HH5gYEeNc3z7PYXmd54d4x6qAf")
+	assert.That(errors.Is(err, identifiers.ErrInvalid))
+	_, err = VerKey("did:sov:V4SGRU86Z58d6TV7PBUe6f")
+	assert.That(errors.Is(err, identifiers.ErrInvalid))
+	// X25519 keys aren't supported as DIDs
+	_, err = VerKey("did:key:z6LShs9GGnqk85isEBzzshkuVWrVKsRp24GnDuHk8QWkARMW")
+	assert.That(errors.Is(err, identifiers.ErrInvalid))
+}
+
+func TestResolve(t *testing.T) {
+	defer assert.PushTester(t)()
+
+	// the example of the did:key spec
+	const didKey = "did:key:z6MkiTBz1ymuepAQ4HEHYSF1H8quG5GLVVQR3djdX3mDooWp"
+	d, err := Resolve(didKey)
+	assert.NoError(err)
+	assert.Equal(d.ID, didKey)
+	assert.SLen(d.VerificationMethod, 2)
+	assert.Equal(d.KeyAgreement[0].ID(), didKey+"#z6LShs9GGnqk85isEBzzshkuVWrVKsRp24GnDuHk8QWkARMW")
+
+	m, err := d.Method(d.Authentication[0].ID())
+	assert.NoError(err)
+	assert.That(m.IsEd25519())
+	verKeys, err := d.VerKeys()
+	assert.NoError(err)
+	verKey, err := VerKey(didKey)
+	assert.NoError(err)
+	assert.DeepEqual(verKeys, []string{verKey})
+}
+
+func TestCreate(t *testing.T) {
+	defer assert.PushTester(t)()
+
+	w, name := helpers.CreateAndOpenTestWallet(t)
+	didKey, err := Create(w, "000000000000000000000000Steward1")
+	assert.NoError(err)
+	d, err := Resolve(didKey)
+	assert.NoError(err)
+	verKeys, err := d.VerKeys()
+	assert.NoError(err)
+	assert.Equal(verKeys[0], "FYmoFw55GeQH7SRFa37dkx1d2dZ3zUF8ckg7wmL7ofN4")
+
+	helpers.CloseAndDeleteTestWallet(w, name, t)
+}
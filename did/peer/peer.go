@@ -0,0 +1,285 @@
+/*
+Package peer implements the did:peer method numalgos 0 and 2. Both are
+resolved from the DID itself, which makes it possible to build connections
+without a ledger.
+
+Numalgo 0 is the Ed25519 key like did:key. Numalgo 2 has the keys and the
+services of the DID Document in the DID:
+
+	did:peer:2.Vz6Mk....Ez6LS....SeyJ0IjoiZG0i...
+
+NewNumalgo2 adds both the Ed25519 verkey (V) and its X25519 conversion (E) of
+the wallet keys, and the verkeys of the resolved document are the ones to use
+with crypto.Pack:
+
+	myDID, myVerKey, err := peer.CreateNumalgo2(wallet, service)
+	...
+	theirVerKeys, err := peer.VerKeys(theirDID)
+*/
+package peer
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/findy-network/findy-wrapper-go/crypto"
+	"github.com/findy-network/findy-wrapper-go/did/doc"
+	"github.com/findy-network/findy-wrapper-go/did/key"
+	"github.com/findy-network/findy-wrapper-go/identifiers"
+	"github.com/findy-network/findy-wrapper-go/internal/base58"
+	"github.com/findy-network/findy-wrapper-go/internal/multibase"
+	"github.com/findy-network/findy-wrapper-go/internal/x25519"
+	"github.com/lainio/err2"
+	"github.com/lainio/err2/try"
+)
+
+// Prefix is the prefix of the did:peer DIDs.
+const Prefix = "did:peer:"
+
+// Prefixes of the supported numalgos.
+const (
+	Numalgo0 = Prefix + "0"
+	Numalgo2 = Prefix + "2"
+)
+
+// Purpose codes of the numalgo 2 elements.
+const (
+	PurposeAssertion            = 'A'
+	PurposeEncryption           = 'E'
+	PurposeVerification         = 'V'
+	PurposeCapabilityInvocation = 'I'
+	PurposeCapabilityDelegation = 'D'
+	PurposeService              = 'S'
+)
+
+// abbreviations of the numalgo 2 service encoding.
+var abbreviations = map[string]string{
+	"type":               "t",
+	"serviceEndpoint":    "s",
+	"routingKeys":        "r",
+	"accept":             "a",
+	doc.ServiceDIDCommV2: "dm",
+}
+
+// CreateNumalgo0 creates a new key to the wallet and returns its numalgo 0
+// DID and the verkey.
+func CreateNumalgo0(wallet int) (didPeer, verKey string, err error) {
+	defer err2.Handle(&err, "did:peer create")
+
+	verKey = string(try.To1(crypto.NewKey(wallet, "", nil)))
+	return try.To1(NewNumalgo0(verKey)), verKey, nil
+}
+
+// CreateNumalgo2 creates a new key to the wallet and returns its numalgo 2
+// DID with the services, and the verkey.
+func CreateNumalgo2(wallet int, services ...doc.Service) (didPeer, verKey string, err error) {
+	defer err2.Handle(&err, "did:peer create")
+
+	verKey = string(try.To1(crypto.NewKey(wallet, "", nil)))
+	return try.To1(NewNumalgo2([]string{verKey}, services...)), verKey, nil
+}
+
+// NewNumalgo0 returns the numalgo 0 DID of the base58 Ed25519 verkey.
+func NewNumalgo0(verKey string) (string, error) {
+	fingerprint, err := key.Fingerprint(verKey)
+	if err != nil {
+		return "", err
+	}
+	return Numalgo0 + fingerprint, nil
+}
+
+// NewNumalgo2 returns the numalgo 2 DID of the base58 Ed25519 verkeys and the
+// services. Every verkey is a verification key, and its X25519 conversion an
+// encryption key. The IDs of the services are optional.
+func NewNumalgo2(verKeys []string, services ...doc.Service) (didPeer string, err error) {
+	defer err2.Handle(&err, "did:peer numalgo 2")
+
+	var b strings.Builder
+	b.WriteString(Numalgo2)
+	encryption := make([]string, 0, len(verKeys))
+	for _, verKey := range verKeys {
+		pub := try.To1(base58.Decode(verKey))
+		encryption = append(encryption, multibase.Encode(multibase.X25519Pub, try.To1(x25519.PublicKey(pub))))
+		b.WriteString("." + string(PurposeVerification) + try.To1(key.Fingerprint(verKey)))
+	}
+	for _, fingerprint := range encryption {
+		b.WriteString("." + string(PurposeEncryption) + fingerprint)
+	}
+	for i, s := range services {
+		if s.ID == serviceID(i) {
+			s.ID = ""
+		}
+		b.WriteString("." + string(PurposeService) + try.To1(encodeService(s)))
+	}
+	return b.String(), nil
+}
+
+// VerKeys resolves the did:peer and returns the base58 verkeys of its Ed25519
+// authentication keys.
+func VerKeys(didPeer string) ([]string, error) {
+	d, err := Resolve(didPeer)
+	if err != nil {
+		return nil, err
+	}
+	return d.VerKeys()
+}
+
+// Resolve resolves the numalgo 0 or 2 DID to its DID Document.
+func Resolve(didPeer string) (*doc.Doc, error) {
+	didPeer, _, _ = strings.Cut(didPeer, "#")
+	switch {
+	case strings.HasPrefix(didPeer, Numalgo0):
+		return key.NewDoc(didPeer, strings.TrimPrefix(didPeer, Numalgo0))
+	case strings.HasPrefix(didPeer, Numalgo2+"."):
+		return resolveNumalgo2(didPeer)
+	}
+	return nil, fmt.Errorf("%w: %q isn't did:peer numalgo 0 or 2", identifiers.ErrInvalid, didPeer)
+}
+
+// IsDIDPeer tells if the string is a did:peer.
+func IsDIDPeer(s string) bool {
+	return strings.HasPrefix(s, Prefix)
+}
+
+func resolveNumalgo2(didPeer string) (d *doc.Doc, err error) {
+	defer err2.Handle(&err, "did:peer %s", didPeer)
+
+	d = &doc.Doc{Context: []string{doc.Context}, ID: didPeer}
+	for _, element := range strings.Split(strings.TrimPrefix(didPeer, Numalgo2+"."), ".") {
+		if len(element) < 2 {
+			return nil, fmt.Errorf("%w: empty element", identifiers.ErrInvalid)
+		}
+		purpose, value := element[0], element[1:]
+		if purpose == PurposeService {
+			s := try.To1(decodeService(value))
+			if s.ID == "" {
+				s.ID = serviceID(len(d.Service))
+			}
+			d.Service = append(d.Service, s)
+			continue
+		}
+		try.To(addKey(d, purpose, value))
+	}
+	return d, nil
+}
+
+func addKey(d *doc.Doc, purpose byte, fingerprint string) error {
+	codec, _, err := multibase.Decode(fingerprint)
+	if err != nil {
+		return fmt.Errorf("%w: %v", identifiers.ErrInvalid, err)
+	}
+	m := doc.VerificationMethod{
+		ID:                 "#key-" + strconv.Itoa(len(d.VerificationMethod)+1),
+		Controller:         d.ID,
+		PublicKeyMultibase: fingerprint,
+	}
+	switch codec {
+	case multibase.Ed25519Pub:
+		m.Type = doc.TypeEd25519VerificationKey2020
+	case multibase.X25519Pub:
+		m.Type = doc.TypeX25519KeyAgreementKey2020
+	default:
+		return fmt.Errorf("%w: unsupported key %s", identifiers.ErrInvalid, fingerprint)
+	}
+	d.VerificationMethod = append(d.VerificationMethod, m)
+
+	ref := []doc.Relationship{{Ref: m.ID}}
+	switch purpose {
+	case PurposeAssertion:
+		d.AssertionMethod = append(d.AssertionMethod, ref...)
+	case PurposeEncryption:
+		d.KeyAgreement = append(d.KeyAgreement, ref...)
+	case PurposeVerification:
+		d.Authentication = append(d.Authentication, ref...)
+	case PurposeCapabilityInvocation:
+		d.CapabilityInvocation = append(d.CapabilityInvocation, ref...)
+	case PurposeCapabilityDelegation:
+		d.CapabilityDelegation = append(d.CapabilityDelegation, ref...)
+	default:
+		return fmt.Errorf("%w: unknown purpose %q", identifiers.ErrInvalid, purpose)
+	}
+	return nil
+}
+
+// serviceID is the default ID of the i'th service.
+func serviceID(i int) string {
+	if i == 0 {
+		return "#service"
+	}
+	return "#service-" + strconv.Itoa(i)
+}
+
+func encodeService(s doc.Service) (string, error) {
+	var m map[string]any
+	if err := remarshal(s, &m); err != nil {
+		return "", err
+	}
+	if m["id"] == "" {
+		delete(m, "id")
+	}
+	data, err := json.Marshal(replaceKeys(m, abbreviate))
+	if err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(data), nil
+}
+
+func decodeService(encoded string) (s doc.Service, err error) {
+	data, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(encoded, "="))
+	if err != nil {
+		return s, fmt.Errorf("%w: service: %v", identifiers.ErrInvalid, err)
+	}
+	var m map[string]any
+	if err := json.Unmarshal(data, &m); err != nil {
+		return s, fmt.Errorf("%w: service: %v", identifiers.ErrInvalid, err)
+	}
+	if err := remarshal(replaceKeys(m, expand), &s); err != nil {
+		return s, fmt.Errorf("%w: service: %v", identifiers.ErrInvalid, err)
+	}
+	return s, nil
+}
+
+// replaceKeys returns the service object whose keys and type value are
+// replaced, also in the nested endpoint object.
+func replaceKeys(m map[string]any, replace func(string) string) map[string]any {
+	replaced := make(map[string]any, len(m))
+	for k, v := range m {
+		switch value := v.(type) {
+		case string:
+			if k == "type" || k == abbreviations["type"] {
+				v = replace(value)
+			}
+		case map[string]any:
+			v = replaceKeys(value, replace)
+		}
+		replaced[replace(k)] = v
+	}
+	return replaced
+}
+
+func abbreviate(s string) string {
+	if a, ok := abbreviations[s]; ok {
+		return a
+	}
+	return s
+}
+
+func expand(s string) string {
+	for full, a := range abbreviations {
+		if a == s {
+			return full
+		}
+	}
+	return s
+}
+
+func remarshal(from, to any) error {
+	data, err := json.Marshal(from)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, to)
+}
@@ -0,0 +1,145 @@
+package peer
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/findy-network/findy-wrapper-go/did/doc"
+	"github.com/findy-network/findy-wrapper-go/helpers"
+	"github.com/findy-network/findy-wrapper-go/identifiers"
+	"github.com/lainio/err2/assert"
+)
+
+const testVerKey = "8HH5gYEeNc3z7PYXmd54d4x6qAfCNrqQqEB3nS7Zfu7K"
+
+func TestNumalgo0(t *testing.T) {
+	defer assert.PushTester(t)()
+
+	didPeer, err := NewNumalgo0(testVerKey)
+	assert.NoError(err)
+	assert.Equal(didPeer, "did:peer:0z6MkmjY8GnV5i9YTDtPETC2uUAW6ejw3nk5mXF5yci5ab7th")
+
+	verKeys, err := VerKeys(didPeer)
+	assert.NoError(err)
+	assert.DeepEqual(verKeys, []string{testVerKey})
+}
+
+// specNumalgo2 is the numalgo 2 example of the did:peer spec.
+const specNumalgo2 = "did:peer:2.Vz6Mkj3PUd1WjvaDhNZhhhXQdz5UnZXmS7ehtx8bsPpD47kKc" +
+	".Ez6LSg8zQom395jKLrGiBNruB9MAsv8ebwKZ3uTHMjU1eRpbe" +
+	".SeyJ0IjoiZG0iLCJzIjp7InVyaSI6Imh0dHA6Ly9leGFtcGxlLmNvbS9kaWRjb21tIiwiYSI6WyJkaWRjb21tL3YyIl0s" +
+	"InIiOlsiZGlkOmV4YW1wbGU6MTIzNDU2Nzg5YWJjZGVmZ2hpI2tleS0xIl19fQ"
+
+func TestNumalgo2(t *testing.T) {
+	defer assert.PushTester(t)()
+
+	d, err := Resolve(specNumalgo2)
+	assert.NoError(err)
+	assert.Equal(d.ID, specNumalgo2)
+	assert.SLen(d.VerificationMethod, 2)
+	assert.Equal(d.VerificationMethod[0].PublicKeyMultibase, "z6Mkj3PUd1WjvaDhNZhhhXQdz5UnZXmS7ehtx8bsPpD47kKc")
+	assert.Equal(d.Authentication[0].ID(), "#key-1")
+	assert.Equal(d.KeyAgreement[0].ID(), "#key-2")
+	assert.DeepEqual(d.Service, []doc.Service{{
+		ID:              "#service",
+		Type:            doc.ServiceDIDCommV2,
+		ServiceEndpoint: "http://example.com/didcomm",
+		Accept:          []string{"didcomm/v2"},
+		RoutingKeys:     []string{"did:example:123456789abcdefghi#key-1"},
+		EndpointObject:  true,
+	}})
+	data, err := json.Marshal(d.Service[0])
+	assert.NoError(err)
+	assert.Equal(string(data), `{"id":"#service","type":"DIDCommMessaging","serviceEndpoint":`+
+		`{"uri":"http://example.com/didcomm","accept":["didcomm/v2"],`+
+		`"routingKeys":["did:example:123456789abcdefghi#key-1"]}}`)
+
+	// the object endpoint is encoded back as the object
+	didPeer, err := NewNumalgo2([]string{testVerKey}, d.Service...)
+	assert.NoError(err)
+	d2, err := Resolve(didPeer)
+	assert.NoError(err)
+	assert.DeepEqual(d2.Service, d.Service)
+}
+
+func TestNewNumalgo2(t *testing.T) {
+	defer assert.PushTester(t)()
+
+	services := []doc.Service{{
+		Type:            doc.ServiceDIDCommV2,
+		ServiceEndpoint: "https://example.com/endpoint",
+		RoutingKeys:     []string{"did:example:somemediator#somekey"},
+		Accept:          []string{"didcomm/v2"},
+	}, {
+		ID:              "#didcomm",
+		Type:            doc.ServiceDIDCommV1,
+		ServiceEndpoint: "http://localhost:8080",
+		RecipientKeys:   []string{"did:key:z6MkmjY8GnV5i9YTDtPETC2uUAW6ejw3nk5mXF5yci5ab7th"},
+	}}
+	didPeer, err := NewNumalgo2([]string{testVerKey}, services...)
+	assert.NoError(err)
+	assert.That(strings.HasPrefix(didPeer, "did:peer:2.Vz6MkmjY8GnV5i9YTDtPETC2uUAW6ejw3nk5mXF5yci5ab7th.Ez6LS"))
+
+	encoded := strings.Split(didPeer, ".S")[1]
+	data, err := base64.RawURLEncoding.DecodeString(encoded)
+	assert.NoError(err)
+	assert.Equal(string(data), `{"a":["didcomm/v2"],"r":["did:example:somemediator#somekey"],`+
+		`"s":"https://example.com/endpoint","t":"dm"}`)
+
+	d, err := Resolve(didPeer + "#key-1")
+	assert.NoError(err)
+	assert.Equal(d.ID, didPeer)
+	assert.SLen(d.VerificationMethod, 2)
+	assert.Equal(d.Authentication[0].ID(), "#key-1")
+	assert.Equal(d.KeyAgreement[0].ID(), "#key-2")
+	services[0].ID = "#service"
+	assert.DeepEqual(d.Service, services)
+
+	m, err := d.Method(didPeer + "#key-2")
+	assert.NoError(err)
+	assert.Equal(m.Type, doc.TypeX25519KeyAgreementKey2020)
+	verKeys, err := d.VerKeys()
+	assert.NoError(err)
+	assert.DeepEqual(verKeys, []string{testVerKey})
+}
+
+func TestResolveInvalid(t *testing.T) {
+	defer assert.PushTester(t)()
+
+	for _, didPeer := range []string{
+		"did:peer:1zQmZMygzYqNwU6Uhmewx5Xepf2VLp5S4HLSwwgf2aiKZuwa",
+		"did:peer:2",
+		"did:peer:2.Xz6MkmjY8GnV5i9YTDtPETC2uUAW6ejw3nk5mXF5yci5ab7th",
+		"did:peer:2.Vz6MkmjY8GnV5i9YTDtPETC2uUAW6ejw3nk5mXF5yci5ab7th..Sabc",
+		"did:peer:2.S!!!",
+		"did:key:z6MkmjY8GnV5i9YTDtPETC2uUAW6ejw3nk5mXF5yci5ab7th",
+	} {
+		_, err := Resolve(didPeer)
+		assert.That(errors.Is(err, identifiers.ErrInvalid), didPeer)
+	}
+}
+
+func TestCreateNumalgo2(t *testing.T) {
+	defer assert.PushTester(t)()
+
+	w, name := helpers.CreateAndOpenTestWallet(t)
+	didPeer, verKey, err := CreateNumalgo2(w, doc.Service{
+		Type:            doc.ServiceDIDCommV2,
+		ServiceEndpoint: "http://localhost:8080",
+	})
+	assert.NoError(err)
+	verKeys, err := VerKeys(didPeer)
+	assert.NoError(err)
+	assert.DeepEqual(verKeys, []string{verKey})
+
+	didPeer, verKey, err = CreateNumalgo0(w)
+	assert.NoError(err)
+	verKeys, err = VerKeys(didPeer)
+	assert.NoError(err)
+	assert.DeepEqual(verKeys, []string{verKey})
+
+	helpers.CloseAndDeleteTestWallet(w, name, t)
+}
@@ -0,0 +1,76 @@
+package resolver
+
+import (
+	"github.com/findy-network/findy-wrapper-go/did"
+	"github.com/findy-network/findy-wrapper-go/did/doc"
+	"github.com/findy-network/findy-wrapper-go/identifiers"
+	"github.com/findy-network/findy-wrapper-go/internal/base58"
+	"github.com/findy-network/findy-wrapper-go/internal/x25519"
+	"github.com/findy-network/findy-wrapper-go/ledger"
+	"github.com/lainio/err2"
+	"github.com/lainio/err2/try"
+)
+
+// Indy resolves did:sov and did:indy DIDs. The verkey is read with
+// ledger.ReadDID, i.e. through the plugin ledgers, and the DIDComm service
+// from did.Endpoint. Register it for both methods when both are used.
+type Indy struct {
+	Pool   int
+	Wallet int
+
+	// Submitter is the DID used to read the ledger. It's optional.
+	Submitter string
+}
+
+// Resolve resolves the Indy DID to the DID Document of the Sovrin DID method
+// spec: the verkey is key-1, its X25519 conversion key-agreement-1, and the
+// endpoint the did-communication service if the DID has it.
+func (r *Indy) Resolve(didName string) (d *doc.Doc, err error) {
+	defer err2.Handle(&err)
+
+	id := try.To1(identifiers.ParseDID(didName))
+	if id.Method == identifiers.Unqualified {
+		id = id.Sov()
+	}
+	unqualified := id.Unqualify().String()
+
+	verKey := try.To1(ledger.ReadDID(r.Pool, r.Submitter, unqualified))
+	pub := try.To1(base58.Decode(verKey))
+	xPub := try.To1(x25519.PublicKey(pub))
+
+	didID := id.String()
+	d = &doc.Doc{
+		Context: []string{doc.Context},
+		ID:      didID,
+		VerificationMethod: []doc.VerificationMethod{{
+			ID:              didID + "#key-1",
+			Type:            doc.TypeEd25519VerificationKey2018,
+			Controller:      didID,
+			PublicKeyBase58: verKey,
+		}, {
+			ID:              didID + "#key-agreement-1",
+			Type:            doc.TypeX25519KeyAgreementKey2019,
+			Controller:      didID,
+			PublicKeyBase58: base58.Encode(xPub),
+		}},
+		Authentication:  []doc.Relationship{{Ref: didID + "#key-1"}},
+		AssertionMethod: []doc.Relationship{{Ref: didID + "#key-1"}},
+		KeyAgreement:    []doc.Relationship{{Ref: didID + "#key-agreement-1"}},
+	}
+
+	// the endpoint is optional
+	res := <-did.Endpoint(r.Wallet, r.Pool, unqualified)
+	if res.Err() == nil && res.Str1() != "" {
+		recipientKey := res.Str2()
+		if recipientKey == "" {
+			recipientKey = verKey
+		}
+		d.Service = []doc.Service{{
+			ID:              didID + "#did-communication",
+			Type:            doc.ServiceDIDCommV1,
+			ServiceEndpoint: res.Str1(),
+			RecipientKeys:   []string{recipientKey},
+		}}
+	}
+	return d, nil
+}
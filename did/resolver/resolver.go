@@ -0,0 +1,219 @@
+/*
+Package resolver resolves DIDs to DID Documents with the resolvers which are
+registered by the DID method, much like the ledger plugins are registered to
+the pool package. key and peer methods are registered by default, because they
+are resolved from the DID itself. The Indy and Web resolvers need their
+configuration, so register them before use:
+
+	resolver.Register(resolver.MethodSov, &resolver.Indy{Pool: pool, Wallet: wallet}, time.Hour)
+	resolver.Register(resolver.MethodWeb, &resolver.Web{}, 10*time.Minute)
+	...
+	d, err := resolver.Resolve(theirDID)
+	verKeys, err := d.VerKeys()
+
+Every method has its own cache, whose TTL is given at the registration. Zero
+TTL means no caching. The cached documents are copied for every caller, so
+the returned documents can be modified. ResolveContext passes the context to the resolvers which
+implement ContextResolver, like Web does.
+*/
+package resolver
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/findy-network/findy-wrapper-go/did/doc"
+	"github.com/findy-network/findy-wrapper-go/did/key"
+	"github.com/findy-network/findy-wrapper-go/did/peer"
+)
+
+// DID methods of the resolvers of this package.
+const (
+	MethodSov  = "sov"
+	MethodIndy = "indy"
+	MethodKey  = "key"
+	MethodPeer = "peer"
+	MethodWeb  = "web"
+)
+
+// ErrUnsupportedMethod is returned when the method has no resolver.
+var ErrUnsupportedMethod = errors.New("resolver: unsupported DID method")
+
+// Resolver resolves the DIDs of its method.
+type Resolver interface {
+	Resolve(did string) (*doc.Doc, error)
+}
+
+// ContextResolver is implemented by the resolvers which do I/O and can be
+// canceled with a context.
+type ContextResolver interface {
+	ResolveContext(ctx context.Context, did string) (*doc.Doc, error)
+}
+
+// Func is an adapter to use a function as a Resolver.
+type Func func(did string) (*doc.Doc, error)
+
+// Resolve calls the function.
+func (f Func) Resolve(did string) (*doc.Doc, error) {
+	return f(did)
+}
+
+// now is replaced in the tests.
+var now = time.Now
+
+// Registry is the set of the method resolvers and their caches. The zero
+// value isn't usable, use NewRegistry.
+type Registry struct {
+	sync.RWMutex
+	methods map[string]*method
+}
+
+type method struct {
+	Resolver
+	ttl time.Duration
+
+	sync.Mutex
+	cache map[string]entry
+}
+
+type entry struct {
+	doc     *doc.Doc
+	expires time.Time
+}
+
+// NewRegistry returns a registry without resolvers.
+func NewRegistry() *Registry {
+	return &Registry{methods: make(map[string]*method)}
+}
+
+// Register sets the resolver of the DID method. The documents are cached for
+// the TTL. Registering the method again replaces its resolver and its cache.
+func (r *Registry) Register(methodName string, resolver Resolver, ttl time.Duration) {
+	r.Lock()
+	defer r.Unlock()
+
+	r.methods[methodName] = &method{Resolver: resolver, ttl: ttl, cache: make(map[string]entry)}
+}
+
+// Methods returns the registered DID methods.
+func (r *Registry) Methods() []string {
+	r.RLock()
+	defer r.RUnlock()
+
+	methods := make([]string, 0, len(r.methods))
+	for name := range r.methods {
+		methods = append(methods, name)
+	}
+	return methods
+}
+
+// Resolve resolves the DID with the resolver of its method. The DID URL
+// fragment is ignored. Unqualified Indy DIDs are resolved as did:sov.
+func (r *Registry) Resolve(did string) (d *doc.Doc, err error) {
+	return r.ResolveContext(context.Background(), did)
+}
+
+// ResolveContext is Resolve with the context, which is given to the resolver
+// if it implements ContextResolver.
+func (r *Registry) ResolveContext(ctx context.Context, did string) (d *doc.Doc, err error) {
+	did, _, _ = strings.Cut(did, "#")
+	m, err := r.method(Method(did))
+	if err != nil {
+		return nil, err
+	}
+	return m.resolve(ctx, did)
+}
+
+// Purge removes the DID from the cache of its method, e.g. after key
+// rotation.
+func (r *Registry) Purge(did string) {
+	did, _, _ = strings.Cut(did, "#")
+	if m, err := r.method(Method(did)); err == nil {
+		m.Lock()
+		delete(m.cache, did)
+		m.Unlock()
+	}
+}
+
+func (r *Registry) method(methodName string) (*method, error) {
+	r.RLock()
+	defer r.RUnlock()
+
+	m, ok := r.methods[methodName]
+	if !ok {
+		return nil, fmt.Errorf("%w %q", ErrUnsupportedMethod, methodName)
+	}
+	return m, nil
+}
+
+func (m *method) resolve(ctx context.Context, did string) (*doc.Doc, error) {
+	if m.ttl > 0 {
+		m.Lock()
+		e, ok := m.cache[did]
+		m.Unlock()
+		if ok && now().Before(e.expires) {
+			return e.doc.Clone(), nil
+		}
+	}
+	var (
+		d   *doc.Doc
+		err error
+	)
+	if cr, ok := m.Resolver.(ContextResolver); ok {
+		d, err = cr.ResolveContext(ctx, did)
+	} else {
+		d, err = m.Resolver.Resolve(did)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("resolver: %s: %w", did, err)
+	}
+	if m.ttl > 0 {
+		m.Lock()
+		m.cache[did] = entry{doc: d.Clone(), expires: now().Add(m.ttl)}
+		m.Unlock()
+	}
+	return d, nil
+}
+
+// Method returns the DID method of the DID. It's MethodSov for unqualified
+// DIDs.
+func Method(did string) string {
+	rest, ok := strings.CutPrefix(did, "did:")
+	if !ok {
+		return MethodSov
+	}
+	name, _, _ := strings.Cut(rest, ":")
+	return name
+}
+
+// Default is the registry of the package level functions.
+var Default = NewRegistry()
+
+func init() {
+	Default.Register(MethodKey, Func(key.Resolve), 0)
+	Default.Register(MethodPeer, Func(peer.Resolve), 0)
+}
+
+// Register registers the resolver of the method to the Default registry.
+func Register(methodName string, resolver Resolver, ttl time.Duration) {
+	Default.Register(methodName, resolver, ttl)
+}
+
+// Resolve resolves the DID with the Default registry.
+func Resolve(did string) (*doc.Doc, error) {
+	return Default.Resolve(did)
+}
+
+// ResolveContext resolves the DID with the Default registry and the context.
+func ResolveContext(ctx context.Context, did string) (*doc.Doc, error) {
+	return Default.ResolveContext(ctx, did)
+}
+
+// Purge removes the DID from the caches of the Default registry.
+func Purge(did string) {
+	Default.Purge(did)
+}
@@ -0,0 +1,244 @@
+package resolver
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/findy-network/findy-wrapper-go"
+	"github.com/findy-network/findy-wrapper-go/did"
+	"github.com/findy-network/findy-wrapper-go/did/doc"
+	"github.com/findy-network/findy-wrapper-go/helpers"
+	"github.com/findy-network/findy-wrapper-go/identifiers"
+	"github.com/findy-network/findy-wrapper-go/ledger"
+	"github.com/lainio/err2/assert"
+)
+
+const (
+	testVerKey = "8HH5gYEeNc3z7PYXmd54d4x6qAfCNrqQqEB3nS7Zfu7K"
+	testDIDKey = "did:key:z6MkmjY8GnV5i9YTDtPETC2uUAW6ejw3nk5mXF5yci5ab7th"
+)
+
+func TestDefault(t *testing.T) {
+	defer assert.PushTester(t)()
+
+	for _, didName := range []string{
+		testDIDKey,
+		testDIDKey + "#z6MkmjY8GnV5i9YTDtPETC2uUAW6ejw3nk5mXF5yci5ab7th",
+		"did:peer:0z6MkmjY8GnV5i9YTDtPETC2uUAW6ejw3nk5mXF5yci5ab7th",
+	} {
+		d, err := Resolve(didName)
+		assert.NoError(err)
+		verKeys, err := d.VerKeys()
+		assert.NoError(err)
+		assert.DeepEqual(verKeys, []string{testVerKey})
+	}
+
+	_, err := Resolve("did:example:123")
+	assert.That(errors.Is(err, ErrUnsupportedMethod))
+	_, err = Resolve("did:key:z6Mk")
+	assert.That(errors.Is(err, identifiers.ErrInvalid))
+}
+
+func TestCache(t *testing.T) {
+	defer assert.PushTester(t)()
+	defer func() { now = time.Now }()
+
+	start := time.Now()
+	now = func() time.Time { return start }
+	calls := 0
+	r := NewRegistry()
+	r.Register("example", Func(func(didName string) (*doc.Doc, error) {
+		calls++
+		return &doc.Doc{ID: didName}, nil
+	}), time.Minute)
+	r.Register("nocache", Func(func(didName string) (*doc.Doc, error) {
+		calls++
+		return &doc.Doc{ID: didName}, nil
+	}), 0)
+	assert.SLen(r.Methods(), 2)
+
+	for i := 0; i < 3; i++ {
+		d, err := r.Resolve("did:example:123#key-1")
+		assert.NoError(err)
+		assert.Equal(d.ID, "did:example:123")
+		assert.SLen(d.Service, 0)
+		// the callers cannot change the cached document
+		d.Service = append(d.Service, doc.Service{ID: "#added"})
+	}
+	assert.Equal(calls, 1)
+
+	now = func() time.Time { return start.Add(2 * time.Minute) }
+	_, err := r.Resolve("did:example:123")
+	assert.NoError(err)
+	assert.Equal(calls, 2)
+
+	r.Purge("did:example:123")
+	_, err = r.Resolve("did:example:123")
+	assert.NoError(err)
+	assert.Equal(calls, 3)
+
+	_, err = r.Resolve("did:nocache:123")
+	assert.NoError(err)
+	_, err = r.Resolve("did:nocache:123")
+	assert.NoError(err)
+	assert.Equal(calls, 5)
+
+	_, err = r.Resolve(testDIDKey)
+	assert.That(errors.Is(err, ErrUnsupportedMethod))
+}
+
+func TestMethod(t *testing.T) {
+	defer assert.PushTester(t)()
+
+	for didName, want := range map[string]string{
+		"V4SGRU86Z58d6TV7PBUe6f":                 MethodSov,
+		"did:sov:V4SGRU86Z58d6TV7PBUe6f":         MethodSov,
+		"did:indy:sovrin:V4SGRU86Z58d6TV7PBUe6f": MethodIndy,
+		testDIDKey:                               MethodKey,
+		"did:web:example.com":                    MethodWeb,
+	} {
+		assert.Equal(Method(didName), want)
+	}
+}
+
+func TestWebURL(t *testing.T) {
+	defer assert.PushTester(t)()
+
+	for didName, want := range map[string]string{
+		"did:web:w3c-ccg.github.io":             "https://w3c-ccg.github.io/.well-known/did.json",
+		"did:web:w3c-ccg.github.io:user:alice":  "https://w3c-ccg.github.io/user/alice/did.json",
+		"did:web:example.com%3A3000:user:alice": "https://example.com:3000/user/alice/did.json",
+		"did:web:example.com%3A3000":            "https://example.com:3000/.well-known/did.json",
+		"did:web:example.com:a%3Fb":             "https://example.com/a%3Fb/did.json",
+	} {
+		u, err := WebURL(didName)
+		assert.NoError(err)
+		assert.Equal(u, want)
+	}
+	for _, didName := range []string{
+		"did:web:", "did:key:abc", "did:web:example.com::x", "did:web:a%2Fb",
+		"did:web:evil.com%40example.com", "did:web:example.com%3Fx", "did:web:example.com%23x",
+		"did:web:%3A3000",
+	} {
+		_, err := WebURL(didName)
+		assert.That(errors.Is(err, identifiers.ErrInvalid), didName)
+	}
+}
+
+func TestWeb(t *testing.T) {
+	defer assert.PushTester(t)()
+
+	var host, webHost string
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := "did:web:" + webHost + strings.ReplaceAll(strings.TrimSuffix(r.URL.Path, "/did.json"), "/", ":")
+		switch r.URL.Path {
+		case "/.well-known/did.json":
+			id = "did:web:" + webHost
+		case "/wrong/did.json":
+			id = "did:web:example.com"
+		case "/user/alice/did.json":
+		default:
+			http.NotFound(w, r)
+			return
+		}
+		_ = json.NewEncoder(w).Encode(doc.Doc{
+			Context: []string{doc.Context},
+			ID:      id,
+			VerificationMethod: []doc.VerificationMethod{{
+				ID:              id + "#key-1",
+				Type:            doc.TypeEd25519VerificationKey2018,
+				Controller:      id,
+				PublicKeyBase58: testVerKey,
+			}},
+			Authentication: []doc.Relationship{{Ref: "#key-1"}},
+		})
+	}))
+	defer srv.Close()
+	host = strings.TrimPrefix(srv.URL, "https://")
+	webHost = strings.ReplaceAll(host, ":", "%3A")
+
+	r := NewRegistry()
+	r.Register(MethodWeb, &Web{Client: srv.Client()}, time.Minute)
+
+	for _, didName := range []string{
+		"did:web:" + webHost,
+		"did:web:" + webHost + ":user:alice",
+	} {
+		d, err := r.Resolve(didName)
+		assert.NoError(err)
+		assert.Equal(d.ID, didName)
+		verKeys, err := d.VerKeys()
+		assert.NoError(err)
+		assert.DeepEqual(verKeys, []string{testVerKey})
+	}
+
+	_, err := r.Resolve("did:web:" + webHost + ":wrong")
+	assert.Error(err)
+	_, err = r.Resolve("did:web:" + webHost + ":missing")
+	assert.Error(err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = r.ResolveContext(ctx, "did:web:"+webHost+":user:bob")
+	assert.That(errors.Is(err, context.Canceled))
+}
+
+func TestWeb_timeout(t *testing.T) {
+	defer assert.PushTester(t)()
+
+	done := make(chan struct{})
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {
+		<-done
+	}))
+	defer srv.Close()
+	defer close(done)
+
+	webHost := strings.ReplaceAll(strings.TrimPrefix(srv.URL, "https://"), ":", "%3A")
+	r := &Web{Client: srv.Client(), Timeout: 50 * time.Millisecond}
+	_, err := r.Resolve("did:web:" + webHost)
+	assert.That(errors.Is(err, context.DeadlineExceeded))
+}
+
+func TestIndy(t *testing.T) {
+	defer assert.PushTester(t)()
+
+	pool := helpers.OpenTestPool(t)
+	w, name := helpers.CreateAndOpenTestWallet(t)
+	r := <-did.Create(w)
+	assert.NoError(r.Err())
+	didName, verKey := r.Str1(), r.Str2()
+	r = <-did.SetEndpoint(w, didName, "http://localhost:8080", verKey)
+	assert.NoError(r.Err())
+
+	reg := NewRegistry()
+	reg.Register(MethodSov, &Indy{Pool: pool, Wallet: w}, time.Minute)
+
+	// the DID isn't in the plugin ledgers yet
+	_, err := reg.Resolve("did:sov:" + didName)
+	assert.Error(err)
+	assert.NoError(ledger.WriteDID(pool, w, didName, didName, verKey,
+		findy.NullString, findy.NullString))
+
+	d, err := reg.Resolve("did:sov:" + didName)
+	assert.NoError(err)
+	assert.Equal(d.ID, "did:sov:"+didName)
+	verKeys, err := d.VerKeys()
+	assert.NoError(err)
+	assert.DeepEqual(verKeys, []string{verKey})
+	s, err := d.ServiceByType(doc.ServiceDIDCommV1)
+	assert.NoError(err)
+	assert.Equal(s.ServiceEndpoint, "http://localhost:8080")
+
+	d, err = reg.Resolve(didName)
+	assert.NoError(err)
+	assert.Equal(d.ID, "did:sov:"+didName)
+
+	helpers.CloseAndDeleteTestWallet(w, name, t)
+	helpers.CloseTestPool(pool, t)
+}
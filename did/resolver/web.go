@@ -0,0 +1,95 @@
+package resolver
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/findy-network/findy-wrapper-go/did/doc"
+	"github.com/findy-network/findy-wrapper-go/identifiers"
+	"github.com/lainio/err2"
+	"github.com/lainio/err2/try"
+)
+
+// maxDocSize limits the size of the fetched did:web documents.
+const maxDocSize = 1 << 20
+
+// DefaultWebTimeout is the default timeout of fetching the did:web document.
+const DefaultWebTimeout = 30 * time.Second
+
+// Web resolves did:web DIDs by fetching the did.json over HTTPS. Client is
+// optional, http.DefaultClient is used by default. Timeout limits every fetch,
+// and it's DefaultWebTimeout if it isn't set.
+type Web struct {
+	Client  *http.Client
+	Timeout time.Duration
+}
+
+// Resolve fetches and parses the DID Document, whose ID must be the DID.
+func (r *Web) Resolve(didName string) (*doc.Doc, error) {
+	return r.ResolveContext(context.Background(), didName)
+}
+
+// ResolveContext is Resolve which fetches the document with the context.
+func (r *Web) ResolveContext(ctx context.Context, didName string) (d *doc.Doc, err error) {
+	defer err2.Handle(&err)
+
+	docURL := try.To1(WebURL(didName))
+	client := r.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	timeout := r.Timeout
+	if timeout <= 0 {
+		timeout = DefaultWebTimeout
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	req := try.To1(http.NewRequestWithContext(ctx, http.MethodGet, docURL, nil))
+	resp := try.To1(client.Do(req))
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("%s: %s", docURL, resp.Status)
+	}
+
+	d = new(doc.Doc)
+	try.To(json.NewDecoder(io.LimitReader(resp.Body, maxDocSize)).Decode(d))
+	if d.ID != didName {
+		return nil, fmt.Errorf("document ID %s isn't %s", d.ID, didName)
+	}
+	return d, nil
+}
+
+// WebURL returns the HTTPS URL of the did:web DID Document: the domain, which
+// can have a percent encoded port, and the optional colon separated path.
+// The path defaults to /.well-known. The unescaped domain must be a plain
+// host with the optional port, i.e. it cannot have user info, a query or a
+// fragment.
+func WebURL(didName string) (string, error) {
+	rest, ok := strings.CutPrefix(didName, "did:web:")
+	if !ok || rest == "" {
+		return "", fmt.Errorf("%w: %q isn't did:web", identifiers.ErrInvalid, didName)
+	}
+	parts := strings.Split(rest, ":")
+	for i, part := range parts {
+		p, err := url.PathUnescape(part)
+		if err != nil || p == "" || strings.Contains(p, "/") {
+			return "", fmt.Errorf("%w: did:web %q", identifiers.ErrInvalid, didName)
+		}
+		parts[i] = p
+	}
+	host := parts[0]
+	if u, err := url.Parse("https://" + host); err != nil || u.Host != host || u.Hostname() == "" {
+		return "", fmt.Errorf("%w: did:web %q has invalid domain", identifiers.ErrInvalid, didName)
+	}
+	if len(parts) == 1 {
+		parts = append(parts, ".well-known")
+	}
+	u := url.URL{Scheme: "https", Host: host, Path: "/" + strings.Join(parts[1:], "/") + "/did.json"}
+	return u.String(), nil
+}
@@ -0,0 +1,201 @@
+/*
+Package rotation rotates the verkey of the wallet's DID and publishes it. The
+flow is:
+
+ 1. did.ReplaceKeysStart generates the new key, which is pending in the
+    wallet.
+ 2. ledger.WriteDIDPrimary writes the NYM with the new verkey to the primary
+    ledger plugin, e.g. the Indy ledger. It's signed with the current key.
+ 3. did.ReplaceKeysApply takes the new key into use.
+ 4. The pairwise records whose MyDid is the DID are updated with UpdateMeta,
+    and the DID is purged from the resolver caches.
+ 5. ledger.WriteDIDSecondary writes the NYM to the other ledger plugins, e.g.
+    the cache.
+
+If the primary ledger write fails, the new key isn't applied, and the DID keeps
+its current key. If the primary ledger accepts the new verkey, but the wallet
+cannot take it into use, Rotate returns *PendingError, and Apply resumes the
+rotation from step 3. If only the secondary ledgers fail, the rotation is done,
+and the error wraps ErrSecondaryLedgers.
+
+	rot := rotation.Rotation{Pool: pool, Wallet: wallet}
+	res, err := rot.Rotate(compromisedDID, did.Did{})
+	for _, pw := range res.Pairwise {
+		// notify pw.TheirDid about the new key
+	}
+*/
+package rotation
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/findy-network/findy-wrapper-go"
+	"github.com/findy-network/findy-wrapper-go/did"
+	"github.com/findy-network/findy-wrapper-go/did/resolver"
+	"github.com/findy-network/findy-wrapper-go/identifiers"
+	"github.com/findy-network/findy-wrapper-go/ledger"
+	"github.com/findy-network/findy-wrapper-go/pairwise"
+	"github.com/lainio/err2"
+	"github.com/lainio/err2/try"
+)
+
+// Rotation is the configuration of the key rotation.
+type Rotation struct {
+	Pool   int
+	Wallet int
+
+	// SubmitterDID is the submitter of the NYM. It's the rotated DID itself
+	// if it's empty, which works when the DID owns its NYM.
+	SubmitterDID string
+
+	// Alias and Role of the NYM. The defaults are findy.NullString, which
+	// keep the current ones in the ledger.
+	Alias string
+	Role  string
+
+	// UpdateMeta returns the new metadata of the pairwise whose MyDid is the
+	// rotated DID. The metadata isn't changed if it's nil, but the records
+	// are still returned in the Result.
+	UpdateMeta func(pw pairwise.Data, newVerKey string) (meta string)
+
+	// Resolver is purged from the rotated DID. The default is
+	// resolver.Default.
+	Resolver *resolver.Registry
+}
+
+// Result is the result of the rotation.
+type Result struct {
+	DID       string
+	OldVerKey string
+	VerKey    string
+
+	// Pairwise are the records whose MyDid is the DID.
+	Pairwise []pairwise.Data
+}
+
+// ErrSecondaryLedgers is wrapped in the error which is returned with the
+// Result when the key is rotated, but the new verkey couldn't be written to
+// all of the secondary ledger plugins.
+var ErrSecondaryLedgers = errors.New("rotation: secondary ledgers not updated")
+
+// PendingError is returned when the primary ledger has the new verkey, but the
+// wallet couldn't take it into use. The new key is pending in the wallet, and
+// Apply resumes the rotation.
+type PendingError struct {
+	DID    string
+	VerKey string
+	Err    error
+}
+
+func (e *PendingError) Error() string {
+	return fmt.Sprintf("rotation of %s pending with verkey %s: %v", e.DID, e.VerKey, e.Err)
+}
+
+func (e *PendingError) Unwrap() error {
+	return e.Err
+}
+
+// Rotate rotates the key of the DID. The seed and the crypto type of the new
+// key are given in the identity, and they are optional. The Result is
+// returned with *PendingError and ErrSecondaryLedgers.
+func (r Rotation) Rotate(didName string, identity did.Did) (res *Result, err error) {
+	defer err2.Handle(&err, "rotate %s", didName)
+
+	didName = try.To1(identifiers.ParseDID(didName)).Unqualify().String()
+	res = &Result{DID: didName}
+
+	rr := <-did.LocalKey(r.Wallet, didName)
+	try.To(rr.Err())
+	res.OldVerKey = rr.Str1()
+
+	rr = <-did.ReplaceKeysStart(r.Wallet, didName, did.Did{Seed: identity.Seed, CryptoType: identity.CryptoType})
+	try.To(rr.Err())
+	res.VerKey = rr.Str1()
+
+	try.To(ledger.WriteDIDPrimary(r.Pool, r.Wallet, r.submitter(didName), didName,
+		res.VerKey, nullIfEmpty(r.Alias), nullIfEmpty(r.Role)))
+
+	rr = <-did.ReplaceKeysApply(r.Wallet, didName)
+	if rr.Err() != nil {
+		return res, &PendingError{DID: didName, VerKey: res.VerKey, Err: rr.Err()}
+	}
+	return res, r.finish(res)
+}
+
+// Apply resumes the rotation after *PendingError. It takes the pending key of
+// the DID into use, and does the rest of the rotation like Rotate.
+func (r Rotation) Apply(didName string) (res *Result, err error) {
+	defer err2.Handle(&err, "apply rotation of %s", didName)
+
+	didName = try.To1(identifiers.ParseDID(didName)).Unqualify().String()
+	res = &Result{DID: didName}
+
+	rr := <-did.LocalKey(r.Wallet, didName)
+	try.To(rr.Err())
+	res.OldVerKey = rr.Str1()
+
+	rr = <-did.ReplaceKeysApply(r.Wallet, didName)
+	try.To(rr.Err())
+
+	rr = <-did.LocalKey(r.Wallet, didName)
+	try.To(rr.Err())
+	res.VerKey = rr.Str1()
+
+	return res, r.finish(res)
+}
+
+// finish updates the pairwise records, the resolver caches and the secondary
+// ledgers after the new key is applied.
+func (r Rotation) finish(res *Result) (err error) {
+	defer err2.Handle(&err)
+
+	res.Pairwise = try.To1(r.updatePairwise(res.DID, res.VerKey))
+
+	registry := r.Resolver
+	if registry == nil {
+		registry = resolver.Default
+	}
+	registry.Purge(res.DID)
+	registry.Purge(identifiers.DID{Method: identifiers.MethodSov, ID: res.DID}.String())
+
+	err = ledger.WriteDIDSecondary(r.Pool, r.Wallet, r.submitter(res.DID), res.DID,
+		res.VerKey, nullIfEmpty(r.Alias), nullIfEmpty(r.Role))
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrSecondaryLedgers, err)
+	}
+	return nil
+}
+
+func (r Rotation) submitter(didName string) string {
+	if r.SubmitterDID != "" {
+		return r.SubmitterDID
+	}
+	return didName
+}
+
+func (r Rotation) updatePairwise(didName, verKey string) (pws []pairwise.Data, err error) {
+	defer err2.Handle(&err, "update pairwise")
+
+	rr := <-pairwise.List(r.Wallet)
+	try.To(rr.Err())
+	for _, pw := range pairwise.NewData(rr.Str1()) {
+		if pw.MyDid != didName {
+			continue
+		}
+		if r.UpdateMeta != nil {
+			pw.Metadata = r.UpdateMeta(pw, verKey)
+			rr = <-pairwise.SetMeta(r.Wallet, pw.TheirDid, pw.Metadata)
+			try.To(rr.Err())
+		}
+		pws = append(pws, pw)
+	}
+	return pws, nil
+}
+
+func nullIfEmpty(s string) string {
+	if s == "" {
+		return findy.NullString
+	}
+	return s
+}
@@ -0,0 +1,75 @@
+package rotation
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/lainio/err2/assert"
+
+	"github.com/findy-network/findy-wrapper-go"
+	"github.com/findy-network/findy-wrapper-go/did"
+	"github.com/findy-network/findy-wrapper-go/helpers"
+	"github.com/findy-network/findy-wrapper-go/ledger"
+	"github.com/findy-network/findy-wrapper-go/pairwise"
+)
+
+func TestRotate(t *testing.T) {
+	defer assert.PushTester(t)()
+
+	pool := helpers.OpenTestPool(t)
+	w, name := helpers.CreateAndOpenTestWallet(t)
+
+	r := <-did.CreateAndStore(w, did.Did{Seed: "000000000000000000000000Steward1"})
+	assert.NoError(r.Err())
+	stewardDID := r.Str1()
+	assert.NoError(ledger.WriteDID(pool, w, stewardDID, stewardDID, r.Str2(),
+		findy.NullString, findy.NullString))
+
+	r = <-did.Create(w)
+	assert.NoError(r.Err())
+	myDID, oldVerKey := r.Str1(), r.Str2()
+	assert.NoError(ledger.WriteDID(pool, w, stewardDID, myDID, oldVerKey,
+		findy.NullString, findy.NullString))
+
+	r = <-did.Create(w)
+	assert.NoError(r.Err())
+	theirDID := r.Str1()
+	r = <-pairwise.Create(w, theirDID, myDID, "connection")
+	assert.NoError(r.Err())
+
+	rot := Rotation{
+		Pool:         pool,
+		Wallet:       w,
+		SubmitterDID: stewardDID,
+		UpdateMeta: func(pw pairwise.Data, newVerKey string) string {
+			return pw.Metadata + ":" + newVerKey
+		},
+	}
+	res, err := rot.Rotate("did:sov:"+myDID, did.Did{})
+	assert.NoError(err)
+	assert.Equal(res.DID, myDID)
+	assert.Equal(res.OldVerKey, oldVerKey)
+	assert.NotEqual(res.VerKey, oldVerKey)
+	assert.SLen(res.Pairwise, 1)
+	assert.Equal(res.Pairwise[0].TheirDid, theirDID)
+
+	r = <-did.LocalKey(w, myDID)
+	assert.NoError(r.Err())
+	assert.Equal(r.Str1(), res.VerKey)
+	verKey, err := ledger.ReadDID(pool, stewardDID, myDID)
+	assert.NoError(err)
+	assert.Equal(verKey, res.VerKey)
+
+	// nothing is pending after the rotation
+	_, err = rot.Apply(myDID)
+	assert.Error(err)
+
+	r = <-pairwise.Get(w, theirDID)
+	assert.NoError(r.Err())
+	var pw pairwise.Data
+	assert.NoError(json.Unmarshal([]byte(r.Str1()), &pw))
+	assert.Equal(pw.Metadata, "connection:"+res.VerKey)
+
+	helpers.CloseAndDeleteTestWallet(w, name, t)
+	helpers.CloseTestPool(pool, t)
+}
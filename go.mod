@@ -8,6 +8,7 @@ require (
 	github.com/codenotary/immudb v1.0.5
 	github.com/golang/glog v1.2.1
 	github.com/lainio/err2 v1.0.0
+	golang.org/x/crypto v0.21.0
 	google.golang.org/grpc v1.64.0
 )
 
@@ -44,7 +45,6 @@ require (
 	github.com/spf13/pflag v1.0.5 // indirect
 	github.com/spf13/viper v1.15.0 // indirect
 	github.com/subosito/gotenv v1.4.2 // indirect
-	golang.org/x/crypto v0.21.0 // indirect
 	golang.org/x/exp v0.0.0-20230105202349-8879d0199aa3 // indirect
 	golang.org/x/net v0.23.0 // indirect
 	golang.org/x/sys v0.18.0 // indirect
@@ -3,6 +3,7 @@ package helpers
 import (
 	"fmt"
 	"os"
+	"path/filepath"
 	"sync"
 	"testing"
 	"time"
@@ -11,6 +12,7 @@ import (
 
 	"github.com/findy-network/findy-wrapper-go/pool"
 	"github.com/findy-network/findy-wrapper-go/wallet"
+	"github.com/findy-network/findy-wrapper-go/wallet/sqlstore"
 )
 
 // OpenTestPool is a helper function for tests to open a ledger pool.
@@ -71,17 +73,42 @@ func walletName() string {
 	return fmt.Sprintf("test_wallet_%v_%v", os.Getpid(), nameCounter)
 }
 
+var storageOnce sync.Once
+
+// testWalletConfig returns the config of the test wallet. The wallets are
+// stored to the sqlstore when FINDY_WALLET_STORAGE is its Type, which lets
+// any test run on it, e.g.:
+//
+//	FINDY_WALLET_STORAGE=findy_sql go test ./did/... ./crypto/...
+func testWalletConfig(t testing.TB, name string) wallet.Config {
+	storageType := os.Getenv("FINDY_WALLET_STORAGE")
+	if storageType == sqlstore.Type {
+		storageOnce.Do(func() {
+			s, err := sqlstore.Open(filepath.Join(os.TempDir(),
+				fmt.Sprintf("findy_sqlstore_%v", os.Getpid())))
+			if err == nil {
+				err = (<-sqlstore.Register(s)).Err()
+			}
+			if err != nil {
+				t.Fatal("Cannot register sqlstore:", err)
+			}
+		})
+	}
+	return wallet.Config{ID: name, StorageType: storageType}
+}
+
 // CreateAndOpenTestWallet is a helper function for tests to create and open a
 // new wallet. It returns a wallet handle and a wallet name. It generates unique
 // name for wallets based on time. Use CloseAndDeleteTestWallet for cleaning up.
-func CreateAndOpenTestWallet(t *testing.T) (handle int, name string) {
+func CreateAndOpenTestWallet(t testing.TB) (handle int, name string) {
 	walletName := walletName()
 
-	r := <-wallet.Create(wallet.Config{ID: walletName}, wallet.Credentials{Key: "6cih1cVgRH8yHD54nEYyPKLmdv67o8QbufxaTHot3Qxp", KeyDerivationMethod: "RAW"})
+	cfg := testWalletConfig(t, walletName)
+	r := <-wallet.Create(cfg, wallet.Credentials{Key: "6cih1cVgRH8yHD54nEYyPKLmdv67o8QbufxaTHot3Qxp", KeyDerivationMethod: "RAW"})
 	if r.Err() != nil {
 		t.Fatal("Cannot create test wallet")
 	}
-	r = <-wallet.Open(wallet.Config{ID: walletName}, wallet.Credentials{Key: "6cih1cVgRH8yHD54nEYyPKLmdv67o8QbufxaTHot3Qxp", KeyDerivationMethod: "RAW"})
+	r = <-wallet.Open(cfg, wallet.Credentials{Key: "6cih1cVgRH8yHD54nEYyPKLmdv67o8QbufxaTHot3Qxp", KeyDerivationMethod: "RAW"})
 	if r.Err() != nil {
 		t.Fatal("Cannot open test wallet")
 	}
@@ -90,12 +117,12 @@ func CreateAndOpenTestWallet(t *testing.T) (handle int, name string) {
 
 // CloseAndDeleteTestWallet is a helper function for tests to close and delete a
 // test wallet.
-func CloseAndDeleteTestWallet(w int, name string, t *testing.T) {
+func CloseAndDeleteTestWallet(w int, name string, t testing.TB) {
 	r := <-wallet.Close(w)
 	if r.Err() != nil {
 		t.Error("Cannot close test wallet")
 	}
-	r = <-wallet.Delete(wallet.Config{ID: name}, wallet.Credentials{Key: "6cih1cVgRH8yHD54nEYyPKLmdv67o8QbufxaTHot3Qxp", KeyDerivationMethod: "RAW"})
+	r = <-wallet.Delete(testWalletConfig(t, name), wallet.Credentials{Key: "6cih1cVgRH8yHD54nEYyPKLmdv67o8QbufxaTHot3Qxp", KeyDerivationMethod: "RAW"})
 	if r.Err() != nil {
 		t.Error("Cannot Delete test wallet")
 	}
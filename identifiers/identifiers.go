@@ -0,0 +1,445 @@
+/*
+Package identifiers parses, builds, validates, qualifies and unqualifies Indy
+identifiers: DIDs, schema IDs, cred def IDs and rev reg IDs. Every identifier
+has three forms:
+
+	Unqualified (legacy):  5Lgx4KLRTNgexDqT7WDALu:2:Education:1.0
+	Sov (indy-sdk):        schema:sov:did:sov:5Lgx4KLRTNgexDqT7WDALu:2:Education:1.0
+	Indy (did:indy):       did:indy:sovrin:5Lgx4KLRTNgexDqT7WDALu/anoncreds/v0/SCHEMA/Education/1.0
+
+The parse functions accept all of the forms, and String returns the form of
+the identifier's DID. libindy and the ledger plugins use the unqualified form,
+see Unqualify.
+
+	id, err := identifiers.ParseSchemaID(schemaID)
+	qualified := id.Indy("sovrin").String()
+*/
+package identifiers
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/findy-network/findy-wrapper-go/internal/base58"
+)
+
+// Method is the DID method of the identifier.
+type Method string
+
+// Supported DID methods. Unqualified is the legacy form without a method.
+const (
+	Unqualified Method = ""
+	MethodSov   Method = "sov"
+	MethodIndy  Method = "indy"
+)
+
+// Markers, types and prefixes of the identifiers.
+const (
+	schemaMarker  = "2"
+	credDefMarker = "3"
+	revRegMarker  = "4"
+
+	// SignatureCL is the only signature type of cred defs.
+	SignatureCL = "CL"
+	// RevocationCLAccum is the only revocation type of rev regs.
+	RevocationCLAccum = "CL_ACCUM"
+
+	didPrefix        = "did:"
+	indyObjectPrefix = "/anoncreds/v0/"
+	indySchema       = "SCHEMA"
+	indyCredDef      = "CLAIM_DEF"
+	indyRevRegDef    = "REV_REG_DEF"
+	sovSchemaPrefix  = "schema:sov:"
+	sovCredDefPrefix = "creddef:sov:"
+	sovRevRegPrefix  = "revreg:sov:"
+)
+
+// ErrInvalid is wrapped by all of the parse and validate errors.
+var ErrInvalid = errors.New("invalid identifier")
+
+func invalid(kind, id, format string, a ...any) error {
+	return fmt.Errorf("%w: %s %q: %s", ErrInvalid, kind, id, fmt.Sprintf(format, a...))
+}
+
+// DID is an Indy DID in any of the forms: 5Lgx4KLRTNgexDqT7WDALu,
+// did:sov:5Lgx4KLRTNgexDqT7WDALu or did:indy:sovrin:5Lgx4KLRTNgexDqT7WDALu.
+type DID struct {
+	Method Method
+
+	// Namespace is the did:indy namespace, e.g. sovrin or sovrin:staging.
+	Namespace string
+
+	// ID is the unqualified DID, which is the base58 of 16 or 32 bytes.
+	ID string
+}
+
+// ParseDID parses the DID in any of the forms and validates it.
+func ParseDID(s string) (d DID, err error) {
+	switch {
+	case strings.HasPrefix(s, "did:sov:"):
+		d = DID{Method: MethodSov, ID: strings.TrimPrefix(s, "did:sov:")}
+	case strings.HasPrefix(s, "did:indy:"):
+		rest := strings.TrimPrefix(s, "did:indy:")
+		i := strings.LastIndex(rest, ":")
+		if i < 0 {
+			return d, invalid("DID", s, "namespace is missing")
+		}
+		d = DID{Method: MethodIndy, Namespace: rest[:i], ID: rest[i+1:]}
+	case strings.HasPrefix(s, didPrefix):
+		return d, invalid("DID", s, "unsupported method")
+	default:
+		d = DID{ID: s}
+	}
+	if err = d.Validate(); err != nil {
+		return DID{}, err
+	}
+	return d, nil
+}
+
+// Validate checks that the ID is base58 of 16 or 32 bytes, and that did:indy
+// has a namespace.
+func (d DID) Validate() error {
+	data, err := base58.Decode(d.ID)
+	if err != nil {
+		return invalid("DID", d.ID, "%v", err)
+	}
+	if len(data) != 16 && len(data) != 32 {
+		return invalid("DID", d.ID, "length %d isn't 16 or 32 bytes", len(data))
+	}
+	switch d.Method {
+	case Unqualified, MethodSov:
+	case MethodIndy:
+		if d.Namespace == "" || strings.Trim(d.Namespace, ":") != d.Namespace {
+			return invalid("DID", d.ID, "invalid namespace %q", d.Namespace)
+		}
+	default:
+		return invalid("DID", d.ID, "unsupported method %q", d.Method)
+	}
+	return nil
+}
+
+// String returns the DID in the form of its method.
+func (d DID) String() string {
+	switch d.Method {
+	case MethodSov:
+		return "did:sov:" + d.ID
+	case MethodIndy:
+		return "did:indy:" + d.Namespace + ":" + d.ID
+	}
+	return d.ID
+}
+
+// Unqualify returns the unqualified DID.
+func (d DID) Unqualify() DID { return DID{ID: d.ID} }
+
+// Sov returns the did:sov DID.
+func (d DID) Sov() DID { return DID{Method: MethodSov, ID: d.ID} }
+
+// Indy returns the did:indy DID in the namespace.
+func (d DID) Indy(namespace string) DID {
+	return DID{Method: MethodIndy, Namespace: namespace, ID: d.ID}
+}
+
+// SchemaID is a schema identifier: <did>:2:<name>:<version>.
+type SchemaID struct {
+	DID     DID
+	Name    string
+	Version string
+}
+
+// NewSchemaID builds the schema ID. Use DID methods to qualify it.
+func NewSchemaID(did DID, name, version string) SchemaID {
+	return SchemaID{DID: did, Name: name, Version: version}
+}
+
+// ParseSchemaID parses the schema ID in any of the forms and validates it.
+func ParseSchemaID(s string) (id SchemaID, err error) {
+	if did, parts, ok := splitIndy(s, indySchema); ok {
+		if len(parts) != 2 {
+			return id, invalid("schema ID", s, "name and version expected")
+		}
+		id = SchemaID{DID: did, Name: parts[0], Version: parts[1]}
+	} else {
+		did, parts, err := splitLegacy(s, sovSchemaPrefix, schemaMarker)
+		if err != nil {
+			return id, invalid("schema ID", s, "%v", err)
+		}
+		if len(parts) != 2 {
+			return id, invalid("schema ID", s, "name and version expected")
+		}
+		id = SchemaID{DID: did, Name: parts[0], Version: parts[1]}
+	}
+	if err = id.Validate(); err != nil {
+		return SchemaID{}, err
+	}
+	return id, nil
+}
+
+// Validate checks the DID and that the name and the version are set.
+func (id SchemaID) Validate() error {
+	if err := id.DID.Validate(); err != nil {
+		return err
+	}
+	if id.Name == "" || id.Version == "" {
+		return invalid("schema ID", id.String(), "name and version are mandatory")
+	}
+	return nil
+}
+
+// String returns the schema ID in the form of its DID.
+func (id SchemaID) String() string {
+	switch id.DID.Method {
+	case MethodIndy:
+		return id.DID.String() + indyObjectPrefix + indySchema + "/" + id.Name + "/" + id.Version
+	case MethodSov:
+		return sovSchemaPrefix + join(id.DID.String(), schemaMarker, id.Name, id.Version)
+	}
+	return join(id.DID.ID, schemaMarker, id.Name, id.Version)
+}
+
+// Unqualify returns the unqualified schema ID.
+func (id SchemaID) Unqualify() SchemaID { id.DID = id.DID.Unqualify(); return id }
+
+// Sov returns the indy-sdk qualified schema ID.
+func (id SchemaID) Sov() SchemaID { id.DID = id.DID.Sov(); return id }
+
+// Indy returns the did:indy schema ID in the namespace.
+func (id SchemaID) Indy(namespace string) SchemaID {
+	id.DID = id.DID.Indy(namespace)
+	return id
+}
+
+// CredDefID is a cred def identifier: <did>:3:CL:<schema ref>:<tag>.
+type CredDefID struct {
+	DID DID
+
+	// SchemaRef is the sequence number of the schema in the ledger. Very old
+	// cred defs have the schema ID here.
+	SchemaRef string
+
+	Tag string
+}
+
+// NewCredDefID builds the cred def ID. Use DID methods to qualify it.
+func NewCredDefID(did DID, schemaSeqNo int, tag string) CredDefID {
+	return CredDefID{DID: did, SchemaRef: strconv.Itoa(schemaSeqNo), Tag: tag}
+}
+
+// ParseCredDefID parses the cred def ID in any of the forms and validates
+// it.
+func ParseCredDefID(s string) (id CredDefID, err error) {
+	if did, parts, ok := splitIndy(s, indyCredDef); ok {
+		if len(parts) != 2 {
+			return id, invalid("cred def ID", s, "schema seq no and tag expected")
+		}
+		id = CredDefID{DID: did, SchemaRef: parts[0], Tag: parts[1]}
+	} else {
+		did, parts, err := splitLegacy(s, sovCredDefPrefix, credDefMarker)
+		if err != nil {
+			return id, invalid("cred def ID", s, "%v", err)
+		}
+		if len(parts) < 3 || parts[0] != SignatureCL {
+			return id, invalid("cred def ID", s, "CL:<schema ref>:<tag> expected")
+		}
+		id = CredDefID{
+			DID:       did,
+			SchemaRef: strings.Join(parts[1:len(parts)-1], ":"),
+			Tag:       parts[len(parts)-1],
+		}
+	}
+	if err = id.Validate(); err != nil {
+		return CredDefID{}, err
+	}
+	return id, nil
+}
+
+// Validate checks the DID and that the schema ref and the tag are set.
+// did:indy cred defs must refer to the schema by the sequence number.
+func (id CredDefID) Validate() error {
+	if err := id.DID.Validate(); err != nil {
+		return err
+	}
+	if id.SchemaRef == "" || id.Tag == "" {
+		return invalid("cred def ID", id.String(), "schema ref and tag are mandatory")
+	}
+	if _, err := id.SchemaSeqNo(); err != nil && id.DID.Method == MethodIndy {
+		return invalid("cred def ID", id.String(), "schema seq no must be a number")
+	}
+	return nil
+}
+
+// SchemaSeqNo returns SchemaRef as a number.
+func (id CredDefID) SchemaSeqNo() (int, error) {
+	return strconv.Atoi(id.SchemaRef)
+}
+
+// String returns the cred def ID in the form of its DID.
+func (id CredDefID) String() string {
+	switch id.DID.Method {
+	case MethodIndy:
+		return id.DID.String() + indyObjectPrefix + indyCredDef + "/" + id.SchemaRef + "/" + id.Tag
+	case MethodSov:
+		return sovCredDefPrefix + join(id.DID.String(), credDefMarker, SignatureCL, id.SchemaRef, id.Tag)
+	}
+	return join(id.DID.ID, credDefMarker, SignatureCL, id.SchemaRef, id.Tag)
+}
+
+// Unqualify returns the unqualified cred def ID.
+func (id CredDefID) Unqualify() CredDefID { id.DID = id.DID.Unqualify(); return id }
+
+// Sov returns the indy-sdk qualified cred def ID.
+func (id CredDefID) Sov() CredDefID { id.DID = id.DID.Sov(); return id }
+
+// Indy returns the did:indy cred def ID in the namespace.
+func (id CredDefID) Indy(namespace string) CredDefID {
+	id.DID = id.DID.Indy(namespace)
+	return id
+}
+
+// RevRegID is a revocation registry identifier:
+// <did>:4:<cred def ID>:CL_ACCUM:<tag>.
+type RevRegID struct {
+	CredDef CredDefID
+	Tag     string
+}
+
+// NewRevRegID builds the rev reg ID of the cred def.
+func NewRevRegID(credDef CredDefID, tag string) RevRegID {
+	return RevRegID{CredDef: credDef, Tag: tag}
+}
+
+// ParseRevRegID parses the rev reg ID in any of the forms and validates it.
+func ParseRevRegID(s string) (id RevRegID, err error) {
+	if did, parts, ok := splitIndy(s, indyRevRegDef); ok {
+		if len(parts) != 3 {
+			return id, invalid("rev reg ID", s, "schema seq no, cred def tag and tag expected")
+		}
+		id = RevRegID{
+			CredDef: CredDefID{DID: did, SchemaRef: parts[0], Tag: parts[1]},
+			Tag:     parts[2],
+		}
+	} else {
+		did, parts, err := splitLegacy(s, sovRevRegPrefix, revRegMarker)
+		if err != nil {
+			return id, invalid("rev reg ID", s, "%v", err)
+		}
+		if len(parts) < 3 || parts[len(parts)-2] != RevocationCLAccum {
+			return id, invalid("rev reg ID", s, "<cred def ID>:CL_ACCUM:<tag> expected")
+		}
+		credDef, err := ParseCredDefID(strings.Join(parts[:len(parts)-2], ":"))
+		if err != nil {
+			return id, err
+		}
+		if credDef.DID.ID != did.ID {
+			return id, invalid("rev reg ID", s, "cred def is from other DID")
+		}
+		credDef.DID = did
+		id = RevRegID{CredDef: credDef, Tag: parts[len(parts)-1]}
+	}
+	if err = id.Validate(); err != nil {
+		return RevRegID{}, err
+	}
+	return id, nil
+}
+
+// Validate checks the cred def and that the tag is set.
+func (id RevRegID) Validate() error {
+	if err := id.CredDef.Validate(); err != nil {
+		return err
+	}
+	if id.Tag == "" {
+		return invalid("rev reg ID", id.String(), "tag is mandatory")
+	}
+	return nil
+}
+
+// String returns the rev reg ID in the form of its cred def's DID.
+func (id RevRegID) String() string {
+	did := id.CredDef.DID
+	switch did.Method {
+	case MethodIndy:
+		return did.String() + indyObjectPrefix + indyRevRegDef + "/" +
+			id.CredDef.SchemaRef + "/" + id.CredDef.Tag + "/" + id.Tag
+	case MethodSov:
+		return sovRevRegPrefix + join(did.String(), revRegMarker, id.CredDef.String(),
+			RevocationCLAccum, id.Tag)
+	}
+	return join(did.ID, revRegMarker, id.CredDef.String(), RevocationCLAccum, id.Tag)
+}
+
+// Unqualify returns the unqualified rev reg ID.
+func (id RevRegID) Unqualify() RevRegID { id.CredDef = id.CredDef.Unqualify(); return id }
+
+// Sov returns the indy-sdk qualified rev reg ID.
+func (id RevRegID) Sov() RevRegID { id.CredDef = id.CredDef.Sov(); return id }
+
+// Indy returns the did:indy rev reg ID in the namespace.
+func (id RevRegID) Indy(namespace string) RevRegID {
+	id.CredDef = id.CredDef.Indy(namespace)
+	return id
+}
+
+// Unqualify returns the unqualified form of the DID, schema ID, cred def ID
+// or rev reg ID. Other strings are returned as they are. libindy and the
+// ledger plugins use the unqualified identifiers.
+func Unqualify(s string) string {
+	if id, err := ParseSchemaID(s); err == nil {
+		return id.Unqualify().String()
+	}
+	if id, err := ParseCredDefID(s); err == nil {
+		return id.Unqualify().String()
+	}
+	if id, err := ParseRevRegID(s); err == nil {
+		return id.Unqualify().String()
+	}
+	if did, err := ParseDID(s); err == nil {
+		return did.Unqualify().String()
+	}
+	return s
+}
+
+// splitIndy splits the did:indy object identifier of the type to the DID and
+// the parts after the type.
+func splitIndy(s, objectType string) (did DID, parts []string, ok bool) {
+	if !strings.HasPrefix(s, "did:indy:") {
+		return did, nil, false
+	}
+	didStr, path, found := strings.Cut(s, indyObjectPrefix)
+	if !found {
+		return did, nil, false
+	}
+	parts = strings.Split(path, "/")
+	if parts[0] != objectType {
+		return did, nil, false
+	}
+	did, err := ParseDID(didStr)
+	if err != nil {
+		return did, nil, false
+	}
+	return did, parts[1:], true
+}
+
+// splitLegacy splits the unqualified or the indy-sdk qualified identifier to
+// the DID and the parts after the marker.
+func splitLegacy(s, sovPrefix, marker string) (did DID, parts []string, err error) {
+	rest := s
+	method := Unqualified
+	if strings.HasPrefix(s, sovPrefix) {
+		rest = strings.TrimPrefix(s, sovPrefix+"did:sov:")
+		if rest == s[len(sovPrefix):] {
+			return did, nil, errors.New("did:sov expected")
+		}
+		method = MethodSov
+	}
+	parts = strings.Split(rest, ":")
+	if len(parts) < 2 || parts[1] != marker {
+		return did, nil, fmt.Errorf("marker %s expected", marker)
+	}
+	return DID{Method: method, ID: parts[0]}, parts[2:], nil
+}
+
+func join(parts ...string) string {
+	return strings.Join(parts, ":")
+}
@@ -0,0 +1,153 @@
+package identifiers
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/lainio/err2/assert"
+)
+
+const (
+	testDID     = "5Lgx4KLRTNgexDqT7WDALu"
+	testSchema  = testDID + ":2:Education:1.0"
+	testCredDef = testDID + ":3:CL:14:default"
+	testRevReg  = testDID + ":4:" + testCredDef + ":CL_ACCUM:tag1"
+)
+
+func TestParseDID(t *testing.T) {
+	defer assert.PushTester(t)()
+
+	for s, want := range map[string]DID{
+		testDID:                                        {ID: testDID},
+		"did:sov:" + testDID:                           {Method: MethodSov, ID: testDID},
+		"did:indy:sovrin:" + testDID:                   {Method: MethodIndy, Namespace: "sovrin", ID: testDID},
+		"did:indy:sovrin:staging:" + testDID:           {Method: MethodIndy, Namespace: "sovrin:staging", ID: testDID},
+		"CnEDk9HrMnmiHXEV1WFgbVCRteYnPqsJwrTdcZaNhFVW": {ID: "CnEDk9HrMnmiHXEV1WFgbVCRteYnPqsJwrTdcZaNhFVW"},
+	} {
+		did, err := ParseDID(s)
+		assert.NoError(err, s)
+		assert.Equal(did, want)
+		assert.Equal(did.String(), s)
+		assert.Equal(did.Unqualify().String(), want.ID)
+	}
+
+	for _, s := range []string{
+		"", "did:key:" + testDID, "did:indy:" + testDID, "5Lgx4KLRTNgexDqT7WDAL0",
+		"5Lgx4KLRTNgexDqT7W", "did:indy::" + testDID,
+	} {
+		_, err := ParseDID(s)
+		assert.Error(err, s)
+		assert.That(errors.Is(err, ErrInvalid))
+	}
+}
+
+func TestParseSchemaID(t *testing.T) {
+	defer assert.PushTester(t)()
+
+	forms := []string{
+		testSchema,
+		"schema:sov:did:sov:" + testSchema,
+		"did:indy:sovrin:" + testDID + "/anoncreds/v0/SCHEMA/Education/1.0",
+	}
+	for _, s := range forms {
+		id, err := ParseSchemaID(s)
+		assert.NoError(err, s)
+		assert.Equal(id.String(), s)
+		assert.Equal(id.Name, "Education")
+		assert.Equal(id.Version, "1.0")
+		assert.Equal(id.Unqualify().String(), testSchema)
+		assert.Equal(id.Sov().String(), forms[1])
+		assert.Equal(id.Indy("sovrin").String(), forms[2])
+		assert.Equal(Unqualify(s), testSchema)
+	}
+	assert.Equal(NewSchemaID(DID{ID: testDID}, "Education", "1.0").String(), testSchema)
+
+	for _, s := range []string{
+		testDID + ":2:Education",
+		testDID + ":3:Education:1.0",
+		"schema:sov:" + testSchema,
+		"did:indy:sovrin:" + testDID + "/anoncreds/v0/SCHEMA/Education",
+		testDID + ":2::1.0",
+	} {
+		_, err := ParseSchemaID(s)
+		assert.Error(err, s)
+	}
+}
+
+func TestParseCredDefID(t *testing.T) {
+	defer assert.PushTester(t)()
+
+	forms := []string{
+		testCredDef,
+		"creddef:sov:did:sov:" + testCredDef,
+		"did:indy:sovrin:" + testDID + "/anoncreds/v0/CLAIM_DEF/14/default",
+	}
+	for _, s := range forms {
+		id, err := ParseCredDefID(s)
+		assert.NoError(err, s)
+		assert.Equal(id.String(), s)
+		seqNo, err := id.SchemaSeqNo()
+		assert.NoError(err)
+		assert.Equal(seqNo, 14)
+		assert.Equal(id.Tag, "default")
+		assert.Equal(id.Unqualify().String(), testCredDef)
+		assert.Equal(id.Sov().String(), forms[1])
+		assert.Equal(id.Indy("sovrin").String(), forms[2])
+		assert.Equal(Unqualify(s), testCredDef)
+	}
+	assert.Equal(NewCredDefID(DID{ID: testDID}, 14, "default").String(), testCredDef)
+
+	old, err := ParseCredDefID(testDID + ":3:CL:" + testSchema + ":default")
+	assert.NoError(err)
+	assert.Equal(old.SchemaRef, testSchema)
+	_, err = old.SchemaSeqNo()
+	assert.Error(err)
+	assert.Error(old.Indy("sovrin").Validate())
+
+	for _, s := range []string{
+		testDID + ":3:CL:14",
+		testDID + ":3:BLS:14:default",
+		testDID + ":2:CL:14:default",
+		"did:indy:sovrin:" + testDID + "/anoncreds/v0/CLAIM_DEF/abc/default",
+	} {
+		_, err := ParseCredDefID(s)
+		assert.Error(err, s)
+	}
+}
+
+func TestParseRevRegID(t *testing.T) {
+	defer assert.PushTester(t)()
+
+	forms := []string{
+		testRevReg,
+		"revreg:sov:did:sov:" + testDID + ":4:creddef:sov:did:sov:" + testCredDef + ":CL_ACCUM:tag1",
+		"did:indy:sovrin:" + testDID + "/anoncreds/v0/REV_REG_DEF/14/default/tag1",
+	}
+	for _, s := range forms {
+		id, err := ParseRevRegID(s)
+		assert.NoError(err, s)
+		assert.Equal(id.String(), s)
+		assert.Equal(id.Tag, "tag1")
+		assert.Equal(id.CredDef.Unqualify().String(), testCredDef)
+		assert.Equal(id.Unqualify().String(), testRevReg)
+		assert.Equal(id.Sov().String(), forms[1])
+		assert.Equal(id.Indy("sovrin").String(), forms[2])
+		assert.Equal(Unqualify(s), testRevReg)
+	}
+
+	for _, s := range []string{
+		testDID + ":4:" + testCredDef + ":CL_ACCUM",
+		testDID + ":4:" + testCredDef + ":OTHER:tag1",
+		"WgWxqztrNooG92RXvxSTWv:4:" + testCredDef + ":CL_ACCUM:tag1",
+	} {
+		_, err := ParseRevRegID(s)
+		assert.Error(err, s)
+	}
+}
+
+func TestUnqualify(t *testing.T) {
+	defer assert.PushTester(t)()
+
+	assert.Equal(Unqualify("did:sov:"+testDID), testDID)
+	assert.Equal(Unqualify("not an identifier"), "not an identifier")
+}
@@ -60,15 +60,16 @@ func (i *immu) buildCtx(context context.Context) context.Context {
 }
 
 func (i *immu) Write(tx plugin.TxInfo, ID, data string) (err error) {
+	key, value := plugin.MapEntry(tx, ID, data)
 	defer err2.Handle(&err, func(err error) error {
 		glog.Errorln("write error:", err)
 		err = nil // suspend error for now and retry
-		go i.writeRetry(ID, data)
+		go i.writeRetry(key, value)
 		return err
 	})
 
-	_ = i.cache.Write(tx, ID, data)
-	try.To(i.oneWrite(ID, data))
+	_ = i.cache.Write(tx, key, value)
+	try.To(i.oneWrite(key, value))
 	return nil
 }
 
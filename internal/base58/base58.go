@@ -0,0 +1,90 @@
+// Package base58 implements the Bitcoin base58 encoding used in Indy DIDs and
+// verkeys.
+package base58
+
+import (
+	"errors"
+	"fmt"
+)
+
+const alphabet = "123456789ABCDEFGHJKLMNPQRSTUVWXYZabcdefghijkmnopqrstuvwxyz"
+
+var decodeMap = func() (m [256]int) {
+	for i := range m {
+		m[i] = -1
+	}
+	for i := 0; i < len(alphabet); i++ {
+		m[alphabet[i]] = i
+	}
+	return m
+}()
+
+// ErrEmpty is returned when an empty string is decoded.
+var ErrEmpty = errors.New("base58: empty string")
+
+// Encode encodes the bytes to the base58 string.
+func Encode(data []byte) string {
+	zeros := 0
+	for zeros < len(data) && data[zeros] == 0 {
+		zeros++
+	}
+	// log(256)/log(58) is about 1.37
+	buf := make([]byte, (len(data)-zeros)*138/100+1)
+	high := len(buf) - 1
+	for _, b := range data[zeros:] {
+		carry := int(b)
+		i := len(buf) - 1
+		for ; i > high || carry != 0; i-- {
+			carry += 256 * int(buf[i])
+			buf[i] = byte(carry % 58)
+			carry /= 58
+		}
+		high = i
+	}
+	start := 0
+	for start < len(buf) && buf[start] == 0 {
+		start++
+	}
+	out := make([]byte, zeros+len(buf)-start)
+	for i := 0; i < zeros; i++ {
+		out[i] = alphabet[0]
+	}
+	for i, b := range buf[start:] {
+		out[zeros+i] = alphabet[b]
+	}
+	return string(out)
+}
+
+// Decode decodes the base58 string.
+func Decode(s string) ([]byte, error) {
+	if s == "" {
+		return nil, ErrEmpty
+	}
+	zeros := 0
+	for zeros < len(s) && s[zeros] == alphabet[0] {
+		zeros++
+	}
+	// log(58)/log(256) is about 0.733
+	buf := make([]byte, (len(s)-zeros)*733/1000+1)
+	high := len(buf) - 1
+	for i := zeros; i < len(s); i++ {
+		carry := decodeMap[s[i]]
+		if carry < 0 {
+			return nil, fmt.Errorf("base58: invalid character %q at %d", s[i], i)
+		}
+		j := len(buf) - 1
+		for ; j > high || carry != 0; j-- {
+			carry += 58 * int(buf[j])
+			buf[j] = byte(carry % 256)
+			carry /= 256
+		}
+		high = j
+	}
+	start := 0
+	for start < len(buf) && buf[start] == 0 {
+		start++
+	}
+	out := make([]byte, zeros+len(buf)-start)
+	copy(out[zeros:], buf[start:])
+	return out, nil
+}
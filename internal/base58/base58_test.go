@@ -0,0 +1,49 @@
+package base58
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/hex"
+	"testing"
+
+	"github.com/lainio/err2/assert"
+)
+
+func TestEncodeDecode(t *testing.T) {
+	defer assert.PushTester(t)()
+
+	// vectors from the base58 draft (draft-msporny-base58)
+	vectors := []struct{ hex, b58 string }{
+		{"", ""},
+		{"48656c6c6f20576f726c6421", "2NEpo7TZRRrLZSi2U"},
+		{"54686520717569636b2062726f776e20666f78206a756d7073206f76657220746865206c617a7920646f672e",
+			"USm3fpXnKG5EUBx2ndxBDMPVciP5hGey2Jh4NDv6gmeo1LkMeiKrLJUUBk6Z"},
+		{"0000287fb4cd", "11233QC4"},
+		{"00", "1"},
+	}
+	for _, v := range vectors {
+		data, _ := hex.DecodeString(v.hex)
+		assert.Equal(Encode(data), v.b58)
+		if v.b58 == "" {
+			continue
+		}
+		decoded, err := Decode(v.b58)
+		assert.NoError(err)
+		assert.That(bytes.Equal(decoded, data))
+	}
+
+	for i := 0; i < 100; i++ {
+		data := make([]byte, i)
+		_, _ = rand.Read(data)
+		decoded, err := Decode(Encode(data))
+		if i == 0 {
+			assert.Error(err)
+			continue
+		}
+		assert.NoError(err)
+		assert.That(bytes.Equal(decoded, data))
+	}
+
+	_, err := Decode("0OIl")
+	assert.Error(err)
+}
@@ -47,6 +47,27 @@ func FindyIssuerCreateAndStoreCredentialDef(wallet int, did, schema, tag, sigTyp
 	return ch
 }
 
+func FindyIssuerRotateCredentialDefStart(wallet int, credDefID, config string) ctx.Channel {
+	credDefIDInC := C.CString(credDefID)
+	defer C.free(unsafe.Pointer(credDefIDInC))
+	var configInC *C.char = C.findy_null_string
+	if config != findy.NullString {
+		configInC = C.CString(config)
+		defer C.free(unsafe.Pointer(configInC))
+	}
+	cmdHandle, ch := ctx.CmdContext.NamedPush("FindyIssuerRotateCredentialDefStart")
+	C.findy_issuer_rotate_credential_def_start(C.int(cmdHandle), C.int(wallet), credDefIDInC, configInC)
+	return ch
+}
+
+func FindyIssuerRotateCredentialDefApply(wallet int, credDefID string) ctx.Channel {
+	credDefIDInC := C.CString(credDefID)
+	defer C.free(unsafe.Pointer(credDefIDInC))
+	cmdHandle, ch := ctx.CmdContext.NamedPush("FindyIssuerRotateCredentialDefApply")
+	C.findy_issuer_rotate_credential_def_apply(C.int(cmdHandle), C.int(wallet), credDefIDInC)
+	return ch
+}
+
 func FindyIssuerCreateCredentialOffer(wallet int, credDefID string) ctx.Channel {
 	credDefIDInC := C.CString(credDefID)
 	defer C.free(unsafe.Pointer(credDefIDInC))
@@ -139,6 +160,29 @@ func FindyProverStoreCredential(wallet int, credID, credReqMeta, credentials, cr
 	return ch
 }
 
+func FindyProverSearchCredentials(wallet int, queryJSON string) ctx.Channel {
+	var queryJSONInC *C.char = C.findy_null_string
+	if queryJSON != findy.NullString {
+		queryJSONInC = C.CString(queryJSON)
+		defer C.free(unsafe.Pointer(queryJSONInC))
+	}
+	cmdHandle, ch := ctx.CmdContext.NamedPush("FindyProverSearchCredentials")
+	C.findy_prover_search_credentials(C.int(cmdHandle), C.int(wallet), queryJSONInC)
+	return ch
+}
+
+func FindyProverFetchCredentials(searchHandle int, count int) ctx.Channel {
+	cmdHandle, ch := ctx.CmdContext.NamedPush("FindyProverFetchCredentials")
+	C.findy_prover_fetch_credentials(C.int(cmdHandle), C.int(searchHandle), C.uint(count))
+	return ch
+}
+
+func FindyProverCloseCredentialsSearch(searchHandle int) ctx.Channel {
+	cmdHandle, ch := ctx.CmdContext.NamedPush("FindyProverCloseCredentialsSearch")
+	C.findy_prover_close_credentials_search(C.int(cmdHandle), C.int(searchHandle))
+	return ch
+}
+
 func FindyProverSearchCredentialsForProofReq(wallet int, proofReqJSON, extraQueryJSON string) ctx.Channel {
 	proofReqJSONInC := C.CString(proofReqJSON)
 	defer C.free(unsafe.Pointer(proofReqJSONInC))
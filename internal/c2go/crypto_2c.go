@@ -11,6 +11,35 @@ import (
 	"github.com/findy-network/findy-wrapper-go/internal/ctx"
 )
 
+// findy_create_key
+func FindyCreateKey(wallet int, keyJSON string) ctx.Channel {
+	keyJSONInC := C.CString(keyJSON)
+	defer C.free(unsafe.Pointer(keyJSONInC))
+	cmdHandle, ch := ctx.CmdContext.NamedPush("FindyCreateKey")
+	C.findy_create_key(C.int(cmdHandle), C.int(wallet), keyJSONInC)
+	return ch
+}
+
+// findy_set_key_metadata
+func FindySetKeyMetadata(wallet int, verKey, meta string) ctx.Channel {
+	verKeyInC := C.CString(verKey)
+	defer C.free(unsafe.Pointer(verKeyInC))
+	metaInC := C.CString(meta)
+	defer C.free(unsafe.Pointer(metaInC))
+	cmdHandle, ch := ctx.CmdContext.NamedPush("FindySetKeyMetadata")
+	C.findy_set_key_metadata(C.int(cmdHandle), C.int(wallet), verKeyInC, metaInC)
+	return ch
+}
+
+// findy_get_key_metadata
+func FindyGetKeyMetadata(wallet int, verKey string) ctx.Channel {
+	verKeyInC := C.CString(verKey)
+	defer C.free(unsafe.Pointer(verKeyInC))
+	cmdHandle, ch := ctx.CmdContext.NamedPush("FindyGetKeyMetadata")
+	C.findy_get_key_metadata(C.int(cmdHandle), C.int(wallet), verKeyInC)
+	return ch
+}
+
 // findy_crypto_sign
 func FindyCryptoSign(wallet int, signerVerKey string, msg []byte) ctx.Channel {
 	signerVerKeyInC := C.CString(signerVerKey)
@@ -32,6 +32,24 @@ func KeyForDid(pool, wallet int, did string) ctx.Channel {
 	return ch
 }
 
+func FindyReplaceKeysStart(wallet int, did, identityJSON string) ctx.Channel {
+	didInC := C.CString(did)
+	defer C.free(unsafe.Pointer(didInC))
+	identityJSONInC := C.CString(identityJSON)
+	defer C.free(unsafe.Pointer(identityJSONInC))
+	cmdHandle, ch := ctx.CmdContext.NamedPush("FindyReplaceKeysStart: " + did)
+	C.findy_replace_keys_start(C.int(cmdHandle), C.int(wallet), didInC, identityJSONInC)
+	return ch
+}
+
+func FindyReplaceKeysApply(wallet int, did string) ctx.Channel {
+	didInC := C.CString(did)
+	defer C.free(unsafe.Pointer(didInC))
+	cmdHandle, ch := ctx.CmdContext.NamedPush("FindyReplaceKeysApply: " + did)
+	C.findy_replace_keys_apply(C.int(cmdHandle), C.int(wallet), didInC)
+	return ch
+}
+
 func FindyStoreTheirDid(wallet int, idJSON string) ctx.Channel {
 	idJSONInC := C.CString(idJSON)
 	defer C.free(unsafe.Pointer(idJSONInC))
@@ -93,3 +111,13 @@ func FindyGetEndpointForDid(wallet, pool int, did string) ctx.Channel {
 	C.findy_get_endpoint_for_did(C.int(cmdHandle), C.int(wallet), C.int(pool), didInC)
 	return ch
 }
+
+func FindyAbbreviateVerkey(did, fullVerKey string) ctx.Channel {
+	didInC := C.CString(did)
+	defer C.free(unsafe.Pointer(didInC))
+	fullVerKeyInC := C.CString(fullVerKey)
+	defer C.free(unsafe.Pointer(fullVerKeyInC))
+	cmdHandle, ch := ctx.CmdContext.NamedPush("AbbreviateVerkey: " + did)
+	C.findy_abbreviate_verkey(C.int(cmdHandle), didInC, fullVerKeyInC)
+	return ch
+}
@@ -28,6 +28,22 @@ indy_error_t findy_issuer_create_and_store_credential_def(indy_handle_t command_
 	return err;
 }
 
+indy_error_t findy_issuer_rotate_credential_def_start(indy_handle_t command_handle, indy_handle_t wallet_handle, char *cred_def_id, char *config_json ) {
+	indy_error_t err = indy_issuer_rotate_credential_def_start(command_handle, wallet_handle, cred_def_id, config_json, CALLBACK_FUNCTION_HERE );
+	if (err != Success) {
+		handler(command_handle, err);
+	}
+	return err;
+}
+
+indy_error_t findy_issuer_rotate_credential_def_apply(indy_handle_t command_handle, indy_handle_t wallet_handle, char *cred_def_id ) {
+	indy_error_t err = indy_issuer_rotate_credential_def_apply(command_handle, wallet_handle, cred_def_id, (indy_handler)handler );
+	if (err != Success) {
+		handler(command_handle, err);
+	}
+	return err;
+}
+
 indy_error_t findy_issuer_create_and_store_revoc_reg(indy_handle_t command_handle, indy_handle_t wallet_handle, char *issuer_did, char *revoc_def_type, char *tag, char *cred_def_id, char *config_json, indy_handle_t tails_writer_handle ) {
 	indy_error_t err = indy_issuer_create_and_store_revoc_reg(command_handle, wallet_handle, issuer_did, revoc_def_type, tag, cred_def_id, config_json, tails_writer_handle, (indy_handler_str_str_str)strStrStrHandler );
 	if (err != Success) {
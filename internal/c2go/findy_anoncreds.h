@@ -1,5 +1,7 @@
 extern indy_error_t findy_issuer_create_schema(indy_handle_t command_handle, char *issuer_did, char *name, char *version, char *attr_names);
 extern indy_error_t findy_issuer_create_and_store_credential_def(indy_handle_t command_handle, indy_handle_t wallet_handle, char *issuer_did, char *schema_json, char *tag, char *signature_type, char *config_json);
+extern indy_error_t findy_issuer_rotate_credential_def_start(indy_handle_t command_handle, indy_handle_t wallet_handle, char *cred_def_id, char *config_json);
+extern indy_error_t findy_issuer_rotate_credential_def_apply(indy_handle_t command_handle, indy_handle_t wallet_handle, char *cred_def_id);
 extern indy_error_t findy_issuer_create_and_store_revoc_reg(indy_handle_t command_handle, indy_handle_t wallet_handle, char *issuer_did, char *revoc_def_type, char *tag, char *cred_def_id, char *config_json, indy_handle_t tails_writer_handle);
 extern indy_error_t findy_issuer_create_credential_offer(indy_handle_t command_handle, indy_handle_t wallet_handle, char *cred_def_id);
 extern indy_error_t findy_issuer_create_credential(indy_handle_t command_handle, indy_handle_t wallet_handle, char *cred_offer_json, char *cred_req_json, char *cred_values_json, char *rev_reg_id, indy_handle_t blob_storage_reader_handle);
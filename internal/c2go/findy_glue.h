@@ -30,4 +30,6 @@ extern indy_error_t findy_set_protocol_version(indy_handle_t cmd_handle, indy_u6
 #include "findy_anoncreds.h"
 #include "findy_blob_storage.h"
 #include "findy_pairwise.h"
-#include "findy_did.h"
\ No newline at end of file
+#include "findy_did.h"
+#include "findy_non_secrets.h"
+#include "findy_storage.h"
\ No newline at end of file
@@ -0,0 +1,90 @@
+//
+// Autogenerated from indy-sdk main header files (indy_*.h)
+//
+
+#include <stdio.h>
+#include "findy_glue.h"
+#include "findy_callback_types.h"
+
+// cgo generated
+#include "_cgo_export.h"
+
+indy_error_t findy_add_wallet_record(indy_handle_t command_handle, indy_handle_t wallet_handle, char *type_, char *id, char *value, char *tags_json ) {
+	indy_error_t err = indy_add_wallet_record(command_handle, wallet_handle, type_, id, value, tags_json, (indy_handler)handler);
+	if (err != Success) {
+		handler(command_handle, err);
+	}
+	return err;
+}
+
+indy_error_t findy_update_wallet_record_value(indy_handle_t command_handle, indy_handle_t wallet_handle, char *type_, char *id, char *value ) {
+	indy_error_t err = indy_update_wallet_record_value(command_handle, wallet_handle, type_, id, value, (indy_handler)handler);
+	if (err != Success) {
+		handler(command_handle, err);
+	}
+	return err;
+}
+
+indy_error_t findy_update_wallet_record_tags(indy_handle_t command_handle, indy_handle_t wallet_handle, char *type_, char *id, char *tags_json ) {
+	indy_error_t err = indy_update_wallet_record_tags(command_handle, wallet_handle, type_, id, tags_json, (indy_handler)handler);
+	if (err != Success) {
+		handler(command_handle, err);
+	}
+	return err;
+}
+
+indy_error_t findy_add_wallet_record_tags(indy_handle_t command_handle, indy_handle_t wallet_handle, char *type_, char *id, char *tags_json ) {
+	indy_error_t err = indy_add_wallet_record_tags(command_handle, wallet_handle, type_, id, tags_json, (indy_handler)handler);
+	if (err != Success) {
+		handler(command_handle, err);
+	}
+	return err;
+}
+
+indy_error_t findy_delete_wallet_record_tags(indy_handle_t command_handle, indy_handle_t wallet_handle, char *type_, char *id, char *tag_names_json ) {
+	indy_error_t err = indy_delete_wallet_record_tags(command_handle, wallet_handle, type_, id, tag_names_json, (indy_handler)handler);
+	if (err != Success) {
+		handler(command_handle, err);
+	}
+	return err;
+}
+
+indy_error_t findy_delete_wallet_record(indy_handle_t command_handle, indy_handle_t wallet_handle, char *type_, char *id ) {
+	indy_error_t err = indy_delete_wallet_record(command_handle, wallet_handle, type_, id, (indy_handler)handler);
+	if (err != Success) {
+		handler(command_handle, err);
+	}
+	return err;
+}
+
+indy_error_t findy_get_wallet_record(indy_handle_t command_handle, indy_handle_t wallet_handle, char *type_, char *id, char *options_json ) {
+	indy_error_t err = indy_get_wallet_record(command_handle, wallet_handle, type_, id, options_json, (indy_handler_str)strHandler);
+	if (err != Success) {
+		handler(command_handle, err);
+	}
+	return err;
+}
+
+indy_error_t findy_open_wallet_search(indy_handle_t command_handle, indy_handle_t wallet_handle, char *type_, char *query_json, char *options_json ) {
+	indy_error_t err = indy_open_wallet_search(command_handle, wallet_handle, type_, query_json, options_json, (indy_handler_handle)handleHandler);
+	if (err != Success) {
+		handler(command_handle, err);
+	}
+	return err;
+}
+
+indy_error_t findy_fetch_wallet_search_next_records(indy_handle_t command_handle, indy_handle_t wallet_handle, indy_handle_t wallet_search_handle, indy_u32_t count ) {
+	indy_error_t err = indy_fetch_wallet_search_next_records(command_handle, wallet_handle, wallet_search_handle, count, (indy_handler_str)strHandler);
+	if (err != Success) {
+		handler(command_handle, err);
+	}
+	return err;
+}
+
+indy_error_t findy_close_wallet_search(indy_handle_t command_handle, indy_handle_t wallet_search_handle ) {
+	indy_error_t err = indy_close_wallet_search(command_handle, wallet_search_handle, (indy_handler)handler);
+	if (err != Success) {
+		handler(command_handle, err);
+	}
+	return err;
+}
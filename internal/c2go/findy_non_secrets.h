@@ -0,0 +1,10 @@
+extern indy_error_t findy_add_wallet_record(indy_handle_t command_handle, indy_handle_t wallet_handle, char *type_, char *id, char *value, char *tags_json);
+extern indy_error_t findy_update_wallet_record_value(indy_handle_t command_handle, indy_handle_t wallet_handle, char *type_, char *id, char *value);
+extern indy_error_t findy_update_wallet_record_tags(indy_handle_t command_handle, indy_handle_t wallet_handle, char *type_, char *id, char *tags_json);
+extern indy_error_t findy_add_wallet_record_tags(indy_handle_t command_handle, indy_handle_t wallet_handle, char *type_, char *id, char *tags_json);
+extern indy_error_t findy_delete_wallet_record_tags(indy_handle_t command_handle, indy_handle_t wallet_handle, char *type_, char *id, char *tag_names_json);
+extern indy_error_t findy_delete_wallet_record(indy_handle_t command_handle, indy_handle_t wallet_handle, char *type_, char *id);
+extern indy_error_t findy_get_wallet_record(indy_handle_t command_handle, indy_handle_t wallet_handle, char *type_, char *id, char *options_json);
+extern indy_error_t findy_open_wallet_search(indy_handle_t command_handle, indy_handle_t wallet_handle, char *type_, char *query_json, char *options_json);
+extern indy_error_t findy_fetch_wallet_search_next_records(indy_handle_t command_handle, indy_handle_t wallet_handle, indy_handle_t wallet_search_handle, indy_u32_t count);
+extern indy_error_t findy_close_wallet_search(indy_handle_t command_handle, indy_handle_t wallet_search_handle);
@@ -0,0 +1,151 @@
+//
+// Wallet storage callbacks of indy_register_wallet_storage (indy_wallet.h)
+// which delegate to the Go storages of storage_2c.go.
+//
+
+#include <stdio.h>
+#include "findy_glue.h"
+#include "findy_callback_types.h"
+
+// cgo generated
+#include "_cgo_export.h"
+
+typedef indy_error_t (*findy_storage_create_cb)(const char *name, const char *config, const char *credentials_json, const char *metadata);
+typedef indy_error_t (*findy_storage_open_cb)(const char *name, const char *config, const char *credentials_json, indy_handle_t *storage_handle_p);
+typedef indy_error_t (*findy_storage_delete_cb)(const char *name, const char *config, const char *credentials_json);
+
+#define FINDY_STORAGE_SLOT(n) \
+static indy_error_t findy_storage_create_##n(const char *name, const char *config, const char *credentials_json, const char *metadata) { \
+	return (indy_error_t)findyStorageCreate(n, (char*)name, (char*)config, (char*)credentials_json, (char*)metadata); \
+} \
+static indy_error_t findy_storage_open_##n(const char *name, const char *config, const char *credentials_json, indy_handle_t *storage_handle_p) { \
+	return (indy_error_t)findyStorageOpen(n, (char*)name, (char*)config, (char*)credentials_json, storage_handle_p); \
+} \
+static indy_error_t findy_storage_delete_##n(const char *name, const char *config, const char *credentials_json) { \
+	return (indy_error_t)findyStorageDelete(n, (char*)name, (char*)config, (char*)credentials_json); \
+}
+
+FINDY_STORAGE_SLOT(0)
+FINDY_STORAGE_SLOT(1)
+FINDY_STORAGE_SLOT(2)
+FINDY_STORAGE_SLOT(3)
+FINDY_STORAGE_SLOT(4)
+FINDY_STORAGE_SLOT(5)
+FINDY_STORAGE_SLOT(6)
+FINDY_STORAGE_SLOT(7)
+
+static findy_storage_create_cb findy_storage_create_cbs[FINDY_STORAGE_SLOTS] = {
+	findy_storage_create_0, findy_storage_create_1, findy_storage_create_2, findy_storage_create_3,
+	findy_storage_create_4, findy_storage_create_5, findy_storage_create_6, findy_storage_create_7,
+};
+
+static findy_storage_open_cb findy_storage_open_cbs[FINDY_STORAGE_SLOTS] = {
+	findy_storage_open_0, findy_storage_open_1, findy_storage_open_2, findy_storage_open_3,
+	findy_storage_open_4, findy_storage_open_5, findy_storage_open_6, findy_storage_open_7,
+};
+
+static findy_storage_delete_cb findy_storage_delete_cbs[FINDY_STORAGE_SLOTS] = {
+	findy_storage_delete_0, findy_storage_delete_1, findy_storage_delete_2, findy_storage_delete_3,
+	findy_storage_delete_4, findy_storage_delete_5, findy_storage_delete_6, findy_storage_delete_7,
+};
+
+static indy_error_t findy_storage_close(indy_handle_t storage_handle) {
+	return (indy_error_t)findyStorageClose(storage_handle);
+}
+
+static indy_error_t findy_storage_add_record(indy_handle_t storage_handle, const char *type_, const char *id, const indy_u8_t *value, indy_u32_t value_len, const char *tags_json) {
+	return (indy_error_t)findyStorageAddRecord(storage_handle, (char*)type_, (char*)id, (unsigned char*)value, value_len, (char*)tags_json);
+}
+
+static indy_error_t findy_storage_update_record_value(indy_handle_t storage_handle, const char *type_, const char *id, const indy_u8_t *value, indy_u32_t value_len) {
+	return (indy_error_t)findyStorageUpdateRecordValue(storage_handle, (char*)type_, (char*)id, (unsigned char*)value, value_len);
+}
+
+static indy_error_t findy_storage_update_record_tags(indy_handle_t storage_handle, const char *type_, const char *id, const char *tags_json) {
+	return (indy_error_t)findyStorageUpdateRecordTags(storage_handle, (char*)type_, (char*)id, (char*)tags_json);
+}
+
+static indy_error_t findy_storage_add_record_tags(indy_handle_t storage_handle, const char *type_, const char *id, const char *tags_json) {
+	return (indy_error_t)findyStorageAddRecordTags(storage_handle, (char*)type_, (char*)id, (char*)tags_json);
+}
+
+static indy_error_t findy_storage_delete_record_tags(indy_handle_t storage_handle, const char *type_, const char *id, const char *tag_names_json) {
+	return (indy_error_t)findyStorageDeleteRecordTags(storage_handle, (char*)type_, (char*)id, (char*)tag_names_json);
+}
+
+static indy_error_t findy_storage_delete_record(indy_handle_t storage_handle, const char *type_, const char *id) {
+	return (indy_error_t)findyStorageDeleteRecord(storage_handle, (char*)type_, (char*)id);
+}
+
+static indy_error_t findy_storage_get_record(indy_handle_t storage_handle, const char *type_, const char *id, const char *options_json, indy_handle_t *record_handle_p) {
+	return (indy_error_t)findyStorageGetRecord(storage_handle, (char*)type_, (char*)id, (char*)options_json, record_handle_p);
+}
+
+static indy_error_t findy_storage_get_record_id(indy_handle_t storage_handle, indy_handle_t record_handle, const char **record_id_p) {
+	return (indy_error_t)findyStorageGetRecordID(storage_handle, record_handle, (char**)record_id_p);
+}
+
+static indy_error_t findy_storage_get_record_type(indy_handle_t storage_handle, indy_handle_t record_handle, const char **record_type_p) {
+	return (indy_error_t)findyStorageGetRecordType(storage_handle, record_handle, (char**)record_type_p);
+}
+
+static indy_error_t findy_storage_get_record_value(indy_handle_t storage_handle, indy_handle_t record_handle, const indy_u8_t **record_value_p, indy_u32_t *record_value_len_p) {
+	return (indy_error_t)findyStorageGetRecordValue(storage_handle, record_handle, (unsigned char**)record_value_p, record_value_len_p);
+}
+
+static indy_error_t findy_storage_get_record_tags(indy_handle_t storage_handle, indy_handle_t record_handle, const char **record_tags_p) {
+	return (indy_error_t)findyStorageGetRecordTags(storage_handle, record_handle, (char**)record_tags_p);
+}
+
+static indy_error_t findy_storage_free_record(indy_handle_t storage_handle, indy_handle_t record_handle) {
+	return (indy_error_t)findyStorageFreeRecord(storage_handle, record_handle);
+}
+
+static indy_error_t findy_storage_get_storage_metadata(indy_handle_t storage_handle, const char **metadata_p, indy_handle_t *metadata_handle) {
+	return (indy_error_t)findyStorageGetMetadata(storage_handle, (char**)metadata_p, metadata_handle);
+}
+
+static indy_error_t findy_storage_set_storage_metadata(indy_handle_t storage_handle, const char *metadata_p) {
+	return (indy_error_t)findyStorageSetMetadata(storage_handle, (char*)metadata_p);
+}
+
+static indy_error_t findy_storage_free_storage_metadata(indy_handle_t storage_handle, indy_handle_t metadata_handle) {
+	return (indy_error_t)findyStorageFreeMetadata(storage_handle, metadata_handle);
+}
+
+static indy_error_t findy_storage_search_records(indy_handle_t storage_handle, const char *type_, const char *query_json, const char *options_json, indy_handle_t *search_handle_p) {
+	return (indy_error_t)findyStorageSearchRecords(storage_handle, (char*)type_, (char*)query_json, (char*)options_json, search_handle_p);
+}
+
+static indy_error_t findy_storage_search_all_records(indy_handle_t storage_handle, indy_handle_t *search_handle_p) {
+	return (indy_error_t)findyStorageSearchAllRecords(storage_handle, search_handle_p);
+}
+
+static indy_error_t findy_storage_get_search_total_count(indy_handle_t storage_handle, indy_handle_t search_handle, indy_u32_t *total_count_p) {
+	return (indy_error_t)findyStorageGetSearchTotalCount(storage_handle, search_handle, total_count_p);
+}
+
+static indy_error_t findy_storage_fetch_search_next_record(indy_handle_t storage_handle, indy_handle_t search_handle, indy_handle_t *record_handle_p) {
+	return (indy_error_t)findyStorageFetchSearchNextRecord(storage_handle, search_handle, record_handle_p);
+}
+
+static indy_error_t findy_storage_free_search(indy_handle_t storage_handle, indy_handle_t search_handle) {
+	return (indy_error_t)findyStorageFreeSearch(storage_handle, search_handle);
+}
+
+indy_error_t findy_register_wallet_storage(indy_handle_t command_handle, char *type_, int slot) {
+	indy_error_t err = indy_register_wallet_storage(command_handle, type_,
+		findy_storage_create_cbs[slot], findy_storage_open_cbs[slot], findy_storage_close, findy_storage_delete_cbs[slot],
+		findy_storage_add_record, findy_storage_update_record_value, findy_storage_update_record_tags,
+		findy_storage_add_record_tags, findy_storage_delete_record_tags, findy_storage_delete_record,
+		findy_storage_get_record, findy_storage_get_record_id, findy_storage_get_record_type,
+		findy_storage_get_record_value, findy_storage_get_record_tags, findy_storage_free_record,
+		findy_storage_get_storage_metadata, findy_storage_set_storage_metadata, findy_storage_free_storage_metadata,
+		findy_storage_search_records, findy_storage_search_all_records, findy_storage_get_search_total_count,
+		findy_storage_fetch_search_next_record, findy_storage_free_search,
+		(indy_handler)handler);
+	if (err != Success) {
+		handler(command_handle, err);
+	}
+	return err;
+}
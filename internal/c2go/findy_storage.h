@@ -0,0 +1,6 @@
+// FINDY_STORAGE_SLOTS is the count of the Go wallet storages which can be
+// registered. The create, open and delete callbacks don't have the storage
+// type, so every Go storage has its own slot of them.
+#define FINDY_STORAGE_SLOTS 8
+
+extern indy_error_t findy_register_wallet_storage(indy_handle_t command_handle, char *type_, int slot);
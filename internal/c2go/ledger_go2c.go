@@ -178,3 +178,46 @@ func FindyBuildGetCredDefRequest(submitter, id string) ctx.Channel {
 	C.findy_build_get_cred_def_request(C.int(cmdHandle), submitterInC, idInC)
 	return ch
 }
+
+func FindyBuildGetRevocRegDefRequest(submitter, id string) ctx.Channel {
+	var submitterInC *C.char = C.findy_null_string
+	if submitter != findy.NullString {
+		submitterInC = C.CString(submitter)
+		defer C.free(unsafe.Pointer(submitterInC))
+	}
+	idInC := C.CString(id)
+	defer C.free(unsafe.Pointer(idInC))
+	cmdHandle, ch := ctx.CmdContext.NamedPush("FindyBuildGetRevocRegDefRequest")
+	C.findy_build_get_revoc_reg_def_request(C.int(cmdHandle), submitterInC, idInC)
+	return ch
+}
+
+func FindyParseGetRevocRegDefResponse(response string) ctx.Channel {
+	responseInC := C.CString(response)
+	defer C.free(unsafe.Pointer(responseInC))
+	cmdHandle, ch := ctx.CmdContext.NamedPush("FindyParseGetRevocRegDefResponse")
+	C.findy_parse_get_revoc_reg_def_response(C.int(cmdHandle), responseInC)
+	return ch
+}
+
+func FindyBuildGetRevocRegRequest(submitter, revRegDefID string, timestamp int64) ctx.Channel {
+	var submitterInC *C.char = C.findy_null_string
+	if submitter != findy.NullString {
+		submitterInC = C.CString(submitter)
+		defer C.free(unsafe.Pointer(submitterInC))
+	}
+	revRegDefIDInC := C.CString(revRegDefID)
+	defer C.free(unsafe.Pointer(revRegDefIDInC))
+	cmdHandle, ch := ctx.CmdContext.NamedPush("FindyBuildGetRevocRegRequest")
+	C.findy_build_get_revoc_reg_request(C.int(cmdHandle), submitterInC, revRegDefIDInC,
+		C.longlong(timestamp))
+	return ch
+}
+
+func FindyParseGetRevocRegResponse(response string) ctx.Channel {
+	responseInC := C.CString(response)
+	defer C.free(unsafe.Pointer(responseInC))
+	cmdHandle, ch := ctx.CmdContext.NamedPush("FindyParseGetRevocRegResponse")
+	C.findy_parse_get_revoc_reg_response(C.int(cmdHandle), responseInC)
+	return ch
+}
@@ -0,0 +1,119 @@
+package c2go
+
+//#include <stdio.h>
+//#include <stdlib.h>
+//#include "findy_glue.h"
+import "C"
+import (
+	"unsafe"
+
+	"github.com/findy-network/findy-wrapper-go/internal/ctx"
+)
+
+func FindyAddWalletRecord(wallet int, typ, id, value, tagsJSON string) ctx.Channel {
+	typInC := C.CString(typ)
+	defer C.free(unsafe.Pointer(typInC))
+	idInC := C.CString(id)
+	defer C.free(unsafe.Pointer(idInC))
+	valueInC := C.CString(value)
+	defer C.free(unsafe.Pointer(valueInC))
+	tagsJSONInC := C.CString(tagsJSON)
+	defer C.free(unsafe.Pointer(tagsJSONInC))
+	cmdHandle, ch := ctx.CmdContext.NamedPush("FindyAddWalletRecord: " + typ)
+	C.findy_add_wallet_record(C.int(cmdHandle), C.int(wallet), typInC, idInC, valueInC, tagsJSONInC)
+	return ch
+}
+
+func FindyUpdateWalletRecordValue(wallet int, typ, id, value string) ctx.Channel {
+	typInC := C.CString(typ)
+	defer C.free(unsafe.Pointer(typInC))
+	idInC := C.CString(id)
+	defer C.free(unsafe.Pointer(idInC))
+	valueInC := C.CString(value)
+	defer C.free(unsafe.Pointer(valueInC))
+	cmdHandle, ch := ctx.CmdContext.NamedPush("FindyUpdateWalletRecordValue: " + typ)
+	C.findy_update_wallet_record_value(C.int(cmdHandle), C.int(wallet), typInC, idInC, valueInC)
+	return ch
+}
+
+func FindyUpdateWalletRecordTags(wallet int, typ, id, tagsJSON string) ctx.Channel {
+	typInC := C.CString(typ)
+	defer C.free(unsafe.Pointer(typInC))
+	idInC := C.CString(id)
+	defer C.free(unsafe.Pointer(idInC))
+	tagsJSONInC := C.CString(tagsJSON)
+	defer C.free(unsafe.Pointer(tagsJSONInC))
+	cmdHandle, ch := ctx.CmdContext.NamedPush("FindyUpdateWalletRecordTags: " + typ)
+	C.findy_update_wallet_record_tags(C.int(cmdHandle), C.int(wallet), typInC, idInC, tagsJSONInC)
+	return ch
+}
+
+func FindyAddWalletRecordTags(wallet int, typ, id, tagsJSON string) ctx.Channel {
+	typInC := C.CString(typ)
+	defer C.free(unsafe.Pointer(typInC))
+	idInC := C.CString(id)
+	defer C.free(unsafe.Pointer(idInC))
+	tagsJSONInC := C.CString(tagsJSON)
+	defer C.free(unsafe.Pointer(tagsJSONInC))
+	cmdHandle, ch := ctx.CmdContext.NamedPush("FindyAddWalletRecordTags: " + typ)
+	C.findy_add_wallet_record_tags(C.int(cmdHandle), C.int(wallet), typInC, idInC, tagsJSONInC)
+	return ch
+}
+
+func FindyDeleteWalletRecordTags(wallet int, typ, id, tagNamesJSON string) ctx.Channel {
+	typInC := C.CString(typ)
+	defer C.free(unsafe.Pointer(typInC))
+	idInC := C.CString(id)
+	defer C.free(unsafe.Pointer(idInC))
+	tagNamesJSONInC := C.CString(tagNamesJSON)
+	defer C.free(unsafe.Pointer(tagNamesJSONInC))
+	cmdHandle, ch := ctx.CmdContext.NamedPush("FindyDeleteWalletRecordTags: " + typ)
+	C.findy_delete_wallet_record_tags(C.int(cmdHandle), C.int(wallet), typInC, idInC, tagNamesJSONInC)
+	return ch
+}
+
+func FindyDeleteWalletRecord(wallet int, typ, id string) ctx.Channel {
+	typInC := C.CString(typ)
+	defer C.free(unsafe.Pointer(typInC))
+	idInC := C.CString(id)
+	defer C.free(unsafe.Pointer(idInC))
+	cmdHandle, ch := ctx.CmdContext.NamedPush("FindyDeleteWalletRecord: " + typ)
+	C.findy_delete_wallet_record(C.int(cmdHandle), C.int(wallet), typInC, idInC)
+	return ch
+}
+
+func FindyGetWalletRecord(wallet int, typ, id, optionsJSON string) ctx.Channel {
+	typInC := C.CString(typ)
+	defer C.free(unsafe.Pointer(typInC))
+	idInC := C.CString(id)
+	defer C.free(unsafe.Pointer(idInC))
+	optionsJSONInC := C.CString(optionsJSON)
+	defer C.free(unsafe.Pointer(optionsJSONInC))
+	cmdHandle, ch := ctx.CmdContext.NamedPush("FindyGetWalletRecord: " + typ)
+	C.findy_get_wallet_record(C.int(cmdHandle), C.int(wallet), typInC, idInC, optionsJSONInC)
+	return ch
+}
+
+func FindyOpenWalletSearch(wallet int, typ, queryJSON, optionsJSON string) ctx.Channel {
+	typInC := C.CString(typ)
+	defer C.free(unsafe.Pointer(typInC))
+	queryJSONInC := C.CString(queryJSON)
+	defer C.free(unsafe.Pointer(queryJSONInC))
+	optionsJSONInC := C.CString(optionsJSON)
+	defer C.free(unsafe.Pointer(optionsJSONInC))
+	cmdHandle, ch := ctx.CmdContext.NamedPush("FindyOpenWalletSearch: " + typ)
+	C.findy_open_wallet_search(C.int(cmdHandle), C.int(wallet), typInC, queryJSONInC, optionsJSONInC)
+	return ch
+}
+
+func FindyFetchWalletSearchNextRecords(wallet, searchHandle, count int) ctx.Channel {
+	cmdHandle, ch := ctx.CmdContext.NamedPush("FindyFetchWalletSearchNextRecords")
+	C.findy_fetch_wallet_search_next_records(C.int(cmdHandle), C.int(wallet), C.int(searchHandle), C.uint(count))
+	return ch
+}
+
+func FindyCloseWalletSearch(searchHandle int) ctx.Channel {
+	cmdHandle, ch := ctx.CmdContext.NamedPush("FindyCloseWalletSearch")
+	C.findy_close_wallet_search(C.int(cmdHandle), C.int(searchHandle))
+	return ch
+}
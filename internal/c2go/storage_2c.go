@@ -0,0 +1,540 @@
+package c2go
+
+//#include <stdio.h>
+//#include <stdlib.h>
+//#include "findy_glue.h"
+import "C"
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"sync"
+	"unsafe"
+
+	"github.com/findy-network/findy-wrapper-go/dto"
+	"github.com/findy-network/findy-wrapper-go/internal/ctx"
+	"github.com/findy-network/findy-wrapper-go/plugin"
+	"github.com/findy-network/findy-wrapper-go/wql"
+	"github.com/golang/glog"
+)
+
+// Indy error codes returned to libindy by the storage callbacks.
+const (
+	errCodeInvalidStructure = 113
+	errCodeInvalidState     = 112
+	errCodeInvalidHandle    = 200
+	errCodeTypeRegistered   = 202
+	errCodeWalletExists     = 203
+	errCodeWalletNotFound   = 204
+	errCodeStorageError     = 210
+	errCodeItemNotFound     = 212
+	errCodeItemExists       = 213
+	errCodeQueryError       = 214
+)
+
+// storageRecord is the record given to libindy. Its C strings are allocated
+// when libindy asks them, and they are freed by free_record.
+type storageRecord struct {
+	plugin.StorageRecord
+
+	id, typ, tags *C.char
+	value         unsafe.Pointer
+}
+
+type storageSearch struct {
+	records []plugin.StorageRecord
+	total   int
+}
+
+// storages holds the registered Go storages and the handles given to
+// libindy. Each handle map has its own counter, and the handles are unique
+// within their map.
+var storages = struct {
+	sync.Mutex
+	slots    [C.FINDY_STORAGE_SLOTS]plugin.Storage
+	types    map[string]int // slots by the registered storage types
+	handles  storageHandles
+	wallets  map[int32]plugin.StorageWallet
+	records  map[int32]*storageRecord
+	searches map[int32]*storageSearch
+	metadata map[int32]*C.char
+}{
+	types:    make(map[string]int),
+	wallets:  make(map[int32]plugin.StorageWallet),
+	records:  make(map[int32]*storageRecord),
+	searches: make(map[int32]*storageSearch),
+	metadata: make(map[int32]*C.char),
+}
+
+// FindyRegisterWalletStorage registers the Go storage as the wallet storage
+// type to libindy. Only FINDY_STORAGE_SLOTS storages can be registered to
+// the process. The slot is freed if libindy doesn't accept the type, and the
+// type which is already registered is rejected before it takes a slot.
+func FindyRegisterWalletStorage(typ string, storage plugin.Storage) ctx.Channel {
+	storages.Lock()
+	if _, exists := storages.types[typ]; exists {
+		storages.Unlock()
+		return errorResult(errCodeTypeRegistered,
+			fmt.Errorf("cannot register %s: storage type is already registered", typ))
+	}
+	slot := freeStorageSlot()
+	if slot < 0 {
+		storages.Unlock()
+		return errorResult(errCodeInvalidState,
+			fmt.Errorf("cannot register %s: all %d storage slots are used", typ, len(storages.slots)))
+	}
+	storages.slots[slot] = storage
+	storages.types[typ] = slot
+	storages.Unlock()
+
+	typInC := C.CString(typ)
+	defer C.free(unsafe.Pointer(typInC))
+	cmdHandle, ch := ctx.CmdContext.NamedPush("FindyRegisterWalletStorage: " + typ)
+	C.findy_register_wallet_storage(C.int(cmdHandle), typInC, C.int(slot))
+
+	result := make(ctx.Channel, ctx.ChannelBufferSize)
+	go func() {
+		r := <-ch
+		if r.Err() != nil {
+			storages.Lock()
+			storages.slots[slot] = nil
+			delete(storages.types, typ)
+			storages.Unlock()
+		}
+		result <- r
+	}()
+	return result
+}
+
+// freeStorageSlot returns the first free slot, or -1 if all of them are used.
+// The storages must be locked.
+func freeStorageSlot() int {
+	for slot, storage := range storages.slots {
+		if storage == nil {
+			return slot
+		}
+	}
+	return -1
+}
+
+func errorResult(code int, err error) ctx.Channel {
+	r := dto.Result{}
+	r.SetErrCode(code)
+	r.SetErr(err)
+	ch := make(ctx.Channel, ctx.ChannelBufferSize)
+	ch <- r
+	return ch
+}
+
+func storageErrCode(err error) C.int {
+	switch {
+	case err == nil:
+		return C.Success
+	case errors.Is(err, plugin.ErrRecordNotFound):
+		return errCodeItemNotFound
+	case errors.Is(err, plugin.ErrRecordExists):
+		return errCodeItemExists
+	case errors.Is(err, plugin.ErrWalletNotFound):
+		return errCodeWalletNotFound
+	case errors.Is(err, plugin.ErrWalletExists):
+		return errCodeWalletExists
+	}
+	glog.Errorln("wallet storage:", err)
+	return errCodeStorageError
+}
+
+// storageHandles are the last handles given from each of the handle maps.
+type storageHandles struct {
+	wallet, record, search, metadata int32
+}
+
+// addHandle adds the value to the handle map with the next positive handle
+// which isn't in use, and returns the handle. The counter wraps around
+// to 1. The storages must be locked.
+func addHandle[T any](handles map[int32]T, last *int32, v T) int32 {
+	for {
+		if *last == math.MaxInt32 {
+			*last = 0
+		}
+		*last++
+		if _, used := handles[*last]; !used {
+			handles[*last] = v
+			return *last
+		}
+	}
+}
+
+func slotStorage(slot C.int) plugin.Storage {
+	storages.Lock()
+	defer storages.Unlock()
+	return storages.slots[slot]
+}
+
+func storageWallet(handle C.indy_handle_t) (plugin.StorageWallet, bool) {
+	storages.Lock()
+	defer storages.Unlock()
+	w, ok := storages.wallets[int32(handle)]
+	return w, ok
+}
+
+func newRecordHandle(r plugin.StorageRecord) C.indy_handle_t {
+	storages.Lock()
+	defer storages.Unlock()
+	h := addHandle(storages.records, &storages.handles.record, &storageRecord{StorageRecord: r})
+	return C.indy_handle_t(h)
+}
+
+// withRecord calls f with the record while the storages are locked.
+func withRecord(handle C.indy_handle_t, f func(r *storageRecord)) C.int {
+	storages.Lock()
+	defer storages.Unlock()
+	r, ok := storages.records[int32(handle)]
+	if !ok {
+		return errCodeInvalidHandle
+	}
+	f(r)
+	return C.Success
+}
+
+func parseTags(tagsJSON *C.char) (tags map[string]string, code C.int) {
+	if err := json.Unmarshal([]byte(C.GoString(tagsJSON)), &tags); err != nil {
+		glog.Errorln("wallet storage tags:", err)
+		return nil, errCodeInvalidStructure
+	}
+	return tags, C.Success
+}
+
+// MARK: wallet storage callbacks, these are called from findy_storage.c
+
+//export findyStorageCreate
+func findyStorageCreate(slot C.int, name, config, credentials, metadata *C.char) C.int {
+	return storageErrCode(slotStorage(slot).Create(C.GoString(name), C.GoString(config),
+		C.GoString(credentials), C.GoString(metadata)))
+}
+
+//export findyStorageOpen
+func findyStorageOpen(slot C.int, name, config, credentials *C.char, handle *C.indy_handle_t) C.int {
+	w, err := slotStorage(slot).Open(C.GoString(name), C.GoString(config), C.GoString(credentials))
+	if err != nil {
+		return storageErrCode(err)
+	}
+	storages.Lock()
+	defer storages.Unlock()
+	h := addHandle(storages.wallets, &storages.handles.wallet, w)
+	*handle = C.indy_handle_t(h)
+	return C.Success
+}
+
+//export findyStorageDelete
+func findyStorageDelete(slot C.int, name, config, credentials *C.char) C.int {
+	return storageErrCode(slotStorage(slot).Delete(C.GoString(name), C.GoString(config),
+		C.GoString(credentials)))
+}
+
+//export findyStorageClose
+func findyStorageClose(handle C.indy_handle_t) C.int {
+	storages.Lock()
+	w, ok := storages.wallets[int32(handle)]
+	delete(storages.wallets, int32(handle))
+	storages.Unlock()
+	if !ok {
+		return errCodeInvalidHandle
+	}
+	return storageErrCode(w.Close())
+}
+
+//export findyStorageAddRecord
+func findyStorageAddRecord(handle C.indy_handle_t, typ, id *C.char, value *C.uchar, valueLen C.indy_u32_t, tagsJSON *C.char) C.int {
+	w, ok := storageWallet(handle)
+	if !ok {
+		return errCodeInvalidHandle
+	}
+	tags, code := parseTags(tagsJSON)
+	if code != C.Success {
+		return code
+	}
+	return storageErrCode(w.Add(plugin.StorageRecord{
+		Type:  C.GoString(typ),
+		ID:    C.GoString(id),
+		Value: C.GoBytes(unsafe.Pointer(value), C.int(valueLen)),
+		Tags:  tags,
+	}))
+}
+
+//export findyStorageUpdateRecordValue
+func findyStorageUpdateRecordValue(handle C.indy_handle_t, typ, id *C.char, value *C.uchar, valueLen C.indy_u32_t) C.int {
+	w, ok := storageWallet(handle)
+	if !ok {
+		return errCodeInvalidHandle
+	}
+	return storageErrCode(w.UpdateValue(C.GoString(typ), C.GoString(id),
+		C.GoBytes(unsafe.Pointer(value), C.int(valueLen))))
+}
+
+//export findyStorageUpdateRecordTags
+func findyStorageUpdateRecordTags(handle C.indy_handle_t, typ, id, tagsJSON *C.char) C.int {
+	w, ok := storageWallet(handle)
+	if !ok {
+		return errCodeInvalidHandle
+	}
+	tags, code := parseTags(tagsJSON)
+	if code != C.Success {
+		return code
+	}
+	return storageErrCode(w.UpdateTags(C.GoString(typ), C.GoString(id), tags))
+}
+
+//export findyStorageAddRecordTags
+func findyStorageAddRecordTags(handle C.indy_handle_t, typ, id, tagsJSON *C.char) C.int {
+	w, ok := storageWallet(handle)
+	if !ok {
+		return errCodeInvalidHandle
+	}
+	tags, code := parseTags(tagsJSON)
+	if code != C.Success {
+		return code
+	}
+	return storageErrCode(w.AddTags(C.GoString(typ), C.GoString(id), tags))
+}
+
+//export findyStorageDeleteRecordTags
+func findyStorageDeleteRecordTags(handle C.indy_handle_t, typ, id, namesJSON *C.char) C.int {
+	w, ok := storageWallet(handle)
+	if !ok {
+		return errCodeInvalidHandle
+	}
+	var names []string
+	if err := json.Unmarshal([]byte(C.GoString(namesJSON)), &names); err != nil {
+		glog.Errorln("wallet storage tag names:", err)
+		return errCodeInvalidStructure
+	}
+	return storageErrCode(w.DeleteTags(C.GoString(typ), C.GoString(id), names))
+}
+
+//export findyStorageDeleteRecord
+func findyStorageDeleteRecord(handle C.indy_handle_t, typ, id *C.char) C.int {
+	w, ok := storageWallet(handle)
+	if !ok {
+		return errCodeInvalidHandle
+	}
+	return storageErrCode(w.Delete(C.GoString(typ), C.GoString(id)))
+}
+
+// findyStorageGetRecord returns the whole record. Libindy reads only the
+// parts its options_json asks.
+//
+//export findyStorageGetRecord
+func findyStorageGetRecord(handle C.indy_handle_t, typ, id, _ *C.char, recordHandle *C.indy_handle_t) C.int {
+	w, ok := storageWallet(handle)
+	if !ok {
+		return errCodeInvalidHandle
+	}
+	r, err := w.Get(C.GoString(typ), C.GoString(id))
+	if err != nil {
+		return storageErrCode(err)
+	}
+	*recordHandle = newRecordHandle(r)
+	return C.Success
+}
+
+//export findyStorageGetRecordID
+func findyStorageGetRecordID(_, recordHandle C.indy_handle_t, id **C.char) C.int {
+	return withRecord(recordHandle, func(r *storageRecord) {
+		if r.id == nil {
+			r.id = C.CString(r.ID)
+		}
+		*id = r.id
+	})
+}
+
+//export findyStorageGetRecordType
+func findyStorageGetRecordType(_, recordHandle C.indy_handle_t, typ **C.char) C.int {
+	return withRecord(recordHandle, func(r *storageRecord) {
+		if r.typ == nil {
+			r.typ = C.CString(r.Type)
+		}
+		*typ = r.typ
+	})
+}
+
+//export findyStorageGetRecordValue
+func findyStorageGetRecordValue(_, recordHandle C.indy_handle_t, value **C.uchar, valueLen *C.indy_u32_t) C.int {
+	return withRecord(recordHandle, func(r *storageRecord) {
+		if r.value == nil {
+			// one extra byte, because C.CBytes of an empty slice is nil
+			r.value = C.CBytes(append(r.Value, 0))
+		}
+		*value = (*C.uchar)(r.value)
+		*valueLen = C.indy_u32_t(len(r.Value))
+	})
+}
+
+//export findyStorageGetRecordTags
+func findyStorageGetRecordTags(_, recordHandle C.indy_handle_t, tags **C.char) C.int {
+	return withRecord(recordHandle, func(r *storageRecord) {
+		if r.tags == nil {
+			r.tags = C.CString(tagsJSON(r.Tags))
+		}
+		*tags = r.tags
+	})
+}
+
+//export findyStorageFreeRecord
+func findyStorageFreeRecord(_, recordHandle C.indy_handle_t) C.int {
+	storages.Lock()
+	defer storages.Unlock()
+	r, ok := storages.records[int32(recordHandle)]
+	if !ok {
+		return errCodeInvalidHandle
+	}
+	delete(storages.records, int32(recordHandle))
+	for _, p := range []unsafe.Pointer{unsafe.Pointer(r.id), unsafe.Pointer(r.typ),
+		unsafe.Pointer(r.tags), r.value} {
+		C.free(p)
+	}
+	return C.Success
+}
+
+//export findyStorageGetMetadata
+func findyStorageGetMetadata(handle C.indy_handle_t, metadata **C.char, metadataHandle *C.indy_handle_t) C.int {
+	w, ok := storageWallet(handle)
+	if !ok {
+		return errCodeInvalidHandle
+	}
+	m, err := w.Metadata()
+	if err != nil {
+		return storageErrCode(err)
+	}
+	storages.Lock()
+	defer storages.Unlock()
+	*metadata = C.CString(m)
+	h := addHandle(storages.metadata, &storages.handles.metadata, *metadata)
+	*metadataHandle = C.indy_handle_t(h)
+	return C.Success
+}
+
+//export findyStorageSetMetadata
+func findyStorageSetMetadata(handle C.indy_handle_t, metadata *C.char) C.int {
+	w, ok := storageWallet(handle)
+	if !ok {
+		return errCodeInvalidHandle
+	}
+	return storageErrCode(w.SetMetadata(C.GoString(metadata)))
+}
+
+//export findyStorageFreeMetadata
+func findyStorageFreeMetadata(_, metadataHandle C.indy_handle_t) C.int {
+	storages.Lock()
+	defer storages.Unlock()
+	m, ok := storages.metadata[int32(metadataHandle)]
+	if !ok {
+		return errCodeInvalidHandle
+	}
+	delete(storages.metadata, int32(metadataHandle))
+	C.free(unsafe.Pointer(m))
+	return C.Success
+}
+
+//export findyStorageSearchRecords
+func findyStorageSearchRecords(handle C.indy_handle_t, typ, queryJSON, optionsJSON *C.char, searchHandle *C.indy_handle_t) C.int {
+	w, ok := storageWallet(handle)
+	if !ok {
+		return errCodeInvalidHandle
+	}
+	query, err := wql.Parse(C.GoString(queryJSON))
+	if err != nil {
+		glog.Errorln("wallet storage query:", err)
+		return errCodeQueryError
+	}
+	options := struct {
+		RetrieveRecords bool `json:"retrieveRecords"`
+	}{RetrieveRecords: true}
+	if s := C.GoString(optionsJSON); s != "" {
+		if err := json.Unmarshal([]byte(s), &options); err != nil {
+			glog.Errorln("wallet storage search options:", err)
+			return errCodeInvalidStructure
+		}
+	}
+	records, err := w.Search(C.GoString(typ), query)
+	if err != nil {
+		return storageErrCode(err)
+	}
+	search := &storageSearch{records: records, total: len(records)}
+	if !options.RetrieveRecords {
+		search.records = nil
+	}
+	*searchHandle = newSearchHandle(search)
+	return C.Success
+}
+
+//export findyStorageSearchAllRecords
+func findyStorageSearchAllRecords(handle C.indy_handle_t, searchHandle *C.indy_handle_t) C.int {
+	w, ok := storageWallet(handle)
+	if !ok {
+		return errCodeInvalidHandle
+	}
+	records, err := w.SearchAll()
+	if err != nil {
+		return storageErrCode(err)
+	}
+	*searchHandle = newSearchHandle(&storageSearch{records: records, total: len(records)})
+	return C.Success
+}
+
+func newSearchHandle(s *storageSearch) C.indy_handle_t {
+	storages.Lock()
+	defer storages.Unlock()
+	h := addHandle(storages.searches, &storages.handles.search, s)
+	return C.indy_handle_t(h)
+}
+
+//export findyStorageGetSearchTotalCount
+func findyStorageGetSearchTotalCount(_, searchHandle C.indy_handle_t, count *C.indy_u32_t) C.int {
+	storages.Lock()
+	defer storages.Unlock()
+	s, ok := storages.searches[int32(searchHandle)]
+	if !ok {
+		return errCodeInvalidHandle
+	}
+	*count = C.indy_u32_t(s.total)
+	return C.Success
+}
+
+// findyStorageFetchSearchNextRecord returns WalletItemNotFound when the search
+// is exhausted like libindy expects.
+//
+//export findyStorageFetchSearchNextRecord
+func findyStorageFetchSearchNextRecord(_, searchHandle C.indy_handle_t, recordHandle *C.indy_handle_t) C.int {
+	storages.Lock()
+	defer storages.Unlock()
+	s, ok := storages.searches[int32(searchHandle)]
+	if !ok {
+		return errCodeInvalidHandle
+	}
+	if len(s.records) == 0 {
+		return errCodeItemNotFound
+	}
+	h := addHandle(storages.records, &storages.handles.record, &storageRecord{StorageRecord: s.records[0]})
+	s.records = s.records[1:]
+	*recordHandle = C.indy_handle_t(h)
+	return C.Success
+}
+
+//export findyStorageFreeSearch
+func findyStorageFreeSearch(_, searchHandle C.indy_handle_t) C.int {
+	storages.Lock()
+	defer storages.Unlock()
+	if _, ok := storages.searches[int32(searchHandle)]; !ok {
+		return errCodeInvalidHandle
+	}
+	delete(storages.searches, int32(searchHandle))
+	return C.Success
+}
+
+func tagsJSON(tags map[string]string) string {
+	if tags == nil {
+		return "{}"
+	}
+	return dto.ToJSON(tags)
+}
@@ -0,0 +1,47 @@
+// Package multibase encodes and decodes the multicodec prefixed public keys
+// in the base58btc multibase, i.e. the z6Mk... keys of did:key and did:peer.
+package multibase
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+
+	"github.com/findy-network/findy-wrapper-go/internal/base58"
+)
+
+// Multicodec codes of the public keys.
+const (
+	Ed25519Pub uint64 = 0xed
+	X25519Pub  uint64 = 0xec
+)
+
+// base58BTC is the multibase prefix of base58btc.
+const base58BTC = 'z'
+
+// ErrInvalid is returned when the key cannot be decoded.
+var ErrInvalid = errors.New("multibase: invalid key")
+
+// Encode returns the base58btc multibase of the key with the multicodec
+// prefix.
+func Encode(codec uint64, key []byte) string {
+	b := binary.AppendUvarint(nil, codec)
+	return string(base58BTC) + base58.Encode(append(b, key...))
+}
+
+// Decode decodes the base58btc multibase key and returns its multicodec and
+// the raw key.
+func Decode(s string) (codec uint64, key []byte, err error) {
+	if len(s) < 2 || s[0] != base58BTC {
+		return 0, nil, fmt.Errorf("%w %q: not base58btc", ErrInvalid, s)
+	}
+	b, err := base58.Decode(s[1:])
+	if err != nil {
+		return 0, nil, fmt.Errorf("%w %q: %v", ErrInvalid, s, err)
+	}
+	codec, n := binary.Uvarint(b)
+	if n <= 0 {
+		return 0, nil, fmt.Errorf("%w %q: multicodec", ErrInvalid, s)
+	}
+	return codec, b[n:], nil
+}
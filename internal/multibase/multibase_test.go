@@ -0,0 +1,28 @@
+package multibase
+
+import (
+	"testing"
+
+	"github.com/findy-network/findy-wrapper-go/internal/base58"
+	"github.com/lainio/err2/assert"
+)
+
+func TestEncodeDecode(t *testing.T) {
+	defer assert.PushTester(t)()
+
+	// the Ed25519 test key of ACA-Py
+	const (
+		fingerprint = "z6MkmjY8GnV5i9YTDtPETC2uUAW6ejw3nk5mXF5yci5ab7th"
+		verKey      = "8HH5gYEeNc3z7PYXmd54d4x6qAfCNrqQqEB3nS7Zfu7K"
+	)
+	codec, key, err := Decode(fingerprint)
+	assert.NoError(err)
+	assert.Equal(codec, Ed25519Pub)
+	assert.Equal(base58.Encode(key), verKey)
+	assert.Equal(Encode(codec, key), fingerprint)
+
+	_, _, err = Decode("6MkhaXgBZDvotDkL5257faiztiGiC2QtKLGpbnnEGta2doK")
+	assert.Error(err)
+	_, _, err = Decode("z0OIl")
+	assert.Error(err)
+}
@@ -0,0 +1,49 @@
+// Package verkey expands the abbreviated Indy verkeys. It's shared by the
+// crypto and ledger packages, which cannot import each other.
+package verkey
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/findy-network/findy-wrapper-go/internal/base58"
+)
+
+// AbbreviatedPrefix is the prefix of the abbreviated verkeys.
+const AbbreviatedPrefix = "~"
+
+// halfSize is the byte size of the DID and the abbreviated verkey, i.e. the
+// halves of the Ed25519 public key.
+const halfSize = 16
+
+// ErrInvalid is returned when the verkey cannot be expanded.
+var ErrInvalid = errors.New("verkey: cannot expand")
+
+// IsAbbreviated tells if the verkey is in the abbreviated form.
+func IsAbbreviated(verKey string) bool {
+	return strings.HasPrefix(verKey, AbbreviatedPrefix)
+}
+
+// fullSize is the byte size of the full verkey, i.e. the Ed25519 public key.
+const fullSize = 2 * halfSize
+
+// Expand returns the full verkey of the abbreviated verkey of the DID. Full
+// verkeys are checked and returned as they are. The DID must be unqualified.
+func Expand(did, verKey string) (string, error) {
+	if !IsAbbreviated(verKey) {
+		if b, err := base58.Decode(verKey); err != nil || len(b) != fullSize {
+			return "", fmt.Errorf("%w: verkey %q", ErrInvalid, verKey)
+		}
+		return verKey, nil
+	}
+	head, err := base58.Decode(did)
+	if err != nil || len(head) != halfSize {
+		return "", fmt.Errorf("%w: DID %q", ErrInvalid, did)
+	}
+	tail, err := base58.Decode(strings.TrimPrefix(verKey, AbbreviatedPrefix))
+	if err != nil || len(tail) != halfSize {
+		return "", fmt.Errorf("%w: verkey %q", ErrInvalid, verKey)
+	}
+	return base58.Encode(append(head, tail...)), nil
+}
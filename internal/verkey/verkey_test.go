@@ -0,0 +1,36 @@
+package verkey
+
+import (
+	"crypto/ed25519"
+	"errors"
+	"testing"
+
+	"github.com/findy-network/findy-wrapper-go/internal/base58"
+	"github.com/lainio/err2/assert"
+)
+
+func TestExpand(t *testing.T) {
+	defer assert.PushTester(t)()
+
+	pub := ed25519.NewKeyFromSeed([]byte("000000000000000000000000Steward1")).Public().(ed25519.PublicKey)
+	full := base58.Encode(pub)
+	did := base58.Encode(pub[:16])
+	abbr := AbbreviatedPrefix + base58.Encode(pub[16:])
+	assert.That(IsAbbreviated(abbr))
+	assert.ThatNot(IsAbbreviated(full))
+
+	got, err := Expand(did, abbr)
+	assert.NoError(err)
+	assert.Equal(got, full)
+
+	got, err = Expand("invalid", full)
+	assert.NoError(err)
+	assert.Equal(got, full)
+	_, err = Expand(did, did)
+	assert.That(errors.Is(err, ErrInvalid))
+
+	_, err = Expand("invalid", abbr)
+	assert.That(errors.Is(err, ErrInvalid))
+	_, err = Expand(did, "~abc")
+	assert.That(errors.Is(err, ErrInvalid))
+}
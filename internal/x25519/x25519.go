@@ -0,0 +1,67 @@
+// Package x25519 converts Ed25519 keys to X25519 keys like libsodium's
+// crypto_sign_ed25519_pk_to_curve25519 and crypto_sign_ed25519_sk_to_curve25519
+// do. libindy uses the conversion for the box encryption with the signing
+// keys.
+package x25519
+
+import (
+	"crypto/ed25519"
+	"crypto/sha512"
+	"errors"
+	"math/big"
+)
+
+// KeySize is the size of the X25519 keys.
+const KeySize = 32
+
+// p is the field prime 2^255 - 19.
+var p = new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), 255), big.NewInt(19))
+
+// ErrInvalidKey is returned when the Ed25519 public key isn't valid.
+var ErrInvalidKey = errors.New("x25519: invalid ed25519 public key")
+
+// PublicKey converts the Ed25519 public key to the X25519 public key with the
+// birational map u = (1 + y) / (1 - y).
+func PublicKey(pub ed25519.PublicKey) ([]byte, error) {
+	if len(pub) != ed25519.PublicKeySize {
+		return nil, ErrInvalidKey
+	}
+	le := make([]byte, KeySize)
+	copy(le, pub)
+	le[31] &= 0x7f // sign bit of x
+	y := new(big.Int).SetBytes(reverse(le))
+	if y.Cmp(p) >= 0 {
+		return nil, ErrInvalidKey
+	}
+
+	one := big.NewInt(1)
+	den := new(big.Int).Sub(one, y)
+	den.Mod(den, p)
+	if den.Sign() == 0 {
+		return nil, ErrInvalidKey
+	}
+	u := new(big.Int).Add(one, y)
+	u.Mul(u, den.ModInverse(den, p))
+	u.Mod(u, p)
+
+	out := make([]byte, KeySize)
+	u.FillBytes(out)
+	return reverse(out), nil
+}
+
+// PrivateKey converts the Ed25519 private key to the X25519 private key. It's
+// the clamped first half of the SHA-512 hash of the seed.
+func PrivateKey(priv ed25519.PrivateKey) []byte {
+	h := sha512.Sum512(priv.Seed())
+	h[0] &= 248
+	h[31] &= 127
+	h[31] |= 64
+	return h[:KeySize]
+}
+
+func reverse(b []byte) []byte {
+	for i, j := 0, len(b)-1; i < j; i, j = i+1, j-1 {
+		b[i], b[j] = b[j], b[i]
+	}
+	return b
+}
@@ -0,0 +1,33 @@
+package x25519
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"testing"
+
+	"github.com/lainio/err2/assert"
+	"golang.org/x/crypto/curve25519"
+)
+
+func TestKeys(t *testing.T) {
+	defer assert.PushTester(t)()
+
+	for _, seed := range []string{
+		"000000000000000000000000Steward1",
+		"00000000000000000000000000Alice1",
+		"\x01\x02\x03\x04\x05\x06\x07\x08\x09\x0a\x0b\x0c\x0d\x0e\x0f\x10" +
+			"\x11\x12\x13\x14\x15\x16\x17\x18\x19\x1a\x1b\x1c\x1d\x1e\x1f\x20",
+	} {
+		priv := ed25519.NewKeyFromSeed([]byte(seed))
+		pub, err := PublicKey(priv.Public().(ed25519.PublicKey))
+		assert.NoError(err)
+
+		// the converted public key must match the converted private key
+		want, err := curve25519.X25519(PrivateKey(priv), curve25519.Basepoint)
+		assert.NoError(err)
+		assert.That(bytes.Equal(pub, want))
+	}
+
+	_, err := PublicKey(make([]byte, 31))
+	assert.Error(err)
+}
@@ -154,3 +154,47 @@ func ParseGetCredDefResponse(credDefResp string) ctx.Channel {
 func BuildGetCredDefRequest(submitter, id string) ctx.Channel {
 	return c2go.FindyBuildGetCredDefRequest(submitter, id)
 }
+
+// BuildGetRevocRegDefRequest builds a GET_REVOC_REG_DEF request. Request to
+// get a revocation registry definition, that Issuer creates for a particular
+// Credential Definition.
+//
+// Note! You should us ReadRevRegDef instead.
+func BuildGetRevocRegDefRequest(submitter, id string) ctx.Channel {
+	return c2go.FindyBuildGetRevocRegDefRequest(submitter, id)
+}
+
+/*
+ParseGetRevocRegDefResponse is a libindy wrapper function. See more information
+from indy SDK documentation.
+
+The function parses the results from SubmitRequest. Note that in normal cases,
+you should not use it for leger communication. You should use the special
+transaction functions from this package (e.g. ReadRevRegDef, ReadRevReg,...).
+*/
+func ParseGetRevocRegDefResponse(response string) ctx.Channel {
+	return c2go.FindyParseGetRevocRegDefResponse(response)
+}
+
+// BuildGetRevocRegRequest builds a GET_REVOC_REG request. Request to get the
+// accumulated state of the revocation registry at the timestamp (seconds since
+// the epoch).
+//
+// Note! You should us ReadRevReg instead.
+func BuildGetRevocRegRequest(submitter, revRegDefID string, timestamp int64) ctx.Channel {
+	return c2go.FindyBuildGetRevocRegRequest(submitter, revRegDefID, timestamp)
+}
+
+/*
+ParseGetRevocRegResponse is a libindy wrapper function. See more information
+from indy SDK documentation.
+
+The function parses the results from SubmitRequest. The result has the rev reg
+def ID in Str1, the rev reg JSON in Str2, and its timestamp in Uint64. Note
+that in normal cases, you should not use it for leger communication. You
+should use the special transaction functions from this package (e.g.
+ReadRevReg).
+*/
+func ParseGetRevocRegResponse(response string) ctx.Channel {
+	return c2go.FindyParseGetRevocRegResponse(response)
+}
@@ -1,14 +1,20 @@
 package ledger
 
 import (
+	"fmt"
+
 	"github.com/findy-network/findy-wrapper-go/dto"
+	"github.com/findy-network/findy-wrapper-go/identifiers"
+	"github.com/findy-network/findy-wrapper-go/internal/verkey"
 	"github.com/findy-network/findy-wrapper-go/plugin"
 	"github.com/findy-network/findy-wrapper-go/pool"
 	"github.com/lainio/err2"
+	"github.com/lainio/err2/try"
 )
 
 // ReadCredDef reads cred def from ledgers by cred def ID. If multiple ledger
-// plugins is used, it returns where it can find data first.
+// plugins is used, it returns where it can find data first. The ID can be
+// qualified, but the returned ID is unqualified like the ledgers use them.
 func ReadCredDef(
 	_ int,
 	submitter,
@@ -23,7 +29,7 @@ func ReadCredDef(
 			TxType:       plugin.TxTypeCredDef,
 			SubmitterDID: submitter,
 		},
-		credDefID)
+		identifiers.Unqualify(credDefID))
 }
 
 // WriteCredDef writes cred def to ledger. If multiple ledger plugins is in use,
@@ -45,25 +51,80 @@ func WriteCredDef(
 		credDef)
 }
 
+// UpdateCredDef writes the updated cred def, e.g. a rotated one, to ledger with
+// update semantics, i.e. the cred def must already exist. If multiple ledger
+// plugins is in use, it writes data to all of them.
+func UpdateCredDef(
+	_,
+	wallet int,
+	submitter,
+	credDef string,
+) (err error) {
+	defer err2.Handle(&err, "update cred def")
+
+	return writePluginLedgers(
+		plugin.TxInfo{
+			TxType:       plugin.TxTypeCredDef,
+			Wallet:       wallet,
+			SubmitterDID: submitter,
+			Update:       true,
+		},
+		credDef)
+}
+
 func writePluginLedgers(tx plugin.TxInfo, data string) error {
 	var raw map[string]interface{}
 	dto.FromJSONStr(data, &raw)
-	dataID := raw["id"].(string)
+	dataID := identifiers.Unqualify(raw["id"].(string))
 	return pool.Write(tx, dataID, data)
 }
 
 // ReadSchema reads schema from ledgers by ID. If multiple ledger plugins is
-// used, it returns where it can find data first.
+// used, it returns where it can find data first. The ID can be qualified, but
+// the returned ID is unqualified like the ledgers use them.
 func ReadSchema(_ int, submitter, ID string) (sID, s string, err error) {
 	return pool.Read(
 		plugin.TxInfo{
 			TxType:       plugin.TxTypeSchema,
 			SubmitterDID: submitter,
 		},
-		ID,
+		identifiers.Unqualify(ID),
+	)
+}
+
+// ReadRevRegDef reads revocation registry definition from ledgers by ID. If
+// multiple ledger plugins is used, it returns where it can find data first.
+func ReadRevRegDef(_ int, submitter, ID string) (rrdID, rrd string, err error) {
+	return pool.Read(
+		plugin.TxInfo{
+			TxType:       plugin.TxTypeRevRegDef,
+			SubmitterDID: submitter,
+		},
+		identifiers.Unqualify(ID),
 	)
 }
 
+// ReadRevReg reads the state of the revocation registry at the timestamp from
+// ledgers. The timestamp is seconds since the epoch, e.g. the timestamp of the
+// proof identifiers. If multiple ledger plugins is used, it returns where it
+// can find data first.
+func ReadRevReg(
+	_ int,
+	submitter,
+	revRegDefID string,
+	timestamp int64,
+) (revReg string, err error) {
+	defer err2.Handle(&err, "read rev reg %s", revRegDefID)
+
+	_, revReg = try.To2(pool.Read(
+		plugin.TxInfo{
+			TxType:       plugin.TxTypeRevReg,
+			SubmitterDID: submitter,
+		},
+		plugin.RevRegStateID(identifiers.Unqualify(revRegDefID), timestamp)))
+	return revReg, nil
+}
+
 // WriteSchema writes schema to ledger. If multiple ledger plugins is in use, it
 // writes to all of them.
 func WriteSchema(
@@ -84,9 +145,8 @@ func WriteSchema(
 }
 
 // WriteDID writes DID to ledger. If multiple ledger plugins in in use, it
-// writes to all of them. Note! There are no ReadDID yet, because it is not used
-// explicitly. Some of the indy SDK functions read ledger implicitly like
-// did_get_key().
+// writes to all of them. The plugins get the submitter DID as the ID and the
+// target DID as the data, and the verkey in the TxInfo, see plugin.MapEntry.
 func WriteDID(
 	_,
 	wallet int,
@@ -98,7 +158,53 @@ func WriteDID(
 ) (err error) {
 	defer err2.Handle(&err)
 
-	return pool.Write(
+	return writeDID(pool.Write, wallet, submitterDID, targetDID, verKey, alias, role)
+}
+
+// WriteDIDPrimary writes DID like WriteDID, but only to the primary ledger
+// plugin, e.g. the Indy ledger when it's used with a cache. Use it with
+// WriteDIDSecondary when the primary ledger must accept the DID before
+// anything else is done, like in the key rotation.
+func WriteDIDPrimary(
+	_,
+	wallet int,
+	submitterDID,
+	targetDID,
+	verKey,
+	alias,
+	role string,
+) (err error) {
+	defer err2.Handle(&err, "primary ledger")
+
+	return writeDID(pool.WritePrimary, wallet, submitterDID, targetDID, verKey, alias, role)
+}
+
+// WriteDIDSecondary writes DID like WriteDID to all of the ledger plugins but
+// the primary one.
+func WriteDIDSecondary(
+	_,
+	wallet int,
+	submitterDID,
+	targetDID,
+	verKey,
+	alias,
+	role string,
+) (err error) {
+	defer err2.Handle(&err, "secondary ledgers")
+
+	return writeDID(pool.WriteSecondary, wallet, submitterDID, targetDID, verKey, alias, role)
+}
+
+func writeDID(
+	write func(tx plugin.TxInfo, ID, data string) error,
+	wallet int,
+	submitterDID,
+	targetDID,
+	verKey,
+	alias,
+	role string,
+) error {
+	return write(
 		plugin.TxInfo{
 			TxType:       plugin.TxTypeDID,
 			Wallet:       wallet,
@@ -107,5 +213,30 @@ func WriteDID(
 			Alias:        alias,
 			Role:         role,
 		},
-		submitterDID, targetDID)
+		submitterDID, identifiers.Unqualify(targetDID))
+}
+
+// ReadDID reads DID's verkey from ledgers. If multiple ledger plugins is used,
+// it returns where it can find data first. The DID can be qualified, and the
+// abbreviated verkeys are returned in their full form. The entries which the
+// earlier versions stored by the submitter DID have the target DID instead
+// of the verkey, and they are reported as plugin.ErrNotExist.
+func ReadDID(_ int, submitter, did string) (verKey string, err error) {
+	defer err2.Handle(&err, "read DID %s", did)
+
+	did = identifiers.Unqualify(did)
+	_, verKey = try.To2(pool.Read(
+		plugin.TxInfo{
+			TxType:       plugin.TxTypeDID,
+			SubmitterDID: submitter,
+		},
+		did))
+	if verKey == "" {
+		return "", plugin.ErrNotExist
+	}
+	full, err := verkey.Expand(did, verKey)
+	if err != nil {
+		return "", fmt.Errorf("%w: no verkey in the entry: %v", plugin.ErrNotExist, err)
+	}
+	return full, nil
 }
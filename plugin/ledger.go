@@ -1,7 +1,13 @@
-// Package plugin is an interface package for ledger addons.
+// Package plugin is an interface package for ledger addons and wallet storage
+// implementations.
 package plugin
 
-import "errors"
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
 
 // Plugin is a plugin interface for addon ledger implementations.
 type Plugin interface {
@@ -15,10 +21,13 @@ const (
 	TxTypeDID TxType = iota
 	TxTypeSchema
 	TxTypeCredDef
+	TxTypeRevRegDef
+	TxTypeRevReg
 )
 
 func (t TxType) String() string {
-	return []string{"TxTypeDID", "TxTypeSchema", "TxTypeCredDef"}[t]
+	return []string{"TxTypeDID", "TxTypeSchema", "TxTypeCredDef",
+		"TxTypeRevRegDef", "TxTypeRevReg"}[t]
 }
 
 type TxInfo struct {
@@ -42,15 +51,21 @@ func (ti TxInfo) String() string {
 }
 
 var (
-	TxDID     = TxInfo{TxType: TxTypeDID}
-	TxSchema  = TxInfo{TxType: TxTypeSchema}
-	TxCredDef = TxInfo{TxType: TxTypeCredDef}
+	TxDID       = TxInfo{TxType: TxTypeDID}
+	TxSchema    = TxInfo{TxType: TxTypeSchema}
+	TxCredDef   = TxInfo{TxType: TxTypeCredDef}
+	TxRevRegDef = TxInfo{TxType: TxTypeRevRegDef}
+	TxRevReg    = TxInfo{TxType: TxTypeRevReg}
 
 	ErrNotExist = errors.New("Ledger element doesn't exist")
 )
 
 // Mapper is an property getter/setter interface for addon ledger
-// implementations.
+// implementations. The ID of the TxTypeDID is the submitter DID, the data is
+// the target DID, and the verkey of the target is in TxInfo.VerKey. The
+// Mappers which serve ledger.ReadDID store the verkeys by the target DIDs,
+// see MapEntry. The ID of the TxTypeRevReg is built with RevRegStateID, because
+// the state of the revocation registry depends on the time.
 type Mapper interface {
 	Write(tx TxInfo, ID, data string) error
 
@@ -58,9 +73,41 @@ type Mapper interface {
 	Read(tx TxInfo, ID string) (string, string, error)
 }
 
+// MapEntry returns the key and the value which the Mapper stores for the
+// write. The TxTypeDID writes with the verkey are stored by the target DID
+// and its verkey, which ledger.ReadDID reads. The other writes are stored as
+// they are.
+func MapEntry(tx TxInfo, ID, data string) (key, value string) {
+	if tx.TxType == TxTypeDID && tx.VerKey != "" {
+		return data, tx.VerKey
+	}
+	return ID, data
+}
+
 // Ledger is a plugin interface used to offer implementations of addon ledgers.
 // See pool package for more information.
 type Ledger interface {
 	Plugin
 	Mapper
 }
+
+// RevRegStateID returns the ID of the TxTypeRevReg, which is the state of the
+// revocation registry at the timestamp. The state of the given time never
+// changes, which makes it cacheable like the other ledger elements.
+func RevRegStateID(revRegDefID string, timestamp int64) string {
+	return revRegDefID + "@" + strconv.FormatInt(timestamp, 10)
+}
+
+// SplitRevRegStateID returns the revocation registry definition ID and the
+// timestamp of the ID built with RevRegStateID.
+func SplitRevRegStateID(ID string) (revRegDefID string, timestamp int64, err error) {
+	i := strings.LastIndex(ID, "@")
+	if i < 0 {
+		return "", 0, fmt.Errorf("rev reg state ID %q has no timestamp", ID)
+	}
+	timestamp, err = strconv.ParseInt(ID[i+1:], 10, 64)
+	if err != nil {
+		return "", 0, fmt.Errorf("rev reg state ID %q: %w", ID, err)
+	}
+	return ID[:i], timestamp, nil
+}
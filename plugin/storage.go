@@ -0,0 +1,63 @@
+package plugin
+
+import (
+	"errors"
+
+	"github.com/findy-network/findy-wrapper-go/wql"
+)
+
+// Errors of the wallet storage implementations. They are mapped to the
+// corresponding libindy error codes, and the other errors are storage errors.
+var (
+	ErrWalletExists   = errors.New("wallet already exists")
+	ErrWalletNotFound = errors.New("wallet not found")
+	ErrRecordExists   = errors.New("wallet record already exists")
+	ErrRecordNotFound = errors.New("wallet record not found")
+)
+
+// StorageRecord is the record of the wallet storage. Libindy encrypts
+// everything before it's given to the storage: Type, ID and the tag names are
+// encrypted and encoded to strings, and Value is the encrypted bytes. The
+// values of the unencrypted (~) tags are plain text.
+type StorageRecord struct {
+	Type  string
+	ID    string
+	Value []byte
+	Tags  map[string]string
+}
+
+// Storage is a plugin interface for the wallet storage implementations. The
+// storages are registered with wallet.RegisterStorage, and the wallets are
+// stored to them when their wallet.Config has the StorageType. The config and
+// credentials are the storage_config and storage_credentials JSONs of the
+// wallet, and the metadata is libindy's encrypted wallet metadata.
+type Storage interface {
+	Create(name, config, credentials, metadata string) error
+	Open(name, config, credentials string) (StorageWallet, error)
+	Delete(name, config, credentials string) error
+}
+
+// StorageWallet is the opened wallet of the Storage. The functions follow the
+// ErrRecordNotFound and ErrRecordExists semantics. The calls come from
+// libindy's threads, and they can be concurrent.
+type StorageWallet interface {
+	Close() error
+
+	Add(r StorageRecord) error
+	UpdateValue(typ, id string, value []byte) error
+	UpdateTags(typ, id string, tags map[string]string) error
+	AddTags(typ, id string, tags map[string]string) error
+	DeleteTags(typ, id string, names []string) error
+	Delete(typ, id string) error
+	Get(typ, id string) (StorageRecord, error)
+
+	Metadata() (string, error)
+	SetMetadata(metadata string) error
+
+	// Search returns the records of the type whose tags match the query,
+	// see wql.Query.Match.
+	Search(typ string, query wql.Query) ([]StorageRecord, error)
+
+	// SearchAll returns all of the wallet's records, e.g. for the export.
+	SearchAll() ([]StorageRecord, error)
+}
@@ -190,15 +190,43 @@ func IsIndyLedgerOpen(handle int) bool {
 
 // Write writes data to all of the plugin ledgers, and waits their results.
 func Write(tx plugin.TxInfo, ID, data string) (err error) {
-	var wg sync.WaitGroup
-	for _, ledger := range openPlugins {
+	return writePlugins(tx, ID, data, func(int) bool { return true })
+}
+
+// WritePrimary writes data only to the primary plugin ledger, which is the
+// first opened one, e.g. the Indy ledger when it's used with a cache. Use it
+// with WriteSecondary when the primary ledger must accept the data before
+// anything else is done.
+func WritePrimary(tx plugin.TxInfo, ID, data string) (err error) {
+	return writePlugins(tx, ID, data, func(h int) bool { return h == primaryHandle })
+}
+
+// WriteSecondary writes data to all of the plugin ledgers but the primary one.
+func WriteSecondary(tx plugin.TxInfo, ID, data string) (err error) {
+	return writePlugins(tx, ID, data, func(h int) bool { return h != primaryHandle })
+}
+
+// primaryHandle is the handle of the first opened plugin.
+const primaryHandle = -1
+
+func writePlugins(tx plugin.TxInfo, ID, data string, selected func(h int) bool) (err error) {
+	var (
+		wg sync.WaitGroup
+		mu sync.Mutex
+	)
+	for h, ledger := range openPlugins {
+		if !selected(h) {
+			continue
+		}
 		l := ledger
 		wg.Add(1)
 		go func() {
 			defer wg.Done()
-			defer err2.Catch(func(er error) {
+			defer err2.Catch(func(er error) error {
 				glog.Errorln("-- writing err ledger:", tx, ID, "data:\n", data)
 				glog.Errorln("-- error:", er)
+				mu.Lock()
+				defer mu.Unlock()
 				if err != nil {
 					// if there was previous error, get them both
 					err = fmt.Errorf("plugin write error: %w: %w", err, er)
@@ -206,6 +234,7 @@ func Write(tx plugin.TxInfo, ID, data string) (err error) {
 					// other report just current error (er)
 					err = fmt.Errorf("plugin write error: %w", er)
 				}
+				return nil
 			})
 			try.To(l.Write(tx, ID, data))
 		}()
@@ -260,7 +289,7 @@ loop:
 			if readCount >= 2 && exist {
 				glog.V(5).Infoln("--- update cache plugin:", r1.id, r1.result)
 				tmpTx := tx
-				tx.Update = true
+				tmpTx.Update = true
 				err := openPlugins[cacheLedger].Write(tmpTx, ID, r1.result)
 				if err != nil {
 					glog.Errorln("error cache update", err)
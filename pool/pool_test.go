@@ -1,10 +1,12 @@
 package pool_test
 
 import (
+	"errors"
 	"reflect"
 	"testing"
 
 	_ "github.com/findy-network/findy-wrapper-go/addons"
+	"github.com/findy-network/findy-wrapper-go/plugin"
 	"github.com/findy-network/findy-wrapper-go/pool"
 	"github.com/lainio/err2/assert"
 )
@@ -124,3 +126,32 @@ func TestBuildLegacyPluginArgs(t *testing.T) {
 		assert.DeepEqual(tt.result, pools)
 	}
 }
+
+// failingLedger is a plugin ledger whose writes fail.
+type failingLedger struct{}
+
+func (failingLedger) Open(...string) bool { return true }
+func (failingLedger) Close()              {}
+
+func (failingLedger) Write(plugin.TxInfo, string, string) error {
+	return errors.New("write failed")
+}
+
+func (failingLedger) Read(plugin.TxInfo, string) (string, string, error) {
+	return "", "", plugin.ErrNotExist
+}
+
+func TestWritePrimary(t *testing.T) {
+	assert.PushTester(t)
+	defer assert.PopTester()
+
+	pool.RegisterPlugin("TEST_FAILING_LEDGER", failingLedger{})
+	<-pool.CloseLedger(0)
+	r := <-pool.OpenLedger("FINDY_MEM_LEDGER", "", "TEST_FAILING_LEDGER", "")
+	assert.NoError(r.Err())
+	defer func() { <-pool.CloseLedger(r.Handle()) }()
+
+	assert.NoError(pool.WritePrimary(plugin.TxDID, "did1", "verkey1"))
+	assert.Error(pool.WriteSecondary(plugin.TxDID, "did1", "verkey1"))
+	assert.Error(pool.Write(plugin.TxDID, "did1", "verkey1"))
+}
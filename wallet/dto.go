@@ -12,7 +12,8 @@ type Config struct {
 	ID string `json:"id"`
 
 	// StorageType is optional, only use if indy_register_wallet_storage() is
-	// called. 'Default' value is for local files sytem wallets.
+	// called, e.g. by RegisterStorage. 'Default' value is for local files
+	// sytem wallets.
 	StorageType string `json:"storage_type,omitempty"`
 
 	// StorageConfig is optional, use when the wallet root path needs to be set.
@@ -36,4 +37,16 @@ type Credentials struct {
 	//  RAW - raw wallet key master provided (skip derivation)
 	//        RAW keys can be generated with indy_generate_wallet_key call
 	KeyDerivationMethod string `json:"key_derivation_method,omitempty"`
+
+	// ReKey with method are the new credentials. Open changes the wallet key
+	// to them. Use Rekey to check the new key and to roll back on failure.
+	ReKey                 string `json:"rekey,omitempty"`
+	ReKeyDerivationMethod string `json:"rekey_derivation_method,omitempty"`
 }
+
+// Key derivation methods of the Credentials.
+const (
+	KeyDerivationArgon2iMod = "ARGON2I_MOD"
+	KeyDerivationArgon2iInt = "ARGON2I_INT"
+	KeyDerivationRaw        = "RAW"
+)
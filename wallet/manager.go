@@ -0,0 +1,353 @@
+package wallet
+
+import (
+	"crypto/sha256"
+	"crypto/subtle"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/findy-network/findy-wrapper-go/dto"
+	"github.com/golang/glog"
+	"github.com/lainio/err2"
+)
+
+// Errors of the Manager.
+var (
+	ErrManagerClosed = errors.New("wallet manager is closed")
+	ErrTooManyOpen   = errors.New("too many wallets in use")
+	ErrCredentials   = errors.New("wallet is open with other credentials")
+)
+
+// minIdleInterval is the minimum interval of the idle checks, which keeps
+// the short IdleTTLs from busy looping.
+const minIdleInterval = 10 * time.Millisecond
+
+// ManagerConfig is the configuration of the Manager.
+type ManagerConfig struct {
+	// IdleTTL is the time after the unused wallets are closed. Zero keeps
+	// them open until they are evicted or the Manager is closed.
+	IdleTTL time.Duration
+
+	// MaxOpen is the maximum count of the open wallets. When it's reached,
+	// the least recently used wallet which isn't in use is closed. Zero is
+	// unlimited.
+	MaxOpen int
+}
+
+// ManagerStats are the statistics of the Manager.
+type ManagerStats struct {
+	Open        int    // count of the open wallets
+	InUse       int    // count of the wallets which have leases
+	Hits        uint64 // Open calls served by an open wallet
+	Misses      uint64 // Open calls which opened the wallet
+	Evicted     uint64 // wallets closed by MaxOpen
+	IdleClosed  uint64 // wallets closed by IdleTTL
+	OpenErrors  uint64
+	CloseErrors uint64
+}
+
+// Manager pools the wallet handles. Opening a wallet derives its key, which
+// is slow by design, and the Manager keeps the wallets open and shares their
+// handles between the goroutines:
+//
+//	m := wallet.NewManager(wallet.ManagerConfig{IdleTTL: 10 * time.Minute, MaxOpen: 1000})
+//	defer m.Close()
+//	...
+//	lease, err := m.Open(config, credentials)
+//	defer lease.Release()
+//	r := <-did.CreateAndStore(lease.Handle(), did.Did{})
+//
+// The wallets are reference counted by the leases, and only the wallets
+// without leases are closed by IdleTTL and MaxOpen.
+type Manager struct {
+	cfg ManagerConfig
+
+	mu      sync.Mutex
+	wallets map[string]*managed
+	closing map[string]*managed
+	stats   ManagerStats
+	closed  bool
+	done    chan struct{}
+
+	// these are replaced by the tests
+	open  func(config Config, credentials Credentials) (int, error)
+	close func(handle int) error
+	now   func() time.Time
+}
+
+// managed is the wallet of the Manager. Ready is closed when the wallet is
+// opened, and err tells if it failed. Closed is closed when the removed
+// wallet is closed.
+type managed struct {
+	key      string
+	creds    [sha256.Size]byte
+	handle   int
+	refs     int
+	lastUsed time.Time
+	ready    chan struct{}
+	err      error
+	closed   chan struct{}
+}
+
+// Lease is the use of the open wallet. The handle must not be used after
+// Release.
+type Lease struct {
+	m    *Manager
+	w    *managed
+	once sync.Once
+}
+
+// NewManager creates the Manager. If IdleTTL is set, it starts the
+// goroutine which closes the idle wallets until the Manager is closed.
+func NewManager(cfg ManagerConfig) *Manager {
+	m := &Manager{
+		cfg:     cfg,
+		wallets: make(map[string]*managed),
+		closing: make(map[string]*managed),
+		done:    make(chan struct{}),
+		open:    openHandle,
+		close:   closeHandle,
+		now:     time.Now,
+	}
+	if cfg.IdleTTL > 0 {
+		go m.closeIdleLoop(idleInterval(cfg.IdleTTL))
+	}
+	return m
+}
+
+// idleInterval returns the interval of the idle checks for the IdleTTL.
+func idleInterval(ttl time.Duration) time.Duration {
+	if ttl/2 < minIdleInterval {
+		return minIdleInterval
+	}
+	return ttl / 2
+}
+
+func openHandle(config Config, credentials Credentials) (int, error) {
+	r := <-Open(config, credentials)
+	return r.Handle(), r.Err()
+}
+
+func closeHandle(handle int) error {
+	return (<-Close(handle)).Err()
+}
+
+// Open returns the Lease of the wallet, and opens the wallet if it isn't
+// open yet. The concurrent calls of the same wallet open it once. The open
+// wallet is shared only with the same credentials.
+func (m *Manager) Open(config Config, credentials Credentials) (l *Lease, err error) {
+	defer err2.Handle(&err, "wallet manager: open %s", config.ID)
+
+	key := managerKey(config)
+	creds := sha256.Sum256([]byte(dto.ToJSON(credentials)))
+
+	m.mu.Lock()
+	if m.closed {
+		m.mu.Unlock()
+		return nil, ErrManagerClosed
+	}
+	if w, ok := m.wallets[key]; ok {
+		if subtle.ConstantTimeCompare(w.creds[:], creds[:]) != 1 {
+			m.mu.Unlock()
+			return nil, ErrCredentials
+		}
+		w.refs++
+		m.stats.Hits++
+		m.mu.Unlock()
+		<-w.ready
+		if w.err != nil {
+			return nil, w.err
+		}
+		return &Lease{m: m, w: w}, nil
+	}
+	victim, err := m.evict()
+	if err != nil {
+		m.mu.Unlock()
+		return nil, err
+	}
+	w := &managed{key: key, creds: creds, refs: 1, ready: make(chan struct{})}
+	m.wallets[key] = w
+	m.stats.Misses++
+	pending := m.closing[key]
+	m.mu.Unlock()
+
+	if victim != nil {
+		_ = m.closeWallet(victim)
+	}
+	if pending != nil {
+		// libindy cannot open the wallet before its previous handle is closed
+		<-pending.closed
+	}
+	return m.openWallet(w, config, credentials)
+}
+
+func (m *Manager) openWallet(w *managed, config Config, credentials Credentials) (*Lease, error) {
+	handle, err := m.open(config, credentials)
+
+	m.mu.Lock()
+	w.handle, w.err = handle, err
+	switch {
+	case err != nil:
+		delete(m.wallets, w.key)
+		m.stats.OpenErrors++
+	case m.closed:
+		w.err = ErrManagerClosed
+		m.remove(w)
+	}
+	close(w.ready)
+	m.mu.Unlock()
+
+	if err == nil && w.err != nil {
+		_ = m.closeWallet(w)
+	}
+	if w.err != nil {
+		return nil, w.err
+	}
+	return &Lease{m: m, w: w}, nil
+}
+
+// evict removes the least recently used wallet without leases if MaxOpen is
+// reached. The caller closes it. The Manager must be locked.
+func (m *Manager) evict() (*managed, error) {
+	if m.cfg.MaxOpen <= 0 || len(m.wallets) < m.cfg.MaxOpen {
+		return nil, nil
+	}
+	var victim *managed
+	for _, w := range m.wallets {
+		if w.refs == 0 && (victim == nil || w.lastUsed.Before(victim.lastUsed)) {
+			victim = w
+		}
+	}
+	if victim == nil {
+		return nil, ErrTooManyOpen
+	}
+	m.remove(victim)
+	m.stats.Evicted++
+	return victim, nil
+}
+
+// remove removes the open wallet, which the caller closes with
+// closeWallet. The Manager must be locked.
+func (m *Manager) remove(w *managed) {
+	delete(m.wallets, w.key)
+	w.closed = make(chan struct{})
+	m.closing[w.key] = w
+}
+
+func (m *Manager) closeWallet(w *managed) error {
+	err := m.close(w.handle)
+
+	m.mu.Lock()
+	if err != nil {
+		glog.Errorln("wallet manager: close:", err)
+		m.stats.CloseErrors++
+	}
+	if m.closing[w.key] == w {
+		delete(m.closing, w.key)
+	}
+	m.mu.Unlock()
+	close(w.closed)
+	return err
+}
+
+func (m *Manager) closeIdleLoop(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			m.closeIdle()
+		case <-m.done:
+			return
+		}
+	}
+}
+
+// closeIdle closes the wallets without leases which are unused for
+// IdleTTL.
+func (m *Manager) closeIdle() {
+	m.mu.Lock()
+	var idle []*managed
+	for _, w := range m.wallets {
+		if w.refs == 0 && m.now().Sub(w.lastUsed) >= m.cfg.IdleTTL {
+			m.remove(w)
+			idle = append(idle, w)
+		}
+	}
+	m.stats.IdleClosed += uint64(len(idle))
+	m.mu.Unlock()
+
+	for _, w := range idle {
+		_ = m.closeWallet(w)
+	}
+}
+
+// Stats returns the current statistics.
+func (m *Manager) Stats() ManagerStats {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	stats := m.stats
+	stats.Open = len(m.wallets)
+	for _, w := range m.wallets {
+		if w.refs > 0 {
+			stats.InUse++
+		}
+	}
+	return stats
+}
+
+// Close closes all of the wallets, also the ones which are in use, and
+// stops the Manager. The wallets which are being opened are closed when
+// they are ready.
+func (m *Manager) Close() error {
+	m.mu.Lock()
+	if m.closed {
+		m.mu.Unlock()
+		return nil
+	}
+	m.closed = true
+	close(m.done)
+	var open []*managed
+	for _, w := range m.wallets {
+		select {
+		case <-w.ready:
+			m.remove(w)
+			open = append(open, w)
+		default:
+		}
+	}
+	m.mu.Unlock()
+
+	var errs []error
+	for _, w := range open {
+		if err := m.closeWallet(w); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Handle returns the wallet handle.
+func (l *Lease) Handle() int {
+	return l.w.handle
+}
+
+// Release ends the use of the wallet. It's safe to call it many times.
+func (l *Lease) Release() {
+	l.once.Do(func() {
+		l.m.mu.Lock()
+		defer l.m.mu.Unlock()
+		l.w.refs--
+		l.w.lastUsed = l.m.now()
+	})
+}
+
+func managerKey(config Config) string {
+	key := config.ID + "|" + config.StorageType
+	if config.StorageConfig != nil {
+		key += "|" + config.Path
+	}
+	return key
+}
@@ -0,0 +1,189 @@
+package wallet
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/lainio/err2/assert"
+)
+
+// fakeWallets replaces libindy's open and close of the Manager.
+type fakeWallets struct {
+	sync.Mutex
+	handle int
+	opens  int
+	open   map[int]bool
+	now    time.Time
+}
+
+func newTestManager(cfg ManagerConfig) (*Manager, *fakeWallets) {
+	f := &fakeWallets{open: make(map[int]bool), now: time.Unix(1700000000, 0)}
+	m := NewManager(cfg)
+	m.open = func(config Config, credentials Credentials) (int, error) {
+		if config.ID == "broken" {
+			return 0, errors.New("cannot open")
+		}
+		time.Sleep(time.Millisecond)
+		f.Lock()
+		defer f.Unlock()
+		f.handle++
+		f.opens++
+		f.open[f.handle] = true
+		return f.handle, nil
+	}
+	m.close = func(handle int) error {
+		f.Lock()
+		defer f.Unlock()
+		delete(f.open, handle)
+		return nil
+	}
+	m.now = func() time.Time {
+		f.Lock()
+		defer f.Unlock()
+		return f.now
+	}
+	return m, f
+}
+
+func (f *fakeWallets) advance(d time.Duration) {
+	f.Lock()
+	defer f.Unlock()
+	f.now = f.now.Add(d)
+}
+
+func (f *fakeWallets) count() (opens, open int) {
+	f.Lock()
+	defer f.Unlock()
+	return f.opens, len(f.open)
+}
+
+var testKey = Credentials{Key: "6cih1cVgRH8yHD54nEYyPKLmdv67o8QbufxaTHot3Qxp", KeyDerivationMethod: "RAW"}
+
+func TestManager_Open(t *testing.T) {
+	defer assert.PushTester(t)()
+
+	m, f := newTestManager(ManagerConfig{})
+
+	var wg sync.WaitGroup
+	leases := make([]*Lease, 10)
+	for i := range leases {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			l, err := m.Open(Config{ID: "w1"}, testKey)
+			assert.NoError(err)
+			leases[i] = l
+		}(i)
+	}
+	wg.Wait()
+	opens, _ := f.count()
+	assert.Equal(opens, 1)
+	for _, l := range leases {
+		assert.Equal(l.Handle(), leases[0].Handle())
+	}
+	stats := m.Stats()
+	assert.Equal(stats.Open, 1)
+	assert.Equal(stats.InUse, 1)
+	assert.Equal(stats.Misses, uint64(1))
+	assert.Equal(stats.Hits, uint64(9))
+
+	_, err := m.Open(Config{ID: "w1"}, Credentials{Key: "other"})
+	assert.That(errors.Is(err, ErrCredentials))
+	_, err = m.Open(Config{ID: "broken"}, testKey)
+	assert.Error(err)
+	assert.Equal(m.Stats().OpenErrors, uint64(1))
+
+	for _, l := range leases {
+		l.Release()
+		l.Release()
+	}
+	assert.Equal(m.Stats().InUse, 0)
+
+	assert.NoError(m.Close())
+	_, open := f.count()
+	assert.Equal(open, 0)
+	_, err = m.Open(Config{ID: "w1"}, testKey)
+	assert.That(errors.Is(err, ErrManagerClosed))
+}
+
+func TestManager_MaxOpen(t *testing.T) {
+	defer assert.PushTester(t)()
+
+	m, f := newTestManager(ManagerConfig{MaxOpen: 2})
+	defer m.Close()
+
+	l1, err := m.Open(Config{ID: "w1"}, testKey)
+	assert.NoError(err)
+	l2, err := m.Open(Config{ID: "w2"}, testKey)
+	assert.NoError(err)
+	_, err = m.Open(Config{ID: "w3"}, testKey)
+	assert.That(errors.Is(err, ErrTooManyOpen))
+
+	l2.Release()
+	f.advance(time.Second)
+	l1.Release()
+	l3, err := m.Open(Config{ID: "w3"}, testKey)
+	assert.NoError(err)
+	defer l3.Release()
+
+	// w2 was the least recently used
+	stats := m.Stats()
+	assert.Equal(stats.Evicted, uint64(1))
+	assert.Equal(stats.Open, 2)
+	l1, err = m.Open(Config{ID: "w1"}, testKey)
+	assert.NoError(err)
+	l1.Release()
+	assert.Equal(m.Stats().Hits, uint64(1))
+	_, open := f.count()
+	assert.Equal(open, 2)
+}
+
+func TestManager_idleInterval(t *testing.T) {
+	defer assert.PushTester(t)()
+
+	assert.Equal(idleInterval(time.Nanosecond), minIdleInterval)
+	assert.Equal(idleInterval(time.Hour), 30*time.Minute)
+
+	// the ticker of the tiny IdleTTL must not panic
+	m := NewManager(ManagerConfig{IdleTTL: time.Nanosecond})
+	assert.NoError(m.Close())
+}
+
+func TestManager_IdleTTL(t *testing.T) {
+	defer assert.PushTester(t)()
+
+	m, f := newTestManager(ManagerConfig{IdleTTL: time.Hour})
+	defer m.Close()
+
+	l1, err := m.Open(Config{ID: "w1"}, testKey)
+	assert.NoError(err)
+	l2, err := m.Open(Config{ID: "w2"}, testKey)
+	assert.NoError(err)
+	l1.Release()
+
+	f.advance(time.Hour)
+	m.closeIdle()
+	stats := m.Stats()
+	assert.Equal(stats.IdleClosed, uint64(1))
+	assert.Equal(stats.Open, 1)
+
+	// the leased wallet isn't closed
+	f.advance(time.Hour)
+	m.closeIdle()
+	assert.Equal(m.Stats().Open, 1)
+	l2.Release()
+	m.closeIdle()
+	assert.Equal(m.Stats().Open, 1)
+	f.advance(time.Hour)
+	m.closeIdle()
+	assert.Equal(m.Stats().Open, 0)
+
+	l1, err = m.Open(Config{ID: "w1"}, testKey)
+	assert.NoError(err)
+	l1.Release()
+	opens, open := f.count()
+	assert.Equal(opens, 3)
+	assert.Equal(open, 1)
+}
@@ -0,0 +1,137 @@
+/*
+Package records is the Go package for libindy's non-secrets namespace, i.e. the
+application's own records in the encrypted wallet. The records have a type, an
+ID, a value and tags. The tags whose names start with ~ are unencrypted, and
+only they can be compared with $gt, $lt and $like in the WQL searches.
+
+The wrapper functions return the Channel like the rest of the packages. The
+typed helpers Get and Find, and the Search iterator wrap them:
+
+	r := <-records.Add(w, records.Record{Type: "connection", ID: id, Value: v,
+		Tags: records.Tags{"their_did": theirDID, "~created": "1700000000"}})
+	...
+	s, err := records.Open(w, "connection", wql.Gt("~created", "1690000000"))
+	for s.Next() {
+		rec := s.Record()
+	}
+	if err := s.Err(); err != nil {
+
+The search closes its handle when it's exhausted or fails. Call Close when the
+iteration is stopped earlier.
+*/
+package records
+
+import (
+	"encoding/json"
+	"errors"
+
+	"github.com/findy-network/findy-wrapper-go/dto"
+	"github.com/findy-network/findy-wrapper-go/internal/c2go"
+	"github.com/findy-network/findy-wrapper-go/internal/ctx"
+	"github.com/lainio/err2"
+	"github.com/lainio/err2/try"
+)
+
+// Indy error codes of the record functions.
+const (
+	ErrCodeItemNotFound      = 212
+	ErrCodeItemAlreadyExists = 213
+)
+
+// Tags are the tags of the record. The names starting with ~ are unencrypted
+// tags.
+type Tags map[string]string
+
+// Record is the non-secret wallet record. Type, Value and Tags are empty if
+// they aren't retrieved, see Options.
+type Record struct {
+	Type  string `json:"type,omitempty"`
+	ID    string `json:"id"`
+	Value string `json:"value,omitempty"`
+	Tags  Tags   `json:"tags,omitempty"`
+}
+
+// Options tells what is retrieved to the records by GetRecord.
+type Options struct {
+	RetrieveType  bool `json:"retrieveType"`
+	RetrieveValue bool `json:"retrieveValue"`
+	RetrieveTags  bool `json:"retrieveTags"`
+}
+
+// AllOptions retrieve everything.
+var AllOptions = Options{RetrieveType: true, RetrieveValue: true, RetrieveTags: true}
+
+// Add adds the record to the wallet. The ID must be unique in its type.
+func Add(wallet int, r Record) ctx.Channel {
+	return c2go.FindyAddWalletRecord(wallet, r.Type, r.ID, r.Value, tagsJSON(r.Tags))
+}
+
+// UpdateValue replaces the value of the record.
+func UpdateValue(wallet int, typ, id, value string) ctx.Channel {
+	return c2go.FindyUpdateWalletRecordValue(wallet, typ, id, value)
+}
+
+// UpdateTags replaces all of the tags of the record.
+func UpdateTags(wallet int, typ, id string, tags Tags) ctx.Channel {
+	return c2go.FindyUpdateWalletRecordTags(wallet, typ, id, tagsJSON(tags))
+}
+
+// AddTags adds the tags to the record. The existing tags with the same names
+// are replaced.
+func AddTags(wallet int, typ, id string, tags Tags) ctx.Channel {
+	return c2go.FindyAddWalletRecordTags(wallet, typ, id, tagsJSON(tags))
+}
+
+// DeleteTags deletes the tags of the names from the record.
+func DeleteTags(wallet int, typ, id string, names ...string) ctx.Channel {
+	if names == nil {
+		names = []string{}
+	}
+	return c2go.FindyDeleteWalletRecordTags(wallet, typ, id, dto.ToJSON(names))
+}
+
+// Delete deletes the record.
+func Delete(wallet int, typ, id string) ctx.Channel {
+	return c2go.FindyDeleteWalletRecord(wallet, typ, id)
+}
+
+// GetRecord returns the record JSON in Str1 with the retrieve options.
+func GetRecord(wallet int, typ, id string, options Options) ctx.Channel {
+	return c2go.FindyGetWalletRecord(wallet, typ, id, dto.ToJSON(options))
+}
+
+// Get returns the record with its type, value and tags.
+func Get(wallet int, typ, id string) (rec *Record, err error) {
+	defer err2.Handle(&err, "get record %s/%s", typ, id)
+
+	r := <-GetRecord(wallet, typ, id, AllOptions)
+	try.To(r.Err())
+	rec = new(Record)
+	try.To(json.Unmarshal([]byte(r.Str1()), rec))
+	return rec, nil
+}
+
+// IsNotFound tells if the error is libindy's WalletItemNotFound.
+func IsNotFound(err error) bool {
+	return errCode(err) == ErrCodeItemNotFound
+}
+
+// IsAlreadyExists tells if the error is libindy's WalletItemAlreadyExists.
+func IsAlreadyExists(err error) bool {
+	return errCode(err) == ErrCodeItemAlreadyExists
+}
+
+func errCode(err error) int {
+	var r dto.Result
+	if errors.As(err, &r) {
+		return r.ErrCode()
+	}
+	return 0
+}
+
+func tagsJSON(tags Tags) string {
+	if tags == nil {
+		return "{}"
+	}
+	return dto.ToJSON(tags)
+}
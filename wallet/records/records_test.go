@@ -0,0 +1,83 @@
+package records
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/lainio/err2/assert"
+
+	"github.com/findy-network/findy-wrapper-go/dto"
+	"github.com/findy-network/findy-wrapper-go/helpers"
+	"github.com/findy-network/findy-wrapper-go/wql"
+)
+
+func TestIsNotFound(t *testing.T) {
+	defer assert.PushTester(t)()
+
+	var r dto.Result
+	r.SetErr(fmt.Errorf("WalletItemNotFound"))
+	r.SetErrCode(ErrCodeItemNotFound)
+	assert.That(IsNotFound(fmt.Errorf("get record: %w", r)))
+	assert.ThatNot(IsAlreadyExists(r))
+	assert.ThatNot(IsNotFound(fmt.Errorf("other")))
+}
+
+func TestRecords(t *testing.T) {
+	defer assert.PushTester(t)()
+
+	w, name := helpers.CreateAndOpenTestWallet(t)
+	const typ = "connection"
+
+	for i := 0; i < 7; i++ {
+		r := <-Add(w, Record{Type: typ, ID: fmt.Sprint("id", i), Value: fmt.Sprint("value", i),
+			Tags: Tags{"state": "ready", "~index": fmt.Sprint(i)}})
+		assert.NoError(r.Err())
+	}
+	r := <-Add(w, Record{Type: typ, ID: "id0"})
+	assert.That(IsAlreadyExists(r.Err()))
+
+	rec, err := Get(w, typ, "id3")
+	assert.NoError(err)
+	assert.Equal(rec.Value, "value3")
+	assert.Equal(rec.Tags["~index"], "3")
+
+	r = <-UpdateValue(w, typ, "id3", "updated")
+	assert.NoError(r.Err())
+	r = <-AddTags(w, typ, "id3", Tags{"state": "done"})
+	assert.NoError(r.Err())
+	r = <-DeleteTags(w, typ, "id4", "state")
+	assert.NoError(r.Err())
+	rec, err = Get(w, typ, "id3")
+	assert.NoError(err)
+	assert.Equal(rec.Value, "updated")
+	assert.Equal(rec.Tags["state"], "done")
+
+	recs, err := Find(w, typ, wql.Eq("state", "ready"))
+	assert.NoError(err)
+	assert.SLen(recs, 5)
+
+	s, err := Open(w, typ, wql.Gte("~index", "2"))
+	assert.NoError(err)
+	s.BatchSize = 2
+	count := 0
+	for s.Next() {
+		count++
+	}
+	assert.NoError(s.Err())
+	assert.Equal(count, 5)
+	assert.Equal(s.TotalCount(), 5)
+	assert.NoError(s.Close())
+
+	r = <-UpdateTags(w, typ, "id5", Tags{})
+	assert.NoError(r.Err())
+	r = <-Delete(w, typ, "id6")
+	assert.NoError(r.Err())
+	_, err = Get(w, typ, "id6")
+	assert.That(IsNotFound(err))
+
+	recs, err = Find(w, typ, wql.Query{})
+	assert.NoError(err)
+	assert.SLen(recs, 6)
+
+	helpers.CloseAndDeleteTestWallet(w, name, t)
+}
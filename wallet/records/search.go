@@ -0,0 +1,173 @@
+package records
+
+import (
+	"encoding/json"
+	"errors"
+
+	"github.com/findy-network/findy-wrapper-go/dto"
+	"github.com/findy-network/findy-wrapper-go/internal/c2go"
+	"github.com/findy-network/findy-wrapper-go/internal/ctx"
+	"github.com/findy-network/findy-wrapper-go/wql"
+	"github.com/lainio/err2"
+	"github.com/lainio/err2/try"
+)
+
+// DefaultBatchSize is the count of the records fetched at once by Search.
+const DefaultBatchSize = 50
+
+// SearchOptions tells what the search retrieves.
+type SearchOptions struct {
+	RetrieveRecords    bool `json:"retrieveRecords"`
+	RetrieveTotalCount bool `json:"retrieveTotalCount"`
+	RetrieveType       bool `json:"retrieveType"`
+	RetrieveValue      bool `json:"retrieveValue"`
+	RetrieveTags       bool `json:"retrieveTags"`
+}
+
+// AllSearchOptions retrieve everything.
+var AllSearchOptions = SearchOptions{
+	RetrieveRecords:    true,
+	RetrieveTotalCount: true,
+	RetrieveType:       true,
+	RetrieveValue:      true,
+	RetrieveTags:       true,
+}
+
+// OpenSearch opens the search of the records of the type. The search handle
+// is returned in Handle.
+func OpenSearch(wallet int, typ string, query wql.Query, options SearchOptions) ctx.Channel {
+	return c2go.FindyOpenWalletSearch(wallet, typ, query.String(), dto.ToJSON(options))
+}
+
+// FetchNext fetches the next count records of the search. The result JSON,
+// i.e. the total count and the records, is returned in Str1.
+func FetchNext(wallet, searchHandle, count int) ctx.Channel {
+	return c2go.FindyFetchWalletSearchNextRecords(wallet, searchHandle, count)
+}
+
+// CloseSearch closes the search handle.
+func CloseSearch(searchHandle int) ctx.Channel {
+	return c2go.FindyCloseWalletSearch(searchHandle)
+}
+
+// Search is the iterator of the records matching the WQL query. It closes
+// the search handle when the records are exhausted or the fetch fails.
+type Search struct {
+	// BatchSize is the count of the records fetched at once.
+	BatchSize int
+
+	wallet     int
+	handle     int
+	open       bool
+	totalCount int
+	batch      []Record
+	current    Record
+	err        error
+}
+
+type fetchResult struct {
+	TotalCount *int     `json:"totalCount"`
+	Records    []Record `json:"records"`
+}
+
+// Open opens the Search of the records of the type. The records have their
+// type, value and tags.
+func Open(wallet int, typ string, query wql.Query) (s *Search, err error) {
+	defer err2.Handle(&err, "open search %s", typ)
+
+	try.To(query.Validate())
+	r := <-OpenSearch(wallet, typ, query, AllSearchOptions)
+	try.To(r.Err())
+	return &Search{
+		BatchSize:  DefaultBatchSize,
+		wallet:     wallet,
+		handle:     r.Handle(),
+		open:       true,
+		totalCount: -1,
+	}, nil
+}
+
+// Next moves to the next record and tells if there was one. It fetches the
+// next batch of the records when needed.
+func (s *Search) Next() bool {
+	if len(s.batch) == 0 && s.open {
+		s.fetch()
+	}
+	if len(s.batch) == 0 {
+		return false
+	}
+	s.current, s.batch = s.batch[0], s.batch[1:]
+	return true
+}
+
+// Record returns the current record.
+func (s *Search) Record() Record {
+	return s.current
+}
+
+// TotalCount returns the total count of the matching records, or -1 before
+// the first Next.
+func (s *Search) TotalCount() int {
+	return s.totalCount
+}
+
+// Err returns the error of the search.
+func (s *Search) Err() error {
+	return s.err
+}
+
+// Close closes the search handle if it's still open. It's safe to call it
+// many times.
+func (s *Search) Close() error {
+	if !s.open {
+		return nil
+	}
+	s.open = false
+	s.batch = nil
+	r := <-CloseSearch(s.handle)
+	return r.Err()
+}
+
+func (s *Search) fetch() {
+	count := s.BatchSize
+	if count <= 0 {
+		count = DefaultBatchSize
+	}
+	r := <-FetchNext(s.wallet, s.handle, count)
+	if r.Err() != nil {
+		s.fail(r.Err())
+		return
+	}
+	var res fetchResult
+	if err := json.Unmarshal([]byte(r.Str1()), &res); err != nil {
+		s.fail(err)
+		return
+	}
+	if res.TotalCount != nil {
+		s.totalCount = *res.TotalCount
+	}
+	s.batch = res.Records
+	if len(res.Records) < count {
+		s.err = s.Close()
+		s.batch = res.Records
+	}
+}
+
+func (s *Search) fail(err error) {
+	s.err = err
+	if closeErr := s.Close(); closeErr != nil {
+		s.err = errors.Join(err, closeErr)
+	}
+}
+
+// Find returns all of the records of the type which match the query.
+func Find(wallet int, typ string, query wql.Query) (recs []Record, err error) {
+	s, err := Open(wallet, typ, query)
+	if err != nil {
+		return nil, err
+	}
+	for s.Next() {
+		recs = append(recs, s.Record())
+	}
+	return recs, s.Err()
+}
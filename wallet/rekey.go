@@ -0,0 +1,153 @@
+package wallet
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/lainio/err2"
+	"github.com/lainio/err2/try"
+)
+
+// ErrRolledBack is returned when the rekey failed and the wallet was restored
+// from its backup with the old credentials.
+var ErrRolledBack = errors.New("wallet rekey rolled back")
+
+// Rekey changes the wallet key from the old credentials to the new ones,
+// e.g. from ARGON2I_MOD to the RAW key of NewRawCredentials. The wallet must
+// be closed, i.e. it cannot be in use, e.g. by the Manager.
+//
+// The wallet is exported to a temporary backup with the old key first, and
+// the key is changed with libindy's rekey on open. If the wallet cannot be
+// opened with the new credentials after that, it's restored from the backup,
+// and the error is ErrRolledBack. If the restore fails too, the backup file
+// is kept, and its path is in the error.
+func Rekey(config Config, oldCreds, newCreds Credentials) (err error) {
+	defer err2.Handle(&err, "rekey wallet %s", config.ID)
+
+	dir := try.To1(os.MkdirTemp("", "findy_rekey_"))
+	keepBackup := false
+	defer func() {
+		if !keepBackup {
+			os.RemoveAll(dir)
+		}
+	}()
+	backup := Credentials{
+		Path:                filepath.Join(dir, "backup"),
+		Key:                 oldCreds.Key,
+		KeyDerivationMethod: oldCreds.KeyDerivationMethod,
+	}
+	h := try.To1(openHandle(config, oldCreds))
+	err = (<-Export(h, backup)).Err()
+	try.To(closeHandle(h))
+	try.To(err)
+
+	rekey := oldCreds
+	rekey.ReKey = newCreds.Key
+	rekey.ReKeyDerivationMethod = newCreds.KeyDerivationMethod
+	h, err = openHandle(config, rekey)
+	if err == nil {
+		try.To(closeHandle(h))
+		err = verifyOpen(config, newCreds)
+	} else if verifyOpen(config, oldCreds) == nil {
+		// libindy didn't change the key
+		return err
+	}
+	if err != nil {
+		if rbErr := restore(config, oldCreds, newCreds, backup); rbErr != nil {
+			keepBackup = true
+			return fmt.Errorf("%w, and its rollback failed: %v, the backup is in %s",
+				err, rbErr, backup.Path)
+		}
+		return fmt.Errorf("%w: %v", ErrRolledBack, err)
+	}
+	return nil
+}
+
+// NewRawCredentials returns the credentials with a generated RAW key, which
+// skips the slow key derivation. The seed is optional.
+func NewRawCredentials(seed string) (Credentials, error) {
+	r := <-GenerateKey(seed)
+	if r.Err() != nil {
+		return Credentials{}, r.Err()
+	}
+	return Credentials{Key: r.Str1(), KeyDerivationMethod: KeyDerivationRaw}, nil
+}
+
+func verifyOpen(config Config, credentials Credentials) error {
+	h, err := openHandle(config, credentials)
+	if err != nil {
+		return err
+	}
+	return closeHandle(h)
+}
+
+// restoreSuffix is the suffix of the wallet ID where restore checks the
+// backup.
+const restoreSuffix = "_rekey_restore"
+
+// restore imports the backup with the old credentials. The backup is
+// imported to the temporary wallet first, and the wallet is deleted with
+// either of the credentials only after the backup opens. If the import of
+// the wallet fails after its delete, the temporary wallet is left, and its
+// ID is in the error.
+func restore(config Config, oldCreds, newCreds, backup Credentials) (err error) {
+	defer err2.Handle(&err, "restore")
+
+	tmp := config
+	tmp.ID = config.ID + restoreSuffix
+	try.To((<-Import(tmp, oldCreds, backup)).Err())
+	if err := verifyOpen(tmp, oldCreds); err != nil {
+		_ = (<-Delete(tmp, oldCreds)).Err()
+		return fmt.Errorf("backup: %w", err)
+	}
+
+	if (<-Delete(config, newCreds)).Err() != nil {
+		try.To((<-Delete(config, oldCreds)).Err())
+	}
+	err = (<-Import(config, oldCreds, backup)).Err()
+	if err == nil {
+		err = verifyOpen(config, oldCreds)
+	}
+	if err != nil {
+		return fmt.Errorf("%w, the backup is restored to wallet %s", err, tmp.ID)
+	}
+	return (<-Delete(tmp, oldCreds)).Err()
+}
+
+// Migration is the rekey of one wallet, see Migrate.
+type Migration struct {
+	Config Config
+	Old    Credentials
+	New    Credentials
+}
+
+// Migrate rekeys the wallets with the count of the concurrent workers. The
+// errors are in the order of the migrations and nil for the successful ones.
+// The returned error joins them.
+func Migrate(migrations []Migration, workers int) (errs []error, err error) {
+	if workers < 1 {
+		workers = 1
+	}
+	errs = make([]error, len(migrations))
+	indexes := make(chan int)
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				mg := migrations[i]
+				errs[i] = Rekey(mg.Config, mg.Old, mg.New)
+			}
+		}()
+	}
+	for i := range migrations {
+		indexes <- i
+	}
+	close(indexes)
+	wg.Wait()
+	return errs, errors.Join(errs...)
+}
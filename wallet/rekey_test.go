@@ -0,0 +1,110 @@
+package wallet
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/findy-network/findy-wrapper-go/dto"
+	"github.com/lainio/err2/assert"
+)
+
+func TestCredentials_rekeyJSON(t *testing.T) {
+	defer assert.PushTester(t)()
+
+	c := Credentials{Key: "old", KeyDerivationMethod: KeyDerivationArgon2iMod,
+		ReKey: "new", ReKeyDerivationMethod: KeyDerivationRaw}
+	assert.Equal(dto.ToJSON(c), `{"key":"old","key_derivation_method":"ARGON2I_MOD",`+
+		`"rekey":"new","rekey_derivation_method":"RAW"}`)
+}
+
+func createRekeyWallet(name string, creds Credentials) Config {
+	cfg := Config{ID: fmt.Sprintf("%s_%d", name, os.Getpid())}
+	assert.NoError((<-Create(cfg, creds)).Err())
+	return cfg
+}
+
+func TestRekey(t *testing.T) {
+	defer assert.PushTester(t)()
+
+	oldCreds := Credentials{Key: "6cih1cVgRH8yHD54nEYyPKLmdv67o8QbufxaTHot3Qxp", KeyDerivationMethod: KeyDerivationRaw}
+	cfg := createRekeyWallet("rekey_wallet", oldCreds)
+
+	newCreds, err := NewRawCredentials("")
+	assert.NoError(err)
+	assert.NoError(Rekey(cfg, oldCreds, newCreds))
+	assert.NoError(verifyOpen(cfg, newCreds))
+	assert.Error(verifyOpen(cfg, oldCreds))
+
+	argonCreds := Credentials{Key: "passphrase", KeyDerivationMethod: KeyDerivationArgon2iInt}
+	assert.NoError(Rekey(cfg, newCreds, argonCreds))
+	assert.NoError(verifyOpen(cfg, argonCreds))
+
+	// the invalid RAW key isn't accepted, and the wallet keeps its key
+	err = Rekey(cfg, argonCreds, Credentials{Key: "invalid", KeyDerivationMethod: KeyDerivationRaw})
+	assert.Error(err)
+	assert.ThatNot(errors.Is(err, ErrRolledBack))
+	assert.NoError(verifyOpen(cfg, argonCreds))
+
+	assert.NoError((<-Delete(cfg, argonCreds)).Err())
+}
+
+func TestRestore(t *testing.T) {
+	defer assert.PushTester(t)()
+
+	oldCreds := Credentials{Key: "6cih1cVgRH8yHD54nEYyPKLmdv67o8QbufxaTHot3Qxp", KeyDerivationMethod: KeyDerivationRaw}
+	newCreds, err := NewRawCredentials("")
+	assert.NoError(err)
+	cfg := createRekeyWallet("restore_wallet", oldCreds)
+
+	dir := t.TempDir()
+	backup := Credentials{Path: filepath.Join(dir, "backup"), Key: oldCreds.Key, KeyDerivationMethod: oldCreds.KeyDerivationMethod}
+	h, err := openHandle(cfg, oldCreds)
+	assert.NoError(err)
+	assert.NoError((<-Export(h, backup)).Err())
+	assert.NoError(closeHandle(h))
+
+	// the wallet isn't deleted if the backup cannot be imported
+	invalid := backup
+	invalid.Path = filepath.Join(dir, "missing")
+	assert.Error(restore(cfg, oldCreds, newCreds, invalid))
+	assert.NoError(verifyOpen(cfg, oldCreds))
+
+	assert.NoError(restore(cfg, oldCreds, newCreds, backup))
+	assert.NoError(verifyOpen(cfg, oldCreds))
+	tmp := cfg
+	tmp.ID += restoreSuffix
+	assert.Error(verifyOpen(tmp, oldCreds))
+
+	assert.NoError((<-Delete(cfg, oldCreds)).Err())
+}
+
+func TestMigrate(t *testing.T) {
+	defer assert.PushTester(t)()
+
+	oldCreds := Credentials{Key: "passphrase", KeyDerivationMethod: KeyDerivationArgon2iInt}
+	migrations := make([]Migration, 3)
+	for i := range migrations {
+		newCreds, err := NewRawCredentials("")
+		assert.NoError(err)
+		migrations[i] = Migration{
+			Config: createRekeyWallet(fmt.Sprint("migrate_wallet_", i), oldCreds),
+			Old:    oldCreds,
+			New:    newCreds,
+		}
+	}
+	migrations = append(migrations, Migration{Config: Config{ID: "missing_migrate_wallet"},
+		Old: oldCreds, New: migrations[0].New})
+
+	errs, err := Migrate(migrations, 2)
+	assert.Error(err)
+	assert.SLen(errs, 4)
+	for i, mg := range migrations[:3] {
+		assert.NoError(errs[i])
+		assert.NoError(verifyOpen(mg.Config, mg.New))
+		assert.NoError((<-Delete(mg.Config, mg.New)).Err())
+	}
+	assert.Error(errs[3])
+}
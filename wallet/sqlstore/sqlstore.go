@@ -0,0 +1,451 @@
+/*
+Package sqlstore is the reference implementation of the Go wallet storages. It
+stores the wallets to the embedded SQL engine of immudb, which is in the same
+directory for all of the wallets:
+
+	s, err := sqlstore.Open(dir)
+	r := <-sqlstore.Register(s)
+	...
+	cfg := wallet.Config{ID: name, StorageType: sqlstore.Type}
+	r = <-wallet.Create(cfg, credentials)
+
+The storage_config and storage_credentials of the wallets aren't used.
+
+# Data Retention
+
+The immudb store is append-only, and nothing is ever removed from it. Delete
+only marks the records and the wallets deleted, and the updates add new
+versions of the rows. The encrypted values, tags and metadata of the deleted
+wallets and records, and all of their earlier versions, stay in the store
+files and in their history for good. That includes the wallet metadata
+encrypted with the old key after wallet.Rekey, i.e. anyone who has the old
+key and the store files can still decrypt the old data.
+
+Use the store only when the retention is acceptable, e.g. when the audit
+trail is wanted. The data is removed only by deleting the store directory.
+*/
+package sqlstore
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/codenotary/immudb/embedded/sql"
+	"github.com/codenotary/immudb/embedded/store"
+	"github.com/codenotary/immudb/pkg/logger"
+	"github.com/findy-network/findy-wrapper-go/internal/ctx"
+	"github.com/findy-network/findy-wrapper-go/plugin"
+	"github.com/findy-network/findy-wrapper-go/wallet"
+	"github.com/findy-network/findy-wrapper-go/wql"
+	"github.com/golang/glog"
+	"github.com/lainio/err2"
+	"github.com/lainio/err2/try"
+)
+
+// Type is the wallet.Config StorageType of the store.
+const Type = "findy_sql"
+
+const dbName = "wallets"
+
+// sqlPrefix is the key prefix of the SQL engine in the immudb store.
+var sqlPrefix = []byte{2}
+
+// The keys are SHA-256 hashes, because immudb's primary keys and indexed
+// values are at most 32 bytes.
+var schema = []string{
+	"CREATE TABLE wallets (id BLOB, name VARCHAR, metadata VARCHAR, deleted BOOLEAN, PRIMARY KEY id)",
+	"CREATE TABLE records (id BLOB, wallet BLOB, type VARCHAR, name VARCHAR, value BLOB, " +
+		"tags VARCHAR, deleted BOOLEAN, PRIMARY KEY id)",
+	"CREATE INDEX ON records(wallet)",
+}
+
+const (
+	upsertWallet = "UPSERT INTO wallets (id, name, metadata, deleted) " +
+		"VALUES (@id, @name, @metadata, @deleted)"
+	upsertRecord = "UPSERT INTO records (id, wallet, type, name, value, tags, deleted) " +
+		"VALUES (@id, @wallet, @type, @name, @value, @tags, @deleted)"
+	selectWallet  = "SELECT name, metadata, deleted FROM wallets WHERE id = @id"
+	selectRecord  = "SELECT type, name, value, tags, deleted FROM records WHERE id = @id"
+	selectRecords = "SELECT type, name, value, tags FROM records " +
+		"WHERE wallet = @wallet AND deleted = false"
+	selectTypeRecords = selectRecords + " AND type = @type"
+)
+
+// Store is the wallet storage on the embedded SQL database. It implements
+// plugin.Storage.
+type Store struct {
+	// mu serializes the read-modify-write operations of the records.
+	mu     sync.Mutex
+	store  *store.ImmuStore
+	engine *sql.Engine
+}
+
+// row is the result row by the column names.
+type row map[string]interface{}
+
+// Open opens the store from the directory, and creates it if it doesn't
+// exist.
+func Open(dir string) (s *Store, err error) {
+	defer err2.Handle(&err, "sqlstore: open %s", dir)
+
+	st := try.To1(store.Open(dir, store.DefaultOptions().WithLog(glogger{})))
+	s = &Store{store: st}
+	if err = s.init(); err != nil {
+		_ = st.Close()
+		return nil, err
+	}
+	return s, nil
+}
+
+func (s *Store) init() (err error) {
+	defer err2.Handle(&err)
+
+	s.engine = try.To1(sql.NewEngine(s.store, s.store, sqlPrefix))
+	try.To(s.engine.EnsureCatalogReady(nil))
+
+	err = s.engine.UseDatabase(dbName)
+	if !errors.Is(err, sql.ErrDatabaseDoesNotExist) {
+		return err
+	}
+	try.To(s.exec("CREATE DATABASE "+dbName, nil))
+	try.To(s.engine.UseDatabase(dbName))
+	for _, stmt := range schema {
+		try.To(s.exec(stmt, nil))
+	}
+	return nil
+}
+
+// Register registers the store as the wallet storage Type.
+func Register(s *Store) ctx.Channel {
+	return wallet.RegisterStorage(Type, s)
+}
+
+// Close closes the store. The wallets of the store must be closed first.
+func (s *Store) Close() (err error) {
+	defer err2.Handle(&err, "sqlstore: close")
+
+	try.To(s.engine.Close())
+	return s.store.Close()
+}
+
+// Create creates the wallet with libindy's metadata.
+func (s *Store) Create(name, _, _, metadata string) (err error) {
+	defer err2.Handle(&err, "sqlstore: create %s", name)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, exists := try.To2(s.walletMetadata(name)); exists {
+		return plugin.ErrWalletExists
+	}
+	return s.exec(upsertWallet, map[string]interface{}{
+		"id": walletKey(name), "name": name, "metadata": metadata, "deleted": false,
+	})
+}
+
+// Open opens the wallet.
+func (s *Store) Open(name, _, _ string) (w plugin.StorageWallet, err error) {
+	defer err2.Handle(&err, "sqlstore: open %s", name)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, exists := try.To2(s.walletMetadata(name)); !exists {
+		return nil, plugin.ErrWalletNotFound
+	}
+	return &storeWallet{s: s, name: name, key: walletKey(name)}, nil
+}
+
+// Delete marks the wallet and its records deleted.
+func (s *Store) Delete(name, _, _ string) (err error) {
+	defer err2.Handle(&err, "sqlstore: delete %s", name)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, exists := try.To2(s.walletMetadata(name)); !exists {
+		return plugin.ErrWalletNotFound
+	}
+	w := &storeWallet{s: s, name: name, key: walletKey(name)}
+	for _, r := range try.To1(w.records("")) {
+		try.To(w.upsert(r, true))
+	}
+	return s.exec(upsertWallet, map[string]interface{}{
+		"id": walletKey(name), "name": name, "metadata": "", "deleted": true,
+	})
+}
+
+func (s *Store) walletMetadata(name string) (metadata string, exists bool, err error) {
+	rows, err := s.query(selectWallet, map[string]interface{}{"id": walletKey(name)})
+	if err != nil || len(rows) == 0 || rows[0].bool("deleted") {
+		return "", false, err
+	}
+	return rows[0].str("metadata"), true, nil
+}
+
+func (s *Store) exec(stmt string, params map[string]interface{}) error {
+	_, _, err := s.engine.ExecStmt(stmt, params, true)
+	return err
+}
+
+func (s *Store) query(stmt string, params map[string]interface{}) (rows []row, err error) {
+	defer err2.Handle(&err)
+
+	reader := try.To1(s.engine.QueryStmt(stmt, params, true))
+	defer reader.Close()
+	cols := try.To1(reader.Columns())
+	for {
+		r, err := reader.Read()
+		if errors.Is(err, sql.ErrNoMoreRows) {
+			return rows, nil
+		}
+		try.To(err)
+		values := make(row, len(cols))
+		for _, col := range cols {
+			if v, ok := r.Values[col.Selector()]; ok {
+				values[col.Column] = v.Value()
+			}
+		}
+		rows = append(rows, values)
+	}
+}
+
+func (r row) str(col string) string {
+	s, _ := r[col].(string)
+	return s
+}
+
+func (r row) bool(col string) bool {
+	b, _ := r[col].(bool)
+	return b
+}
+
+func (r row) record() (rec plugin.StorageRecord, err error) {
+	rec = plugin.StorageRecord{Type: r.str("type"), ID: r.str("name")}
+	rec.Value, _ = r["value"].([]byte)
+	if err = json.Unmarshal([]byte(r.str("tags")), &rec.Tags); err != nil {
+		return rec, fmt.Errorf("tags of %s: %w", rec.ID, err)
+	}
+	return rec, nil
+}
+
+// storeWallet is the opened wallet of the Store.
+type storeWallet struct {
+	s    *Store
+	name string
+	key  []byte
+}
+
+func (w *storeWallet) Close() error {
+	return nil
+}
+
+func (w *storeWallet) Add(r plugin.StorageRecord) (err error) {
+	defer err2.Handle(&err, "sqlstore: add %s", w.name)
+
+	w.s.mu.Lock()
+	defer w.s.mu.Unlock()
+
+	if _, err := w.get(r.Type, r.ID); err == nil {
+		return plugin.ErrRecordExists
+	} else if !errors.Is(err, plugin.ErrRecordNotFound) {
+		return err
+	}
+	return w.upsert(r, false)
+}
+
+func (w *storeWallet) UpdateValue(typ, id string, value []byte) error {
+	return w.update(typ, id, func(r *plugin.StorageRecord) {
+		r.Value = value
+	})
+}
+
+func (w *storeWallet) UpdateTags(typ, id string, tags map[string]string) error {
+	return w.update(typ, id, func(r *plugin.StorageRecord) {
+		r.Tags = tags
+	})
+}
+
+func (w *storeWallet) AddTags(typ, id string, tags map[string]string) error {
+	return w.update(typ, id, func(r *plugin.StorageRecord) {
+		if r.Tags == nil {
+			r.Tags = make(map[string]string, len(tags))
+		}
+		for name, value := range tags {
+			r.Tags[name] = value
+		}
+	})
+}
+
+func (w *storeWallet) DeleteTags(typ, id string, names []string) error {
+	return w.update(typ, id, func(r *plugin.StorageRecord) {
+		for _, name := range names {
+			delete(r.Tags, name)
+		}
+	})
+}
+
+func (w *storeWallet) Delete(typ, id string) (err error) {
+	defer err2.Handle(&err, "sqlstore: delete record %s", w.name)
+
+	w.s.mu.Lock()
+	defer w.s.mu.Unlock()
+
+	r := try.To1(w.get(typ, id))
+	return w.upsert(r, true)
+}
+
+func (w *storeWallet) Get(typ, id string) (r plugin.StorageRecord, err error) {
+	defer err2.Handle(&err, "sqlstore: get %s", w.name)
+
+	w.s.mu.Lock()
+	defer w.s.mu.Unlock()
+
+	return w.get(typ, id)
+}
+
+func (w *storeWallet) Metadata() (metadata string, err error) {
+	defer err2.Handle(&err, "sqlstore: metadata %s", w.name)
+
+	w.s.mu.Lock()
+	defer w.s.mu.Unlock()
+
+	metadata, exists := try.To2(w.s.walletMetadata(w.name))
+	if !exists {
+		return "", plugin.ErrWalletNotFound
+	}
+	return metadata, nil
+}
+
+func (w *storeWallet) SetMetadata(metadata string) (err error) {
+	defer err2.Handle(&err, "sqlstore: set metadata %s", w.name)
+
+	w.s.mu.Lock()
+	defer w.s.mu.Unlock()
+
+	return w.s.exec(upsertWallet, map[string]interface{}{
+		"id": w.key, "name": w.name, "metadata": metadata, "deleted": false,
+	})
+}
+
+func (w *storeWallet) Search(typ string, query wql.Query) (records []plugin.StorageRecord, err error) {
+	defer err2.Handle(&err, "sqlstore: search %s", w.name)
+
+	w.s.mu.Lock()
+	defer w.s.mu.Unlock()
+
+	for _, r := range try.To1(w.records(typ)) {
+		if query.Match(r.Tags) {
+			records = append(records, r)
+		}
+	}
+	return records, nil
+}
+
+func (w *storeWallet) SearchAll() (records []plugin.StorageRecord, err error) {
+	defer err2.Handle(&err, "sqlstore: search all %s", w.name)
+
+	w.s.mu.Lock()
+	defer w.s.mu.Unlock()
+
+	return w.records("")
+}
+
+func (w *storeWallet) update(typ, id string, f func(r *plugin.StorageRecord)) (err error) {
+	defer err2.Handle(&err, "sqlstore: update %s", w.name)
+
+	w.s.mu.Lock()
+	defer w.s.mu.Unlock()
+
+	r := try.To1(w.get(typ, id))
+	f(&r)
+	return w.upsert(r, false)
+}
+
+func (w *storeWallet) get(typ, id string) (r plugin.StorageRecord, err error) {
+	rows, err := w.s.query(selectRecord, map[string]interface{}{"id": w.recordKey(typ, id)})
+	if err != nil {
+		return r, err
+	}
+	if len(rows) == 0 || rows[0].bool("deleted") {
+		return r, plugin.ErrRecordNotFound
+	}
+	return rows[0].record()
+}
+
+// records returns the wallet's records of the type, or all of them if the
+// type is empty. The deleted records and the other types are filtered in
+// the SQL query.
+func (w *storeWallet) records(typ string) (records []plugin.StorageRecord, err error) {
+	stmt, params := selectRecords, map[string]interface{}{"wallet": w.key}
+	if typ != "" {
+		stmt, params["type"] = selectTypeRecords, typ
+	}
+	rows, err := w.s.query(stmt, params)
+	if err != nil {
+		return nil, err
+	}
+	for _, row := range rows {
+		r, err := row.record()
+		if err != nil {
+			return nil, err
+		}
+		records = append(records, r)
+	}
+	return records, nil
+}
+
+func (w *storeWallet) upsert(r plugin.StorageRecord, deleted bool) error {
+	if r.Value == nil {
+		r.Value = []byte{}
+	}
+	tags := "{}"
+	if r.Tags != nil {
+		data, err := json.Marshal(r.Tags)
+		if err != nil {
+			return err
+		}
+		tags = string(data)
+	}
+	return w.s.exec(upsertRecord, map[string]interface{}{
+		"id":      w.recordKey(r.Type, r.ID),
+		"wallet":  w.key,
+		"type":    r.Type,
+		"name":    r.ID,
+		"value":   r.Value,
+		"tags":    tags,
+		"deleted": deleted,
+	})
+}
+
+func walletKey(name string) []byte {
+	return hashKey(name)
+}
+
+func (w *storeWallet) recordKey(typ, id string) []byte {
+	return hashKey(w.name, typ, id)
+}
+
+// hashKey hashes the length prefixed parts.
+func hashKey(parts ...string) []byte {
+	h := sha256.New()
+	for _, p := range parts {
+		_ = binary.Write(h, binary.BigEndian, uint32(len(p)))
+		h.Write([]byte(p))
+	}
+	return h.Sum(nil)
+}
+
+// glogger is the logger of the immudb store.
+type glogger struct{}
+
+func (glogger) Errorf(f string, v ...interface{})   { glog.Errorf(f, v...) }
+func (glogger) Warningf(f string, v ...interface{}) { glog.Warningf(f, v...) }
+func (glogger) Infof(f string, v ...interface{})    { glog.V(5).Infof(f, v...) }
+func (glogger) Debugf(f string, v ...interface{})   { glog.V(10).Infof(f, v...) }
+
+func (l glogger) CloneWithLevel(logger.LogLevel) logger.Logger { return l }
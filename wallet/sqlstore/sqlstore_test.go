@@ -0,0 +1,142 @@
+package sqlstore
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/findy-network/findy-wrapper-go/crypto"
+	"github.com/findy-network/findy-wrapper-go/did"
+	"github.com/findy-network/findy-wrapper-go/plugin"
+	"github.com/findy-network/findy-wrapper-go/wallet"
+	"github.com/findy-network/findy-wrapper-go/wallet/records"
+	"github.com/findy-network/findy-wrapper-go/wql"
+	"github.com/lainio/err2/assert"
+)
+
+func TestStore(t *testing.T) {
+	defer assert.PushTester(t)()
+
+	dir := t.TempDir()
+	s, err := Open(dir)
+	assert.NoError(err)
+
+	assert.NoError(s.Create("w1", "", "", "meta"))
+	assert.That(errors.Is(s.Create("w1", "", "", "meta"), plugin.ErrWalletExists))
+	_, err = s.Open("w2", "", "")
+	assert.That(errors.Is(err, plugin.ErrWalletNotFound))
+
+	w, err := s.Open("w1", "", "")
+	assert.NoError(err)
+	meta, err := w.Metadata()
+	assert.NoError(err)
+	assert.Equal(meta, "meta")
+	assert.NoError(w.SetMetadata("meta2"))
+
+	assert.NoError(w.Add(plugin.StorageRecord{Type: "t", ID: "1", Value: []byte{1},
+		Tags: map[string]string{"a": "x", "~n": "1"}}))
+	assert.NoError(w.Add(plugin.StorageRecord{Type: "t", ID: "2"}))
+	assert.NoError(w.Add(plugin.StorageRecord{Type: "u", ID: "1", Value: []byte{3}}))
+	err = w.Add(plugin.StorageRecord{Type: "t", ID: "1"})
+	assert.That(errors.Is(err, plugin.ErrRecordExists))
+
+	assert.NoError(w.UpdateValue("t", "2", []byte{2}))
+	assert.NoError(w.AddTags("t", "2", map[string]string{"a": "y", "~n": "2"}))
+	assert.NoError(w.DeleteTags("t", "1", []string{"a"}))
+	r, err := w.Get("t", "2")
+	assert.NoError(err)
+	assert.DeepEqual(r.Value, []byte{2})
+	assert.Equal(r.Tags["a"], "y")
+
+	recs, err := w.Search("t", wql.Gte("~n", "1"))
+	assert.NoError(err)
+	assert.SLen(recs, 2)
+	recs, err = w.Search("t", wql.Eq("a", "x"))
+	assert.NoError(err)
+	assert.SLen(recs, 0)
+
+	assert.NoError(w.Delete("u", "1"))
+	_, err = w.Get("u", "1")
+	assert.That(errors.Is(err, plugin.ErrRecordNotFound))
+	assert.That(errors.Is(w.UpdateTags("u", "1", nil), plugin.ErrRecordNotFound))
+	recs, err = w.SearchAll()
+	assert.NoError(err)
+	assert.SLen(recs, 2)
+	assert.NoError(w.Close())
+	assert.NoError(s.Close())
+
+	s, err = Open(dir)
+	assert.NoError(err)
+	w, err = s.Open("w1", "", "")
+	assert.NoError(err)
+	meta, err = w.Metadata()
+	assert.NoError(err)
+	assert.Equal(meta, "meta2")
+	r, err = w.Get("t", "1")
+	assert.NoError(err)
+	assert.DeepEqual(r.Tags, map[string]string{"~n": "1"})
+
+	assert.NoError(s.Delete("w1", "", ""))
+	_, err = s.Open("w1", "", "")
+	assert.That(errors.Is(err, plugin.ErrWalletNotFound))
+	assert.NoError(s.Create("w1", "", "", "meta3"))
+	w, err = s.Open("w1", "", "")
+	assert.NoError(err)
+	recs, err = w.SearchAll()
+	assert.NoError(err)
+	assert.SLen(recs, 0)
+	assert.NoError(s.Close())
+}
+
+func TestWallet(t *testing.T) {
+	defer assert.PushTester(t)()
+
+	s, err := Open(t.TempDir())
+	assert.NoError(err)
+	r := <-Register(s)
+	assert.NoError(r.Err())
+
+	cfg := wallet.Config{ID: "sqlstore_test_wallet", StorageType: Type}
+	key := wallet.Credentials{Key: "6cih1cVgRH8yHD54nEYyPKLmdv67o8QbufxaTHot3Qxp",
+		KeyDerivationMethod: "RAW"}
+	r = <-wallet.Create(cfg, key)
+	assert.NoError(r.Err())
+	r = <-wallet.Open(cfg, key)
+	assert.NoError(r.Err())
+	w := r.Handle()
+
+	r = <-did.CreateAndStore(w, did.Did{Seed: "000000000000000000000000Steward1"})
+	assert.NoError(r.Err())
+	myDID, verKey := r.Str1(), r.Str2()
+	r = <-crypto.SignMsg(w, verKey, []byte("message"))
+	assert.NoError(r.Err())
+	r = <-crypto.VerifySignature(verKey, []byte("message"), r.Bytes())
+	assert.NoError(r.Err())
+	assert.That(r.Yes())
+
+	r = <-records.Add(w, records.Record{Type: "test", ID: "1", Value: "value",
+		Tags: records.Tags{"~n": "1"}})
+	assert.NoError(r.Err())
+	recs, err := records.Find(w, "test", wql.Eq("~n", "1"))
+	assert.NoError(err)
+	assert.SLen(recs, 1)
+
+	r = <-wallet.Close(w)
+	assert.NoError(r.Err())
+	r = <-wallet.Open(cfg, key)
+	assert.NoError(r.Err())
+	w = r.Handle()
+	r = <-did.LocalKey(w, myDID)
+	assert.NoError(r.Err())
+	assert.Equal(r.Str1(), verKey)
+	infos, err := did.Infos(w)
+	assert.NoError(err)
+	assert.SLen(infos, 1)
+
+	r = <-wallet.Close(w)
+	assert.NoError(r.Err())
+	r = <-wallet.Delete(cfg, key)
+	assert.NoError(r.Err())
+	r = <-wallet.Open(cfg, key)
+	assert.Error(r.Err())
+	assert.NoError(s.Close())
+}
@@ -6,6 +6,7 @@ import (
 	"github.com/findy-network/findy-wrapper-go/dto"
 	"github.com/findy-network/findy-wrapper-go/internal/c2go"
 	"github.com/findy-network/findy-wrapper-go/internal/ctx"
+	"github.com/findy-network/findy-wrapper-go/plugin"
 )
 
 // GenerateKey generates a wallet master key. Returned key is compatible with
@@ -52,3 +53,11 @@ func Export(handle int, exportCfg Credentials) ctx.Channel {
 func Import(config Config, credentials Credentials, importCfg Credentials) ctx.Channel {
 	return c2go.FindyImportWallet(dto.ToJSON(config), dto.ToJSON(credentials), dto.ToJSON(importCfg))
 }
+
+// RegisterStorage registers the Go storage implementation as the wallet
+// storage type. The wallets whose Config has the StorageType are stored to
+// it. A process can register eight storages, and the type names must be
+// unique.
+func RegisterStorage(storageType string, storage plugin.Storage) ctx.Channel {
+	return c2go.FindyRegisterWalletStorage(storageType, storage)
+}
@@ -0,0 +1,425 @@
+/*
+Package wql is a type-safe builder for the Wallet Query Language of libindy.
+WQL queries are used in the wallet searches, e.g. in the credential searches
+of the prover. See indy-sdk/docs/design/011-wallet-query-language for the
+language.
+
+	q := wql.And(
+		wql.Eq("schema_id", schemaID),
+		wql.Or(wql.AttrValue("name", "Alice"), wql.AttrMarker("nickname")),
+		wql.Not(wql.In("issuer_did", revokedIssuers...)),
+	)
+	r := <-anoncreds.ProverSearchCredentials(w, q.String())
+
+The zero Query matches everything, i.e. it's "{}".
+*/
+package wql
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Op is the WQL operator.
+type Op string
+
+// WQL operators. OpEq isn't serialized, because the equality is the plain
+// value of the tag.
+const (
+	OpEq   Op = "$eq"
+	OpNeq  Op = "$neq"
+	OpGt   Op = "$gt"
+	OpGte  Op = "$gte"
+	OpLt   Op = "$lt"
+	OpLte  Op = "$lte"
+	OpLike Op = "$like"
+	OpIn   Op = "$in"
+	OpAnd  Op = "$and"
+	OpOr   Op = "$or"
+	OpNot  Op = "$not"
+
+	// opObject is a JSON object with several keys, which WQL interprets as
+	// an implicit $and.
+	opObject Op = ""
+)
+
+// PlaintextPrefix is the prefix of unencrypted tag names. Only unencrypted
+// tags can be compared with $gt, $gte, $lt, $lte and $like.
+const PlaintextPrefix = "~"
+
+// Query is a WQL query. Queries are immutable values which are built with the
+// functions of this package or parsed from JSON.
+type Query struct {
+	op     Op
+	tag    string
+	value  string
+	values []string
+	subs   []Query
+}
+
+// Eq matches the tag value.
+func Eq(tag, value string) Query { return tagQuery(OpEq, tag, value) }
+
+// Neq matches other than the tag value.
+func Neq(tag, value string) Query { return tagQuery(OpNeq, tag, value) }
+
+// Gt matches the tag values greater than the value. Only for unencrypted tags.
+func Gt(tag, value string) Query { return tagQuery(OpGt, tag, value) }
+
+// Gte matches the tag values greater than or equal to the value. Only for
+// unencrypted tags.
+func Gte(tag, value string) Query { return tagQuery(OpGte, tag, value) }
+
+// Lt matches the tag values less than the value. Only for unencrypted tags.
+func Lt(tag, value string) Query { return tagQuery(OpLt, tag, value) }
+
+// Lte matches the tag values less than or equal to the value. Only for
+// unencrypted tags.
+func Lte(tag, value string) Query { return tagQuery(OpLte, tag, value) }
+
+// Like matches the tag values with the SQL LIKE pattern. Only for unencrypted
+// tags.
+func Like(tag, pattern string) Query { return tagQuery(OpLike, tag, pattern) }
+
+// In matches any of the tag values.
+func In(tag string, values ...string) Query {
+	return Query{op: OpIn, tag: tag, values: values}
+}
+
+// And matches if all of the queries match.
+func And(queries ...Query) Query { return Query{op: OpAnd, subs: queries} }
+
+// Or matches if any of the queries match.
+func Or(queries ...Query) Query { return Query{op: OpOr, subs: queries} }
+
+// Not matches if the query doesn't match.
+func Not(query Query) Query { return Query{op: OpNot, subs: []Query{query}} }
+
+// AttrValue matches the credential attribute value. Attribute names are
+// normalized like libindy does it.
+func AttrValue(name, value string) Query {
+	return Eq(AttrValueTag(name), value)
+}
+
+// AttrMarker matches the credentials which have the attribute.
+func AttrMarker(name string) Query {
+	return Eq(AttrMarkerTag(name), "1")
+}
+
+// AttrValueTag returns the credential tag name of the attribute value:
+// attr::<name>::value.
+func AttrValueTag(name string) string {
+	return "attr::" + normalize(name) + "::value"
+}
+
+// AttrMarkerTag returns the credential tag name of the attribute marker:
+// attr::<name>::marker.
+func AttrMarkerTag(name string) string {
+	return "attr::" + normalize(name) + "::marker"
+}
+
+func tagQuery(op Op, tag, value string) Query {
+	return Query{op: op, tag: tag, value: value}
+}
+
+// normalize normalizes the attribute name like libindy does: names are case
+// insensitive and spaces are ignored.
+func normalize(name string) string {
+	return strings.ToLower(strings.ReplaceAll(name, " ", ""))
+}
+
+// IsZero returns true if the query is the zero Query, which matches
+// everything.
+func (q Query) IsZero() bool {
+	return q.op == opObject && len(q.subs) == 0
+}
+
+// String returns the query JSON. Invalid queries return "{}", use Validate
+// or MarshalJSON to get the error.
+func (q Query) String() string {
+	data, err := q.MarshalJSON()
+	if err != nil {
+		return "{}"
+	}
+	return string(data)
+}
+
+// Validate checks that the query is valid WQL: tag names aren't empty,
+// comparison operators are used only with unencrypted tags, $in and the
+// logical operators have operands, and the same tag isn't used twice in one
+// object.
+func (q Query) Validate() error {
+	switch q.op {
+	case OpEq, OpNeq:
+		return validTag(q.tag)
+	case OpGt, OpGte, OpLt, OpLte, OpLike:
+		if err := validTag(q.tag); err != nil {
+			return err
+		}
+		if !strings.HasPrefix(q.tag, PlaintextPrefix) {
+			return fmt.Errorf("%s: %s is only for unencrypted (%s) tags",
+				q.tag, q.op, PlaintextPrefix)
+		}
+	case OpIn:
+		if err := validTag(q.tag); err != nil {
+			return err
+		}
+		if len(q.values) == 0 {
+			return fmt.Errorf("%s: empty %s", q.tag, OpIn)
+		}
+	case OpAnd, OpOr:
+		if len(q.subs) == 0 {
+			return fmt.Errorf("empty %s", q.op)
+		}
+		return validateAll(q.subs)
+	case OpNot:
+		if len(q.subs) != 1 || q.subs[0].IsZero() {
+			return fmt.Errorf("%s needs a query", OpNot)
+		}
+		return q.subs[0].Validate()
+	case opObject:
+		if err := validateAll(q.subs); err != nil {
+			return err
+		}
+		_, err := q.object()
+		return err
+	default:
+		return fmt.Errorf("unknown operator %q", q.op)
+	}
+	return nil
+}
+
+func validTag(tag string) error {
+	if tag == "" || strings.HasPrefix(tag, "$") {
+		return fmt.Errorf("invalid tag name %q", tag)
+	}
+	if strings.HasPrefix(tag, "attr::") {
+		parts := strings.Split(tag, "::")
+		if len(parts) != 3 || parts[1] == "" ||
+			(parts[2] != "value" && parts[2] != "marker") {
+			return fmt.Errorf("invalid attribute tag %q", tag)
+		}
+	}
+	return nil
+}
+
+func validateAll(queries []Query) error {
+	for _, sub := range queries {
+		if err := sub.Validate(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// MarshalJSON returns the WQL JSON of the query. The query is validated.
+func (q Query) MarshalJSON() ([]byte, error) {
+	if err := q.Validate(); err != nil {
+		return nil, fmt.Errorf("wql: %w", err)
+	}
+	obj, err := q.object()
+	if err != nil {
+		return nil, fmt.Errorf("wql: %w", err)
+	}
+	return json.Marshal(obj)
+}
+
+// object returns the query as a JSON object.
+func (q Query) object() (map[string]any, error) {
+	switch q.op {
+	case OpEq:
+		return map[string]any{q.tag: q.value}, nil
+	case OpIn:
+		return map[string]any{q.tag: map[string]any{string(q.op): q.values}}, nil
+	case OpAnd, OpOr:
+		return map[string]any{string(q.op): q.subs}, nil
+	case OpNot:
+		return map[string]any{string(q.op): q.subs[0]}, nil
+	case opObject:
+		obj := make(map[string]any, len(q.subs))
+		for _, sub := range q.subs {
+			subObj, err := sub.object()
+			if err != nil {
+				return nil, err
+			}
+			for key, value := range subObj {
+				if _, ok := obj[key]; ok {
+					return nil, fmt.Errorf("duplicate key %q in object", key)
+				}
+				obj[key] = value
+			}
+		}
+		return obj, nil
+	default:
+		return map[string]any{q.tag: map[string]any{string(q.op): q.value}}, nil
+	}
+}
+
+// Parse parses the WQL JSON to a Query. It validates the query.
+func Parse(s string) (q Query, err error) {
+	if err = json.Unmarshal([]byte(s), &q); err != nil {
+		return q, err
+	}
+	return q, nil
+}
+
+// UnmarshalJSON parses the WQL JSON. An object with several keys is an
+// implicit $and, and it's kept as it is.
+func (q *Query) UnmarshalJSON(data []byte) error {
+	var obj map[string]json.RawMessage
+	if err := json.Unmarshal(data, &obj); err != nil {
+		return fmt.Errorf("wql: %w", err)
+	}
+	keys := make([]string, 0, len(obj))
+	for key := range obj {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	subs := make([]Query, 0, len(obj))
+	for _, key := range keys {
+		sub, err := parseKey(key, obj[key])
+		if err != nil {
+			return fmt.Errorf("wql: %s: %w", key, err)
+		}
+		subs = append(subs, sub)
+	}
+	if len(subs) == 1 {
+		*q = subs[0]
+	} else {
+		*q = Query{op: opObject, subs: subs}
+	}
+	if err := q.Validate(); err != nil {
+		return fmt.Errorf("wql: %w", err)
+	}
+	return nil
+}
+
+func parseKey(key string, value json.RawMessage) (q Query, err error) {
+	switch Op(key) {
+	case OpAnd, OpOr:
+		var subs []Query
+		if err = json.Unmarshal(value, &subs); err != nil {
+			return q, err
+		}
+		return Query{op: Op(key), subs: subs}, nil
+	case OpNot:
+		var sub Query
+		if err = json.Unmarshal(value, &sub); err != nil {
+			return q, err
+		}
+		return Not(sub), nil
+	}
+	if strings.HasPrefix(key, "$") {
+		return q, errors.New("unknown operator")
+	}
+
+	var str string
+	if json.Unmarshal(value, &str) == nil {
+		return Eq(key, str), nil
+	}
+	var ops map[Op]json.RawMessage
+	if err = json.Unmarshal(value, &ops); err != nil {
+		return q, err
+	}
+	if len(ops) != 1 {
+		return q, errors.New("tag needs exactly one operator")
+	}
+	for op, opValue := range ops {
+		switch op {
+		case OpIn:
+			var values []string
+			err = json.Unmarshal(opValue, &values)
+			return In(key, values...), err
+		case OpNeq, OpGt, OpGte, OpLt, OpLte, OpLike:
+			err = json.Unmarshal(opValue, &str)
+			return tagQuery(op, key, str), err
+		}
+		return q, fmt.Errorf("unknown operator %q", op)
+	}
+	return q, nil
+}
+
+// Match tells if the tags match the query. It's for the wallet storage
+// implementations which evaluate the queries themselves, see
+// plugin.StorageWallet. Like libindy, the tag operators match only if the tag
+// exists, and the comparisons are string comparisons.
+func (q Query) Match(tags map[string]string) bool {
+	switch q.op {
+	case OpAnd, opObject:
+		for _, sub := range q.subs {
+			if !sub.Match(tags) {
+				return false
+			}
+		}
+		return true
+	case OpOr:
+		for _, sub := range q.subs {
+			if sub.Match(tags) {
+				return true
+			}
+		}
+		return false
+	case OpNot:
+		return !q.subs[0].Match(tags)
+	}
+
+	value, ok := tags[q.tag]
+	if !ok {
+		return false
+	}
+	switch q.op {
+	case OpEq:
+		return value == q.value
+	case OpNeq:
+		return value != q.value
+	case OpGt:
+		return value > q.value
+	case OpGte:
+		return value >= q.value
+	case OpLt:
+		return value < q.value
+	case OpLte:
+		return value <= q.value
+	case OpLike:
+		return like(value, q.value)
+	case OpIn:
+		for _, v := range q.values {
+			if value == v {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// like matches the value with the SQL LIKE pattern, where % matches any
+// string and _ any character. Only the last % is backtracked, so the pattern
+// is matched without compiling a regexp for every record.
+func like(value, pattern string) bool {
+	v, p := []rune(value), []rune(pattern)
+	vi, pi := 0, 0
+	star, mark := -1, 0
+	for vi < len(v) {
+		switch {
+		case pi < len(p) && (p[pi] == '_' || p[pi] == v[vi]) && p[pi] != '%':
+			vi++
+			pi++
+		case pi < len(p) && p[pi] == '%':
+			star, mark = pi, vi
+			pi++
+		case star >= 0:
+			mark++
+			vi, pi = mark, star+1
+		default:
+			return false
+		}
+	}
+	for pi < len(p) && p[pi] == '%' {
+		pi++
+	}
+	return pi == len(p)
+}
@@ -0,0 +1,185 @@
+package wql
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/lainio/err2/assert"
+)
+
+// libindy query examples from the WQL design and the anoncreds documentation.
+var libindyQueries = []string{
+	`{}`,
+	`{"tagName":"tagValue"}`,
+	`{"tagName":{"$neq":"tagValue"}}`,
+	`{"~tagName":{"$gt":"5"}}`,
+	`{"~tagName":{"$gte":"5"}}`,
+	`{"~tagName":{"$lt":"5"}}`,
+	`{"~tagName":{"$lte":"5"}}`,
+	`{"~tagName":{"$like":"%Value"}}`,
+	`{"tagName":{"$in":["tagValue1","tagValue2"]}}`,
+	`{"$not":{"tagName":"tagValue"}}`,
+	`{"$or":[{"tagName1":"tagValue1"},{"tagName2":"tagValue2"}]}`,
+	`{"$and":[{"tagName1":"tagValue1"},{"tagName2":"tagValue2"}]}`,
+	`{"schema_id":"WgWxqztrNooG92RXvxSTWv:2:schema_name:1.0",` +
+		`"issuer_did":"WgWxqztrNooG92RXvxSTWv",` +
+		`"attr::name::value":"Alex","attr::age::marker":"1"}`,
+	`{"~score":{"$gte":"5"},"$or":[{"attr::name::value":"Alice"},` +
+		`{"$not":{"cred_def_id":{"$in":["id1","id2"]}}}]}`,
+}
+
+func TestParse_roundTrip(t *testing.T) {
+	defer assert.PushTester(t)()
+
+	for _, js := range libindyQueries {
+		q, err := Parse(js)
+		assert.NoError(err, js)
+		assertJSONEqual(q.String(), js)
+
+		data, err := json.Marshal(q)
+		assert.NoError(err)
+		assertJSONEqual(string(data), js)
+	}
+}
+
+func TestBuilder(t *testing.T) {
+	defer assert.PushTester(t)()
+
+	q := And(
+		Eq("schema_id", "WgWxqztrNooG92RXvxSTWv:2:schema_name:1.0"),
+		Or(AttrValue("First Name", "Alice"), AttrMarker("nickname")),
+		Not(In("issuer_did", "did1", "did2")),
+		Gte("~score", "5"),
+		Like("~name", "Al%"),
+		Neq("cred_def_id", "id1"),
+		Lt("~a", "1"), Lte("~b", "2"), Gt("~c", "3"),
+	)
+	assertJSONEqual(q.String(), `{"$and":[
+		{"schema_id":"WgWxqztrNooG92RXvxSTWv:2:schema_name:1.0"},
+		{"$or":[{"attr::firstname::value":"Alice"},{"attr::nickname::marker":"1"}]},
+		{"$not":{"issuer_did":{"$in":["did1","did2"]}}},
+		{"~score":{"$gte":"5"}},
+		{"~name":{"$like":"Al%"}},
+		{"cred_def_id":{"$neq":"id1"}},
+		{"~a":{"$lt":"1"}},{"~b":{"$lte":"2"}},{"~c":{"$gt":"3"}}]}`)
+
+	parsed, err := Parse(q.String())
+	assert.NoError(err)
+	assert.DeepEqual(parsed, q)
+
+	var zero Query
+	assert.That(zero.IsZero())
+	assert.Equal(zero.String(), "{}")
+}
+
+func TestValidate(t *testing.T) {
+	defer assert.PushTester(t)()
+
+	invalid := []Query{
+		Gt("score", "5"),
+		Like("name", "A%"),
+		Eq("", "value"),
+		Eq("$neq", "value"),
+		In("tag"),
+		And(),
+		Or(),
+		Not(Query{}),
+		Eq("attr::name", "value"),
+		Eq("attr::name::raw", "value"),
+		Eq("attr::::value", "value"),
+	}
+	for _, q := range invalid {
+		assert.Error(q.Validate())
+		_, err := q.MarshalJSON()
+		assert.Error(err)
+		assert.Equal(q.String(), "{}")
+	}
+
+	for _, js := range []string{
+		`[]`,
+		`{"$eq":"value"}`,
+		`{"tag":{"$eq":"value"}}`,
+		`{"tag":{"$neq":"a","$in":["b"]}}`,
+		`{"score":{"$gt":"5"}}`,
+		`{"tag":{"$in":"value"}}`,
+		`{"$or":{"tag":"value"}}`,
+		`{"tag":5}`,
+	} {
+		_, err := Parse(js)
+		assert.Error(err, js)
+	}
+}
+
+func assertJSONEqual(got, want string) {
+	var g, w any
+	assert.NoError(json.Unmarshal([]byte(got), &g))
+	assert.NoError(json.Unmarshal([]byte(want), &w))
+	assert.DeepEqual(g, w)
+}
+
+func TestQuery_Match(t *testing.T) {
+	defer assert.PushTester(t)()
+
+	tags := map[string]string{
+		"state":  "ready",
+		"~count": "5",
+		"~name":  "Alice.Smith",
+	}
+	tests := []struct {
+		query string
+		want  bool
+	}{
+		{`{}`, true},
+		{`{"state":"ready"}`, true},
+		{`{"state":"done"}`, false},
+		{`{"missing":{"$neq":"ready"}}`, false},
+		{`{"state":{"$neq":"done"}}`, true},
+		{`{"~count":{"$gt":"4"}}`, true},
+		{`{"~count":{"$gte":"6"}}`, false},
+		{`{"~count":{"$lt":"6"}}`, true},
+		{`{"~count":{"$lte":"4"}}`, false},
+		{`{"~name":{"$like":"Alice.%"}}`, true},
+		{`{"~name":{"$like":"Alice_Smith"}}`, true},
+		{`{"~name":{"$like":"Alice"}}`, false},
+		{`{"state":{"$in":["done","ready"]}}`, true},
+		{`{"$not":{"state":"ready"}}`, false},
+		{`{"$or":[{"state":"done"},{"~count":"5"}]}`, true},
+		{`{"$and":[{"state":"ready"},{"~count":"6"}]}`, false},
+		{`{"state":"ready","~count":"5"}`, true},
+	}
+	for _, tt := range tests {
+		q, err := Parse(tt.query)
+		assert.NoError(err, tt.query)
+		assert.Equal(q.Match(tags), tt.want, tt.query)
+	}
+}
+
+func TestLike(t *testing.T) {
+	defer assert.PushTester(t)()
+
+	tests := []struct {
+		value, pattern string
+		want           bool
+	}{
+		{"", "", true},
+		{"", "%", true},
+		{"", "_", false},
+		{"Alice", "Alice", true},
+		{"Alice", "alice", false},
+		{"Alice", "A%", true},
+		{"Alice", "%e", true},
+		{"Alice", "%lic%", true},
+		{"Alice", "A_i_e", true},
+		{"Alice", "A__e", false},
+		{"Alice", "%%", true},
+		{"abcabd", "%ab_", true},
+		{"abcabc", "%abd%", false},
+		{"a.b*c", "a.b*c", true},
+		{"axb", "a.b", false},
+		{"line\nbreak", "line%", true},
+		{"Älice", "_lice", true},
+	}
+	for _, tt := range tests {
+		assert.Equal(like(tt.value, tt.pattern), tt.want, tt.value, " ", tt.pattern)
+	}
+}